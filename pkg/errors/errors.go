@@ -24,6 +24,12 @@ const (
 	CodeS3QuotaExceeded  ErrorCode = "S3_QUOTA_EXCEEDED"
 	CodeS3Operation      ErrorCode = "S3_OPERATION"
 
+	CodeS3InvalidRange       ErrorCode = "S3_INVALID_RANGE"
+	CodeS3PreconditionFailed ErrorCode = "S3_PRECONDITION_FAILED"
+	CodeS3BucketNotEmpty     ErrorCode = "S3_BUCKET_NOT_EMPTY"
+	CodeS3ObjectArchived     ErrorCode = "S3_OBJECT_ARCHIVED"
+	CodeS3ObjectLocked       ErrorCode = "S3_OBJECT_LOCKED"
+
 	// Configuration errors
 	CodeConfigMissing      ErrorCode = "CONFIG_MISSING"
 	CodeConfigInvalid      ErrorCode = "CONFIG_INVALID"
@@ -35,6 +41,10 @@ const (
 	CodeNetworkUnavailable ErrorCode = "NETWORK_UNAVAILABLE"
 	CodeNetworkUnknown     ErrorCode = "NETWORK_UNKNOWN"
 
+	// Client-side encryption errors
+	CodeEncryptionKeyMissing ErrorCode = "ENCRYPTION_KEY_MISSING"
+	CodeDecryptionFailed     ErrorCode = "DECRYPTION_FAILED"
+
 	// Internal errors
 	CodeInternalError  ErrorCode = "INTERNAL_ERROR"
 	CodeNotImplemented ErrorCode = "NOT_IMPLEMENTED"
@@ -49,6 +59,7 @@ const (
 	CategoryS3         ErrorCategory = "s3"
 	CategoryConfig     ErrorCategory = "config"
 	CategoryNetwork    ErrorCategory = "network"
+	CategoryEncryption ErrorCategory = "encryption"
 	CategoryInternal   ErrorCategory = "internal"
 )
 
@@ -182,6 +193,48 @@ func NewS3AccessDeniedError(operation, resource string) *S3CError {
 		WithSuggestion("Check your AWS permissions and IAM policies")
 }
 
+func NewS3InvalidRangeError(rangeHeader string) *S3CError {
+	return NewS3Error(CodeS3InvalidRange, "Requested range is not satisfiable").
+		WithDetails(map[string]any{
+			"range": rangeHeader,
+		}).
+		WithSuggestion("Request a byte range within the object size")
+}
+
+func NewS3PreconditionFailedError(operation string) *S3CError {
+	return NewS3Error(CodeS3PreconditionFailed, fmt.Sprintf("Precondition failed for %s", operation)).
+		WithDetails(map[string]any{
+			"operation": operation,
+		}).
+		WithSuggestion("The object has changed since it was last read, reload it and try again")
+}
+
+func NewS3BucketNotEmptyError(bucket string) *S3CError {
+	return NewS3Error(CodeS3BucketNotEmpty, fmt.Sprintf("Bucket '%s' is not empty", bucket)).
+		WithDetails(map[string]any{
+			"bucket": bucket,
+		}).
+		WithSuggestion("Empty the bucket first, including all object versions and multipart uploads")
+}
+
+func NewS3ObjectArchivedError(bucket, key string) *S3CError {
+	return NewS3Error(CodeS3ObjectArchived, fmt.Sprintf("Object '%s' in bucket '%s' is archived", key, bucket)).
+		WithDetails(map[string]any{
+			"bucket": bucket,
+			"key":    key,
+		}).
+		WithSuggestion("Request a restore and try again once the restored copy is available")
+}
+
+func NewS3ObjectLockedError(bucket, key string) *S3CError {
+	return NewS3Error(CodeS3ObjectLocked, fmt.Sprintf("Object '%s' in bucket '%s' is protected by object lock", key, bucket)).
+		WithDetails(map[string]any{
+			"bucket": bucket,
+			"key":    key,
+		}).
+		WithSuggestion("Wait for the retention period to end or remove the legal hold. GOVERNANCE retention can be bypassed if you have permission")
+}
+
 func NewS3OperationError(operation string, err error) *S3CError {
 	return NewS3Error(CodeS3Operation, fmt.Sprintf("S3 %s operation failed", operation)).
 		WithWrapped(err).
@@ -222,6 +275,25 @@ func NewNetworkTimeoutError(operation string) *S3CError {
 		WithSuggestion("Check your network connection and try again")
 }
 
+// Encryption error constructors
+func NewEncryptionError(code ErrorCode, message string) *S3CError {
+	return NewS3CError(code, CategoryEncryption, SeverityError, message)
+}
+
+func NewEncryptionKeyMissingError(keySource string) *S3CError {
+	return NewEncryptionError(CodeEncryptionKeyMissing, fmt.Sprintf("Object is client-side encrypted and its %s is not available", keySource)).
+		WithDetails(map[string]any{
+			"keySource": keySource,
+		}).
+		WithSuggestion("Provide the key the object was uploaded with")
+}
+
+func NewDecryptionFailedError(err error) *S3CError {
+	return NewEncryptionError(CodeDecryptionFailed, "Failed to decrypt client-side encrypted object").
+		WithWrapped(err).
+		WithSuggestion("Check that the key matches the one used for the upload, the object may also have been modified")
+}
+
 // Internal error constructors
 func NewInternalError(code ErrorCode, message string) *S3CError {
 	return NewS3CError(code, CategoryInternal, SeverityCritical, message)
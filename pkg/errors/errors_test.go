@@ -220,6 +220,44 @@ func TestNewNotImplementedError(t *testing.T) {
 	}
 }
 
+func TestEncryptionErrors(t *testing.T) {
+	missing := NewEncryptionKeyMissingError("passphrase")
+	if missing.Code != CodeEncryptionKeyMissing || missing.Category != CategoryEncryption {
+		t.Errorf("Unexpected key missing error: %+v", missing)
+	}
+
+	cause := errors.New("cipher: message authentication failed")
+	failed := NewDecryptionFailedError(cause)
+	if failed.Code != CodeDecryptionFailed {
+		t.Errorf("Error code = %v, want %v", failed.Code, CodeDecryptionFailed)
+	}
+	if !errors.Is(failed, cause) {
+		t.Error("NewDecryptionFailedError should wrap the cause")
+	}
+}
+
+func TestNewS3ObjectArchivedError(t *testing.T) {
+	err := NewS3ObjectArchivedError("archive", "2019/backup.tar")
+
+	if err.Code != CodeS3ObjectArchived || err.Category != CategoryS3 {
+		t.Errorf("Unexpected archived error: %+v", err)
+	}
+	if IsRetryable(err) {
+		t.Error("Archived objects stay unreadable until restored, so the error should not be retryable")
+	}
+}
+
+func TestNewS3ObjectLockedError(t *testing.T) {
+	err := NewS3ObjectLockedError("records", "2024/ledger.csv")
+
+	if err.Code != CodeS3ObjectLocked || err.Category != CategoryS3 {
+		t.Errorf("Unexpected locked error: %+v", err)
+	}
+	if IsRetryable(err) {
+		t.Error("Locked objects stay protected until retention ends, so the error should not be retryable")
+	}
+}
+
 func TestJoinErrors(t *testing.T) {
 	err1 := NewInvalidInputError("field1", "value1")
 	err2 := NewMissingFieldError("field2")
@@ -3,6 +3,7 @@ package handler
 import (
 	"archive/zip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -280,11 +281,203 @@ func (h *APIHandler) HandleBucketCreate(w http.ResponseWriter, r *http.Request)
 
 	opLogger.Info("Successfully created S3 bucket", "bucketName", req.Name)
 
+	versioning := service.VersioningUnversioned
+	if req.Versioning {
+		err := h.s3Service.PutBucketVersioning(ctx, service.PutBucketVersioningInput{
+			Bucket: req.Name,
+			Status: service.VersioningEnabled,
+		})
+		if err != nil {
+			opLogger.Error("Failed to enable versioning on new bucket", "error", err, "bucketName", req.Name)
+			var s3cErr *s3cerrors.S3CError
+			if errors.As(err, &s3cErr) {
+				err = s3cErr.WithSuggestion("The bucket was created; enable versioning from the bucket settings")
+			}
+			h.writeStructuredError(w, err, requestID)
+			return
+		}
+		versioning = service.VersioningEnabled
+	}
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message":    "Bucket created successfully",
+			"bucket":     req.Name,
+			"versioning": versioning,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketDelete handles POST /api/buckets/delete
+func (h *APIHandler) HandleBucketDelete(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "delete_bucket", "requestId", requestID)
+
+	opLogger.Debug("Starting bucket deletion operation")
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Parse request body
+	var req DeleteBucketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		opLogger.Error("Failed to decode delete bucket request", "error", err)
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Validate required fields
+	if req.Name == "" {
+		opLogger.Warn("Missing required field: name")
+		s3cErr := s3cerrors.NewMissingFieldError("name")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if req.ConfirmName != req.Name {
+		opLogger.Warn("Bucket deletion not confirmed", "bucketName", req.Name)
+		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Confirmation does not match the bucket name").
+			WithDetails(map[string]any{"field": "confirmName"}).
+			WithSuggestion("Type the bucket name again to confirm the deletion")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if req.BypassGovernanceRetention && !req.Empty {
+		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "The governance bypass only applies when emptying the bucket").
+			WithDetails(map[string]any{"field": "bypassGovernanceRetention"})
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	opLogger.Debug("Deleting S3 bucket", "bucketName", req.Name, "empty", req.Empty, "bypassGovernance", req.BypassGovernanceRetention)
+
+	// Emptying a bucket can take many list and delete round trips
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.DeleteBucket(r.Context(), service.DeleteBucketInput{
+		Bucket:                    req.Name,
+		Empty:                     req.Empty,
+		BypassGovernanceRetention: req.BypassGovernanceRetention,
+	})
+	if err != nil {
+		opLogger.Error("Failed to delete S3 bucket", "error", err, "bucketName", req.Name)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Successfully deleted S3 bucket", "bucketName", req.Name)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message":        "Bucket deleted successfully",
+			"bucket":         req.Name,
+			"deletedObjects": output.DeletedObjects,
+			"abortedUploads": output.AbortedUploads,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketVersioning handles POST /api/buckets/versioning
+func (h *APIHandler) HandleBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "get_bucket_versioning", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketVersioningRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		opLogger.Error("Failed to decode bucket versioning request", "error", err)
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	versioning, err := h.s3Service.GetBucketVersioning(ctx, req.Bucket)
+	if err != nil {
+		opLogger.Error("Failed to get bucket versioning", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      versioning,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketVersioningUpdate handles POST /api/buckets/versioning/update
+func (h *APIHandler) HandleBucketVersioningUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_bucket_versioning", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req service.PutBucketVersioningInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		opLogger.Error("Failed to decode bucket versioning update request", "error", err)
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateVersioningUpdate(req); err != nil {
+		opLogger.Warn("Invalid bucket versioning update", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.s3Service.PutBucketVersioning(ctx, req); err != nil {
+		opLogger.Error("Failed to update bucket versioning", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated bucket versioning", "bucketName", req.Bucket, "status", req.Status, "mfaDelete", req.MFADelete)
+
 	response := APIResponse{
 		Success: true,
 		Data: map[string]any{
-			"message": "Bucket created successfully",
-			"bucket":  req.Name,
+			"message":   "Bucket versioning updated successfully",
+			"bucket":    req.Bucket,
+			"status":    req.Status,
+			"mfaDelete": req.MFADelete,
 		},
 		RequestID: requestID,
 	}
@@ -292,6 +485,29 @@ func (h *APIHandler) HandleBucketCreate(w http.ResponseWriter, r *http.Request)
 	h.writeResponse(w, response)
 }
 
+// validateVersioningUpdate checks a versioning change before it is sent to S3
+func validateVersioningUpdate(req service.PutBucketVersioningInput) error {
+	if req.Bucket == "" {
+		return s3cerrors.NewMissingFieldError("bucket")
+	}
+	if req.Status != service.VersioningEnabled && req.Status != service.VersioningSuspended {
+		// Versioning cannot be turned off again once enabled, only suspended
+		return s3cerrors.NewInvalidInputError("status", "must be Enabled or Suspended").
+			WithSuggestion("Versioning can be suspended but a bucket cannot return to the unversioned state")
+	}
+	switch req.MFADelete {
+	case "":
+	case "Enabled", "Disabled":
+		if req.MFA == "" {
+			return s3cerrors.NewMissingFieldError("mfa").
+				WithSuggestion("Changing MFA delete requires the root account MFA device serial number and current code, separated by a space")
+		}
+	default:
+		return s3cerrors.NewInvalidInputError("mfaDelete", "must be Enabled or Disabled")
+	}
+	return nil
+}
+
 // HandleShutdown handles POST /api/shutdown
 func (h *APIHandler) HandleShutdown(w http.ResponseWriter, r *http.Request) {
 	requestID := generateRequestID()
@@ -440,6 +656,7 @@ func (h *APIHandler) HandleObjectsList(w http.ResponseWriter, r *http.Request) {
 		Delimiter:         req.Delimiter,
 		MaxKeys:           maxKeys,
 		ContinuationToken: req.ContinuationToken,
+		RestoreStatus:     req.RestoreStatus,
 	}
 
 	opLogger.Debug("Starting S3 object listing",
@@ -486,11 +703,28 @@ type ListObjectsRequest struct {
 	Delimiter         string `json:"delimiter,omitempty"`
 	MaxKeys           int32  `json:"maxKeys,omitempty"`
 	ContinuationToken string `json:"continuationToken,omitempty"`
+	RestoreStatus     bool   `json:"restoreStatus,omitempty"` // include the restore state of archived objects
 }
 
 // CreateBucketRequest represents the request for creating a bucket
 type CreateBucketRequest struct {
-	Name string `json:"name"`
+	Name       string `json:"name"`
+	Versioning bool   `json:"versioning,omitempty"` // enable versioning right after creation
+}
+
+// BucketVersioningRequest represents the request for reading bucket versioning
+type BucketVersioningRequest struct {
+	Bucket string `json:"bucket"`
+}
+
+// DeleteBucketRequest represents the request for deleting a bucket
+type DeleteBucketRequest struct {
+	Name        string `json:"name"`
+	ConfirmName string `json:"confirmName"`     // must repeat the bucket name
+	Empty       bool   `json:"empty,omitempty"` // delete all contents first
+
+	// BypassGovernanceRetention must be set explicitly to delete versions under GOVERNANCE retention
+	BypassGovernanceRetention bool `json:"bypassGovernanceRetention,omitempty"`
 }
 
 // CreateFolderRequest represents the request for creating a folder
@@ -502,7 +736,10 @@ type CreateFolderRequest struct {
 // DeleteObjectsRequest represents the request payload for deleting objects
 type DeleteObjectsRequest struct {
 	Bucket string   `json:"bucket"`
-	Keys   []string `json:"keys"`
+	Type   string   `json:"type,omitempty"`   // "files" (default) or "folder"
+	Keys   []string `json:"keys,omitempty"`   // for files
+	Prefix string   `json:"prefix,omitempty"` // for folder
+	DryRun bool     `json:"dryRun,omitempty"` // for folder, only list what would be deleted
 }
 
 // UploadObjectsRequest represents the request for uploading multiple objects
@@ -513,16 +750,28 @@ type UploadObjectsRequest struct {
 
 // UploadFileInfo represents information for a single file upload
 type UploadFileInfo struct {
-	Key  string `json:"key"`  // S3 object key
-	File string `json:"file"` // multipart form field name
+	Key          string                        `json:"key"`                    // S3 object key
+	File         string                        `json:"file"`                   // multipart form field name
+	Tags         map[string]string             `json:"tags,omitempty"`         // object tags set on upload
+	StorageClass string                        `json:"storageClass,omitempty"` // STANDARD when omitted
+	Encryption   *service.ServerSideEncryption `json:"encryption,omitempty"`   // bucket default when omitted
+
+	// ClientEncryption encrypts the file before it leaves the server, on top of any server-side encryption
+	ClientEncryption *service.ClientEncryption `json:"clientEncryption,omitempty"`
 }
 
 // DownloadObjectRequest represents the request for downloading objects
 type DownloadObjectRequest struct {
-	Bucket string   `json:"bucket"`
-	Type   string   `json:"type"`             // "files" or "folder"
-	Keys   []string `json:"keys,omitempty"`   // for files (single or multiple)
-	Prefix string   `json:"prefix,omitempty"` // for folder
+	Bucket    string   `json:"bucket"`
+	Type      string   `json:"type"`                // "files" or "folder"
+	Keys      []string `json:"keys,omitempty"`      // for files (single or multiple)
+	Prefix    string   `json:"prefix,omitempty"`    // for folder
+	VersionID string   `json:"versionId,omitempty"` // for a single file
+
+	// SSECustomerKey is the base64 key SSE-C objects were uploaded with, for files
+	SSECustomerKey string `json:"sseCustomerKey,omitempty"`
+	// Passphrase decrypts objects uploaded with passphrase client-side encryption
+	Passphrase string `json:"passphrase,omitempty"`
 }
 
 // HandleObjectsDelete handles POST /api/objects/delete
@@ -548,6 +797,18 @@ func (h *APIHandler) HandleObjectsDelete(w http.ResponseWriter, r *http.Request)
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
 	}
+
+	switch req.Type {
+	case "", "files":
+	case "folder":
+		h.deleteFolder(w, r, req, requestID)
+		return
+	default:
+		s3cErr := s3cerrors.NewInvalidInputError("type", "must be 'files' or 'folder'")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
 	if len(req.Keys) == 0 {
 		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "At least one key is required")
 		h.writeStructuredError(w, s3cErr, requestID)
@@ -557,127 +818,233 @@ func (h *APIHandler) HandleObjectsDelete(w http.ResponseWriter, r *http.Request)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	var err error
 	if len(req.Keys) == 1 {
 		// Single delete for efficiency
-		err = h.s3Service.DeleteObject(ctx, req.Bucket, req.Keys[0])
-	} else {
-		// Batch delete
-		err = h.s3Service.DeleteObjects(ctx, req.Bucket, req.Keys)
+		if err := h.s3Service.DeleteObject(ctx, req.Bucket, req.Keys[0]); err != nil {
+			// Service should return structured errors
+			h.writeStructuredError(w, err, requestID)
+			return
+		}
+
+		response := APIResponse{
+			Success: true,
+			Data: map[string]any{
+				"message":     "Objects deleted successfully",
+				"bucket":      req.Bucket,
+				"deletedKeys": req.Keys,
+			},
+			RequestID: requestID,
+		}
+		h.writeResponse(w, response)
+		return
 	}
 
+	// Batch delete, where S3 may refuse individual keys
+	output, err := h.s3Service.DeleteObjects(ctx, req.Bucket, req.Keys)
 	if err != nil {
 		// Service should return structured errors
 		h.writeStructuredError(w, err, requestID)
 		return
 	}
 
-	response := APIResponse{
-		Success: true,
-		Data: map[string]any{
-			"message":     "Objects deleted successfully",
-			"bucket":      req.Bucket,
-			"deletedKeys": req.Keys,
-		},
-		RequestID: requestID,
+	failures := make([]BatchItemError, len(output.Failed))
+	for i, failed := range output.Failed {
+		failures[i] = BatchItemError(failed)
 	}
 
-	h.writeResponse(w, response)
+	data := map[string]any{
+		"bucket":      req.Bucket,
+		"deletedKeys": output.Deleted,
+	}
+	if len(failures) == 0 {
+		data["message"] = "Objects deleted successfully"
+	}
+	h.writeBatchResponse(w, requestID, data, len(output.Deleted), failures, "Deleted")
 }
 
-// HandleObjectsUpload handles POST /api/objects/upload
-func (h *APIHandler) HandleObjectsUpload(w http.ResponseWriter, r *http.Request) {
-	requestID := generateRequestID()
-
-	if h.s3Service == nil {
-		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+// deleteFolder deletes everything under a prefix, or only lists it on a dry run
+func (h *APIHandler) deleteFolder(w http.ResponseWriter, r *http.Request, req DeleteObjectsRequest, requestID string) {
+	if req.Prefix == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("prefix")
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
 	}
 
-	// Parse multipart form
-	err := r.ParseMultipartForm(32 << 20) // 32 MB max memory
+	// Large folders take many list and delete round trips
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.DeletePrefix(r.Context(), service.DeletePrefixInput{
+		Bucket: req.Bucket,
+		Prefix: req.Prefix,
+		DryRun: req.DryRun,
+	})
 	if err != nil {
-		s3cErr := s3cerrors.NewInvalidInputError("multipart form", "failed to parse")
-		h.writeStructuredError(w, s3cErr, requestID)
+		h.writeStructuredError(w, err, requestID)
 		return
 	}
 
-	// Get bucket parameter
-	bucket := r.FormValue("bucket")
-	if bucket == "" {
-		s3cErr := s3cerrors.NewMissingFieldError("bucket")
-		h.writeStructuredError(w, s3cErr, requestID)
+	if output.DryRun {
+		response := APIResponse{
+			Success: true,
+			Data: map[string]any{
+				"bucket": req.Bucket,
+				"prefix": output.Prefix,
+				"dryRun": true,
+				"keys":   output.Deleted,
+				"count":  len(output.Deleted),
+			},
+			RequestID: requestID,
+		}
+		h.writeResponse(w, response)
 		return
 	}
 
-	// Get uploads configuration from form
-	uploadsJSON := r.FormValue("uploads")
-	if uploadsJSON == "" {
-		s3cErr := s3cerrors.NewMissingFieldError("uploads")
-		h.writeStructuredError(w, s3cErr, requestID)
-		return
+	failures := make([]BatchItemError, len(output.Failed))
+	for i, failed := range output.Failed {
+		failures[i] = BatchItemError(failed)
 	}
 
-	// Parse uploads configuration
-	var uploads []UploadFileInfo
-	if err := json.Unmarshal([]byte(uploadsJSON), &uploads); err != nil {
-		s3cErr := s3cerrors.NewInvalidInputError("uploads", "invalid JSON format")
+	data := map[string]any{
+		"bucket":      req.Bucket,
+		"prefix":      output.Prefix,
+		"deletedKeys": output.Deleted,
+		"deleted":     len(output.Deleted),
+		"failed":      len(failures),
+	}
+	h.writeBatchResponse(w, requestID, data, len(output.Deleted), failures, "Deleted")
+}
+
+// HandleObjectsUpload handles POST /api/objects/upload
+//
+// The multipart request is read part by part so file contents stream straight
+// through to S3. The "bucket" and "uploads" fields must precede the file parts.
+func (h *APIHandler) HandleObjectsUpload(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
 	}
 
-	if len(uploads) == 0 {
-		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "At least one upload is required")
+	reader, err := r.MultipartReader()
+	if err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("multipart form", "failed to parse")
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
+	// Large uploads outlive the server-wide read/write timeouts, so lift them
+	// for this request and rely on client disconnects for cancellation
+	h.extendDeadlines(w)
+	ctx := r.Context()
 
+	var bucket string
+	var uploads map[string]UploadFileInfo // keyed by multipart form field name
 	var results []map[string]any
 	var errors []string
+	received := make(map[string]bool)
 
-	// Process each file upload
-	for _, upload := range uploads {
-		file, fileHeader, err := r.FormFile(upload.File)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("Failed to get file %s: %v", upload.File, err))
-			continue
+			s3cErr := s3cerrors.NewInvalidInputError("multipart form", "failed to parse")
+			h.writeStructuredError(w, s3cErr, requestID)
+			return
 		}
 
-		// Read file content
-		fileContent, err := io.ReadAll(file)
-		file.Close()
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("Failed to read file %s: %v", upload.File, err))
+		fieldName := part.FormName()
+
+		// Plain form fields carry the upload configuration
+		if part.FileName() == "" {
+			value, err := readFormValue(part)
+			part.Close()
+			if err != nil {
+				s3cErr := s3cerrors.NewInvalidInputError(fieldName, "failed to read form value")
+				h.writeStructuredError(w, s3cErr, requestID)
+				return
+			}
+
+			switch fieldName {
+			case "bucket":
+				bucket = value
+			case "uploads":
+				var list []UploadFileInfo
+				if err := json.Unmarshal([]byte(value), &list); err != nil {
+					s3cErr := s3cerrors.NewInvalidInputError("uploads", "invalid JSON format")
+					h.writeStructuredError(w, s3cErr, requestID)
+					return
+				}
+				uploads = make(map[string]UploadFileInfo, len(list))
+				for i, upload := range list {
+					if err := validateTags(upload.Tags); err != nil {
+						h.writeStructuredError(w, err, requestID)
+						return
+					}
+					if err := validateStorageClass(fmt.Sprintf("uploads[%d].storageClass", i), upload.StorageClass); err != nil {
+						h.writeStructuredError(w, err, requestID)
+						return
+					}
+					if err := validateEncryption(fmt.Sprintf("uploads[%d].encryption", i), upload.Encryption); err != nil {
+						h.writeStructuredError(w, err, requestID)
+						return
+					}
+					if err := validateClientEncryption(fmt.Sprintf("uploads[%d].clientEncryption", i), upload.ClientEncryption); err != nil {
+						h.writeStructuredError(w, err, requestID)
+						return
+					}
+					uploads[upload.File] = upload
+				}
+			}
 			continue
 		}
 
-		// Determine content type
-		contentType := fileHeader.Header.Get("Content-Type")
-		if contentType == "" {
-			ext := filepath.Ext(fileHeader.Filename)
-			contentType = mime.TypeByExtension(ext)
-			if contentType == "" {
-				contentType = "application/octet-stream"
-			}
+		// File parts can only be routed once the configuration is known
+		if bucket == "" {
+			part.Close()
+			s3cErr := s3cerrors.NewMissingFieldError("bucket").
+				WithSuggestion("Send the 'bucket' field before any file parts")
+			h.writeStructuredError(w, s3cErr, requestID)
+			return
 		}
+		if uploads == nil {
+			part.Close()
+			s3cErr := s3cerrors.NewMissingFieldError("uploads").
+				WithSuggestion("Send the 'uploads' field before any file parts")
+			h.writeStructuredError(w, s3cErr, requestID)
+			return
+		}
+
+		upload, ok := uploads[fieldName]
+		if !ok {
+			part.Close()
+			errors = append(errors, fmt.Sprintf("Unexpected file field %s", fieldName))
+			continue
+		}
+		received[fieldName] = true
 
-		// Create upload input
+		filename := part.FileName()
 		uploadInput := service.UploadObjectInput{
 			Bucket:      bucket,
 			Key:         upload.Key,
-			Body:        fileContent,
-			ContentType: contentType,
+			Body:        part,
+			Size:        -1, // multipart parts do not declare their length
+			ContentType: detectContentType(part.Header.Get("Content-Type"), filename),
 			Metadata: map[string]string{
-				"original-filename": fileHeader.Filename,
+				"original-filename": filename,
 			},
+			Tags:             upload.Tags,
+			StorageClass:     upload.StorageClass,
+			Encryption:       upload.Encryption,
+			ClientEncryption: upload.ClientEncryption,
 		}
 
-		// Upload to S3
+		// Upload to S3 while the part is being read from the request
 		output, err := h.s3Service.UploadObject(ctx, uploadInput)
+		part.Close()
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("Failed to upload %s: %v", upload.Key, err))
 			continue
@@ -685,13 +1052,37 @@ func (h *APIHandler) HandleObjectsUpload(w http.ResponseWriter, r *http.Request)
 
 		// Add successful result
 		results = append(results, map[string]any{
-			"key":      output.Key,
-			"etag":     output.ETag,
-			"size":     len(fileContent),
-			"filename": fileHeader.Filename,
+			"key":       output.Key,
+			"etag":      output.ETag,
+			"size":      output.Size,
+			"filename":  filename,
+			"multipart": output.Multipart,
 		})
 	}
 
+	if bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if uploads == nil {
+		s3cErr := s3cerrors.NewMissingFieldError("uploads")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if len(uploads) == 0 {
+		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "At least one upload is required")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Report uploads whose file part never arrived
+	for field := range uploads {
+		if !received[field] {
+			errors = append(errors, fmt.Sprintf("Failed to get file %s: not present in request", field))
+		}
+	}
+
 	// Prepare response
 	responseData := map[string]any{
 		"bucket":   bucket,
@@ -729,6 +1120,15 @@ func (h *APIHandler) HandleObjectsUpload(w http.ResponseWriter, r *http.Request)
 	h.writeResponse(w, response)
 }
 
+// sseCustomerKeyHeader carries the SSE-C key of GET downloads, named after the S3 request header
+const sseCustomerKeyHeader = "X-Amz-Server-Side-Encryption-Customer-Key"
+
+// clientPassphraseHeader carries the client-side encryption passphrase of GET downloads and previews
+const clientPassphraseHeader = "X-S3c-Passphrase"
+
+// sseCustomerKeySize is the decoded length of an SSE-C key, S3 only accepts AES-256 keys
+const sseCustomerKeySize = 32
+
 // HandleObjectsDownload handles POST /api/objects/download
 func (h *APIHandler) HandleObjectsDownload(w http.ResponseWriter, r *http.Request) {
 	requestID := generateRequestID()
@@ -739,9 +1139,19 @@ func (h *APIHandler) HandleObjectsDownload(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Parse request body
 	var req DownloadObjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if r.Method == http.MethodGet {
+		// Media elements and resumable downloads can only issue GET requests,
+		// so a single object may also be addressed with query parameters
+		query := r.URL.Query()
+		req = DownloadObjectRequest{Bucket: query.Get("bucket"), Type: "files", VersionID: query.Get("versionId")}
+		// Keys in the query string would end up in access logs, so SSE-C keys travel in a header
+		req.SSECustomerKey = r.Header.Get(sseCustomerKeyHeader)
+		req.Passphrase = r.Header.Get(clientPassphraseHeader)
+		if key := query.Get("key"); key != "" {
+			req.Keys = []string{key}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
@@ -753,9 +1163,16 @@ func (h *APIHandler) HandleObjectsDownload(w http.ResponseWriter, r *http.Reques
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
 	}
+	if err := validateSSECustomerKey("sseCustomerKey", req.SSECustomerKey); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
+	// Downloads stream for as long as the client keeps reading, so lift the
+	// server-wide deadlines and cancel only when the client goes away
+	h.extendDeadlines(w)
+	ctx := r.Context()
+	object := service.DownloadObjectInput{Bucket: req.Bucket, ClientPassphrase: req.Passphrase}
 
 	switch req.Type {
 	case "files":
@@ -764,10 +1181,18 @@ func (h *APIHandler) HandleObjectsDownload(w http.ResponseWriter, r *http.Reques
 			h.writeStructuredError(w, s3cErr, requestID)
 			return
 		}
+		if req.VersionID != "" && len(req.Keys) != 1 {
+			s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "A version ID can only be given for a single file")
+			h.writeStructuredError(w, s3cErr, requestID)
+			return
+		}
+		object.SSECustomerKey = req.SSECustomerKey
 		if len(req.Keys) == 1 {
-			h.downloadSingleFile(w, ctx, req.Bucket, req.Keys[0], requestID)
+			object.Key = req.Keys[0]
+			object.VersionID = req.VersionID
+			h.downloadSingleFile(w, ctx, object, r.Header, requestID)
 		} else {
-			h.downloadMultipleFiles(w, ctx, req.Bucket, req.Keys)
+			h.downloadMultipleFiles(w, ctx, object, req.Keys)
 		}
 	case "folder":
 		if req.Prefix == "" {
@@ -775,26 +1200,41 @@ func (h *APIHandler) HandleObjectsDownload(w http.ResponseWriter, r *http.Reques
 			h.writeStructuredError(w, s3cErr, requestID)
 			return
 		}
-		h.downloadFolder(w, ctx, req.Bucket, req.Prefix, requestID)
+		h.downloadFolder(w, ctx, object, req.Prefix, requestID)
 	default:
 		s3cErr := s3cerrors.NewInvalidInputError("type", "must be 'files' or 'folder'")
 		h.writeStructuredError(w, s3cErr, requestID)
 	}
 }
 
-// downloadSingleFile downloads a single file directly
-func (h *APIHandler) downloadSingleFile(w http.ResponseWriter, ctx context.Context, bucket, key, requestID string) {
-	downloadInput := service.DownloadObjectInput{
-		Bucket: bucket,
-		Key:    key,
+// downloadSingleFile downloads a single file directly, honouring Range and If-Range headers
+func (h *APIHandler) downloadSingleFile(w http.ResponseWriter, ctx context.Context, object service.DownloadObjectInput, header http.Header, requestID string) {
+	bucket, key := object.Bucket, object.Key
+	downloadInput := object
+
+	// S3 serves a single byte range; multi-range requests get the full object
+	if rangeHeader := header.Get("Range"); isSingleByteRange(rangeHeader) {
+		if applyIfRange(&downloadInput, header.Get("If-Range")) {
+			downloadInput.Range = rangeHeader
+		}
 	}
 
 	output, err := h.s3Service.DownloadObject(ctx, downloadInput)
+	if err != nil && downloadInput.Range != "" && hasErrorCode(err, s3cerrors.CodeS3PreconditionFailed) {
+		// The If-Range validator no longer matches, so the full object is sent instead
+		output, err = h.s3Service.DownloadObject(ctx, object)
+	}
+	if err == nil && output.ContentRange != "" && !lastModifiedMatches(output.LastModified, downloadInput.IfUnmodifiedSince) {
+		// S3 only checks the If-Range date is not older than Last-Modified, it must be equal
+		output.Body.Close()
+		output, err = h.s3Service.DownloadObject(ctx, object)
+	}
 	if err != nil {
 		// Service should return structured errors
 		h.writeStructuredError(w, err, requestID)
 		return
 	}
+	defer output.Body.Close()
 
 	// Extract filename from S3 key (ignore potentially corrupted metadata)
 	filename := filepath.Base(key)
@@ -806,14 +1246,100 @@ func (h *APIHandler) downloadSingleFile(w http.ResponseWriter, ctx context.Conte
 	w.Header().Set("Content-Disposition", contentDisposition)
 	w.Header().Set("Content-Type", output.ContentType)
 	w.Header().Set("Content-Length", strconv.FormatInt(output.ContentLength, 10))
-	w.Header().Set("Last-Modified", output.LastModified)
+	w.Header().Set("Last-Modified", httpTimeFormat(output.LastModified))
+	w.Header().Set("Accept-Ranges", "bytes")
+	if output.ETag != "" {
+		w.Header().Set("ETag", output.ETag)
+	}
 
-	// Write file content
-	w.Write(output.Body)
+	statusCode := http.StatusOK
+	if output.ContentRange != "" {
+		w.Header().Set("Content-Range", output.ContentRange)
+		statusCode = http.StatusPartialContent
+	}
+	w.WriteHeader(statusCode)
+
+	// Stream file content so memory use does not depend on object size
+	if _, err := io.Copy(w, output.Body); err != nil {
+		// Headers are already sent, so the error can only be logged
+		h.logger.Warn("Failed to stream object to client",
+			"error", err,
+			"bucket", bucket,
+			"key", key,
+			"requestId", requestID,
+		)
+	}
 }
 
-// downloadMultipleFiles downloads multiple files as a ZIP
-func (h *APIHandler) downloadMultipleFiles(w http.ResponseWriter, ctx context.Context, bucket string, keys []string) {
+// isSingleByteRange reports whether a Range header requests exactly one byte range
+func isSingleByteRange(rangeHeader string) bool {
+	spec, ok := strings.CutPrefix(rangeHeader, "bytes=")
+	return ok && spec != "" && !strings.Contains(spec, ",")
+}
+
+// applyIfRange converts an If-Range validator into S3 preconditions.
+// It reports false when the validator can never match, meaning the full object must be served.
+// A date validator becomes IfUnmodifiedSince, which S3 also accepts for later dates, so the
+// caller must still compare it with the Last-Modified of the response.
+func applyIfRange(input *service.DownloadObjectInput, ifRange string) bool {
+	switch {
+	case ifRange == "":
+		return true
+	case strings.HasPrefix(ifRange, "W/"):
+		// Weak ETags never satisfy If-Range (RFC 9110 section 13.1.5)
+		return false
+	case strings.HasPrefix(ifRange, `"`):
+		input.IfMatch = ifRange
+		return true
+	}
+
+	modifiedAt, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+	input.IfUnmodifiedSince = modifiedAt
+	return true
+}
+
+// lastModifiedMatches reports whether an If-Range date equals the object's Last-Modified
+// exactly, as RFC 9110 section 13.1.5 requires. A zero date means no date validator was sent.
+func lastModifiedMatches(lastModified string, ifRangeDate time.Time) bool {
+	if ifRangeDate.IsZero() {
+		return true
+	}
+	modifiedAt, err := time.Parse(time.RFC3339, lastModified)
+	return err == nil && modifiedAt.Equal(ifRangeDate)
+}
+
+// httpTimeFormat converts an RFC 3339 timestamp into the HTTP date format
+func httpTimeFormat(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.UTC().Format(http.TimeFormat)
+}
+
+// hasErrorCode reports whether err is an s3c error with the given code
+func hasErrorCode(err error, code s3cerrors.ErrorCode) bool {
+	var s3cErr *s3cerrors.S3CError
+	return errors.As(err, &s3cErr) && s3cErr.Code == code
+}
+
+// zipErrorManifestName is the archive entry listing objects that could not be included
+const zipErrorManifestName = "_s3c_errors.txt"
+
+// folderListPageSize is the number of keys requested per listing page for folder downloads
+const folderListPageSize = 1000
+
+// zipFailure records an object that could not be added to a ZIP download
+type zipFailure struct {
+	Key    string
+	Reason string
+}
+
+// downloadMultipleFiles downloads multiple files as a ZIP, object carries the bucket and keys shared by all of them
+func (h *APIHandler) downloadMultipleFiles(w http.ResponseWriter, ctx context.Context, object service.DownloadObjectInput, keys []string) {
 	// Set response headers for ZIP
 	w.Header().Set("Content-Type", "application/zip")
 	w.Header().Set("Content-Disposition", "attachment; filename=\"files.zip\"")
@@ -821,65 +1347,48 @@ func (h *APIHandler) downloadMultipleFiles(w http.ResponseWriter, ctx context.Co
 	zipWriter := zip.NewWriter(w)
 	defer zipWriter.Close()
 
+	var failures []zipFailure
 	for _, key := range keys {
-		downloadInput := service.DownloadObjectInput{
-			Bucket: bucket,
-			Key:    key,
-		}
-
-		output, err := h.s3Service.DownloadObject(ctx, downloadInput)
-		if err != nil {
-			// Skip failed downloads and continue with others
-			continue
-		}
-
-		// Create file in ZIP
-		fileWriter, err := zipWriter.Create(key)
-		if err != nil {
-			continue
+		object.Key = key
+		if err := h.addObjectToZip(ctx, zipWriter, object); err != nil {
+			failures = append(failures, zipFailure{Key: key, Reason: err.Error()})
 		}
-
-		// Write file content to ZIP
-		fileWriter.Write(output.Body)
 	}
+
+	h.finishZip(zipWriter, object.Bucket, failures)
 }
 
-// downloadFolder downloads all objects in a folder as a ZIP
-func (h *APIHandler) downloadFolder(w http.ResponseWriter, ctx context.Context, bucket, prefix, requestID string) {
-	// List all objects in the folder
+// downloadFolder downloads all objects in a folder as a ZIP, following listing pagination.
+// SSE-C keys are not sent, S3 rejects them for objects encrypted any other way.
+func (h *APIHandler) downloadFolder(w http.ResponseWriter, ctx context.Context, object service.DownloadObjectInput, prefix, requestID string) {
+	bucket := object.Bucket
+	object.SSECustomerKey = ""
+
+	// List the first page before writing headers so an empty folder can still be reported as an error
 	listInput := service.ListObjectsInput{
 		Bucket:    bucket,
 		Prefix:    prefix,
-		MaxKeys:   1000, // Get up to 1000 objects
-		Delimiter: "",   // No delimiter to get all nested objects
+		MaxKeys:   folderListPageSize,
+		Recursive: true, // No delimiter to get all nested objects
 	}
 
-	listOutput, err := h.s3Service.ListObjects(ctx, listInput)
+	page, err := h.s3Service.ListObjects(ctx, listInput)
 	if err != nil {
 		// Service should return structured errors
 		h.writeStructuredError(w, err, requestID)
 		return
 	}
 
-	if len(listOutput.Objects) == 0 {
+	if len(page.Objects) == 0 {
 		s3cErr := s3cerrors.NewS3ObjectNotFoundError(bucket, prefix)
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
 	}
 
-	// Extract keys from objects (exclude folders)
-	// Use Go 1.24 slices.Collect to filter out folders and extract file keys
-	keys := slices.Collect(func(yield func(string) bool) {
-		for _, obj := range listOutput.Objects {
-			if !obj.IsFolder {
-				if !yield(obj.Key) {
-					return
-				}
-			}
-		}
+	hasFiles := slices.ContainsFunc(page.Objects, func(obj service.S3Object) bool {
+		return !obj.IsFolder
 	})
-
-	if len(keys) == 0 {
+	if !hasFiles && !page.IsTruncated {
 		s3cErr := s3cerrors.NewS3ObjectNotFoundError(bucket, prefix).WithSuggestion("Folder contains no files to download")
 		h.writeStructuredError(w, s3cErr, requestID)
 		return
@@ -893,33 +1402,101 @@ func (h *APIHandler) downloadFolder(w http.ResponseWriter, ctx context.Context,
 	w.Header().Set("Content-Type", "application/zip")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.zip\"", folderName))
 
+	// archive/zip switches to ZIP64 records automatically once entries,
+	// sizes or offsets exceed the classic 32-bit limits
 	zipWriter := zip.NewWriter(w)
 	defer zipWriter.Close()
 
-	for _, key := range keys {
-		downloadInput := service.DownloadObjectInput{
-			Bucket: bucket,
-			Key:    key,
-		}
+	var failures []zipFailure
+	for {
+		for _, obj := range page.Objects {
+			if obj.IsFolder {
+				continue
+			}
 
-		output, err := h.s3Service.DownloadObject(ctx, downloadInput)
-		if err != nil {
-			// Skip failed downloads and continue with others
-			continue
+			// Create file in ZIP with folder structure preserved
+			// For prefix "sandbox/" and key "sandbox/subdir/file.txt"
+			// we want zipPath to be "sandbox/subdir/file.txt" (keep full path)
+			object.Key = obj.Key
+			if err := h.addObjectToZip(ctx, zipWriter, object); err != nil {
+				failures = append(failures, zipFailure{Key: obj.Key, Reason: err.Error()})
+			}
 		}
 
-		// Create file in ZIP with folder structure preserved
-		// For prefix "sandbox/" and key "sandbox/subdir/file.txt"
-		// we want zipPath to be "sandbox/subdir/file.txt" (keep full path)
-		zipPath := key
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
 
-		fileWriter, err := zipWriter.Create(zipPath)
+		listInput.ContinuationToken = page.NextContinuationToken
+		page, err = h.s3Service.ListObjects(ctx, listInput)
 		if err != nil {
-			continue
+			// Headers are already sent, so record the truncation in the archive itself
+			failures = append(failures, zipFailure{
+				Key:    prefix,
+				Reason: fmt.Sprintf("listing stopped before all objects were read: %v", err),
+			})
+			break
 		}
+	}
 
-		// Write file content to ZIP
-		fileWriter.Write(output.Body)
+	h.finishZip(zipWriter, bucket, failures)
+}
+
+// addObjectToZip streams a single S3 object into a new ZIP entry named after its key
+func (h *APIHandler) addObjectToZip(ctx context.Context, zipWriter *zip.Writer, object service.DownloadObjectInput) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	output, err := h.s3Service.DownloadObject(ctx, object)
+	if err != nil {
+		return err
+	}
+
+	return writeZipEntry(zipWriter, object.Key, output)
+}
+
+// writeZipEntry streams a downloaded object into a new ZIP entry and closes its body
+func writeZipEntry(zipWriter *zip.Writer, name string, output *service.DownloadObjectOutput) error {
+	defer output.Body.Close()
+
+	header := &zip.FileHeader{
+		Name:   name,
+		Method: zip.Deflate,
+	}
+	if modified, err := time.Parse(time.RFC3339, output.LastModified); err == nil {
+		header.Modified = modified
+	}
+
+	fileWriter, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(fileWriter, output.Body)
+	return err
+}
+
+// finishZip appends the error manifest when some objects could not be archived
+func (h *APIHandler) finishZip(zipWriter *zip.Writer, bucket string, failures []zipFailure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	h.logger.Warn("ZIP download is incomplete",
+		"bucket", bucket,
+		"failedCount", len(failures),
+	)
+
+	manifest, err := zipWriter.Create(zipErrorManifestName)
+	if err != nil {
+		h.logger.Error("Failed to write ZIP error manifest", "error", err, "bucket", bucket)
+		return
+	}
+
+	fmt.Fprintf(manifest, "%d object(s) from bucket %s could not be added to this archive:\n\n", len(failures), bucket)
+	for _, failure := range failures {
+		fmt.Fprintf(manifest, "%s\t%s\n", failure.Key, failure.Reason)
 	}
 }
 
@@ -1065,6 +1642,16 @@ func mapErrorCodeToHTTPStatus(code s3cerrors.ErrorCode) int {
 	case s3cerrors.CodeS3BucketNotFound, s3cerrors.CodeS3ObjectNotFound:
 		return http.StatusNotFound
 
+	// Conflicting resource state -> 409
+	case s3cerrors.CodeS3BucketNotEmpty, s3cerrors.CodeS3ObjectArchived, s3cerrors.CodeS3ObjectLocked:
+		return http.StatusConflict
+
+	// Conditional and range request errors -> 412/416
+	case s3cerrors.CodeS3PreconditionFailed:
+		return http.StatusPreconditionFailed
+	case s3cerrors.CodeS3InvalidRange:
+		return http.StatusRequestedRangeNotSatisfiable
+
 	// Rate limiting -> 429
 	case s3cerrors.CodeS3QuotaExceeded:
 		return http.StatusTooManyRequests
@@ -1073,6 +1660,10 @@ func mapErrorCodeToHTTPStatus(code s3cerrors.ErrorCode) int {
 	case s3cerrors.CodeNetworkTimeout, s3cerrors.CodeNetworkUnavailable, s3cerrors.CodeS3Connection:
 		return http.StatusServiceUnavailable
 
+	// Client-side encryption errors -> 400 Bad Request, the caller has to supply the right key
+	case s3cerrors.CodeEncryptionKeyMissing, s3cerrors.CodeDecryptionFailed:
+		return http.StatusBadRequest
+
 	// Configuration errors -> 400 Bad Request
 	case s3cerrors.CodeConfigMissing, s3cerrors.CodeConfigInvalid, s3cerrors.CodeProfileNotFound:
 		return http.StatusBadRequest
@@ -1087,6 +1678,97 @@ func mapErrorCodeToHTTPStatus(code s3cerrors.ErrorCode) int {
 	}
 }
 
+// BatchItemError describes a single failed item in a batch operation
+type BatchItemError struct {
+	Key     string `json:"key"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// newBatchItemError builds a per-key error entry, keeping the s3c error code when available
+func newBatchItemError(key string, err error) BatchItemError {
+	itemErr := BatchItemError{
+		Key:     key,
+		Code:    string(s3cerrors.CodeInternalError),
+		Message: err.Error(),
+	}
+	var s3cErr *s3cerrors.S3CError
+	if errors.As(err, &s3cErr) {
+		itemErr.Code = string(s3cErr.Code)
+		itemErr.Message = s3cErr.Message
+	}
+	return itemErr
+}
+
+// writeBatchResponse writes the result of a batch operation.
+// Like uploads, a partial failure answers 206 and a total failure answers 500.
+func (h *APIHandler) writeBatchResponse(w http.ResponseWriter, requestID string, data map[string]any, succeeded int, failures []BatchItemError, action string) {
+	total := succeeded + len(failures)
+	data["success"] = succeeded
+	data["total"] = total
+	if len(failures) > 0 {
+		data["errors"] = failures
+		data["failed"] = len(failures)
+	}
+
+	response := APIResponse{
+		Success:   succeeded > 0 || total == 0,
+		Data:      data,
+		RequestID: requestID,
+	}
+
+	statusCode := http.StatusOK
+	if total > 0 && succeeded == 0 {
+		statusCode = http.StatusInternalServerError
+		response.Error = fmt.Sprintf("All %s operations failed", action)
+	} else if len(failures) > 0 {
+		statusCode = http.StatusPartialContent
+		response.Error = fmt.Sprintf("%s %d of %d objects successfully", action, succeeded, total)
+	}
+
+	w.WriteHeader(statusCode)
+	h.writeResponse(w, response)
+}
+
+// maxFormValueSize limits non-file multipart fields read into memory
+const maxFormValueSize = 1 << 20 // 1 MB
+
+// readFormValue reads a non-file multipart field, rejecting oversized values
+func readFormValue(part io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(part, maxFormValueSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxFormValueSize {
+		return "", fmt.Errorf("form value exceeds %d bytes", maxFormValueSize)
+	}
+	return string(data), nil
+}
+
+// detectContentType returns the declared content type, falling back to the filename extension
+func detectContentType(declared, filename string) string {
+	if declared != "" {
+		return declared
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(filename)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// extendDeadlines removes the server read/write deadlines for long-running transfers.
+// Failures are logged rather than returned: the transfer still proceeds, but may be
+// cut off by the server timeouts if the writer chain does not expose the connection.
+func (h *APIHandler) extendDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear read deadline", "error", err)
+	}
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("Failed to clear write deadline", "error", err)
+	}
+}
+
 // generateRequestID generates a simple request ID for tracking
 func generateRequestID() string {
 	return fmt.Sprintf("req_%d", time.Now().UnixNano())
@@ -1216,3 +1898,92 @@ func validateFolderName(name string) error {
 
 	return nil
 }
+
+// validateEncryption checks that a server-side encryption option only sets the fields of its mode
+func validateEncryption(field string, sse *service.ServerSideEncryption) error {
+	if sse == nil {
+		return nil
+	}
+
+	hasKMSFields := sse.KMSKeyID != "" || len(sse.KMSContext) > 0 || sse.BucketKeyEnabled
+	switch sse.Mode {
+	case service.SSEModeS3:
+		if hasKMSFields || sse.CustomerKey != "" {
+			return s3cerrors.NewInvalidInputError(field+".mode", sse.Mode).
+				WithSuggestion("Key IDs, encryption context and bucket keys need SSE-KMS, customer keys need SSE-C")
+		}
+	case service.SSEModeKMS:
+		if sse.CustomerKey != "" {
+			return s3cerrors.NewInvalidInputError(field+".mode", sse.Mode).
+				WithSuggestion("Customer keys need SSE-C")
+		}
+		for key := range sse.KMSContext {
+			if key == "" || strings.HasPrefix(strings.ToLower(key), "aws:") {
+				return s3cerrors.NewInvalidInputError(field+".kmsContext", key).
+					WithSuggestion("Context keys must not be empty, and the aws: prefix is reserved")
+			}
+		}
+	case service.SSEModeC:
+		if hasKMSFields {
+			return s3cerrors.NewInvalidInputError(field+".mode", sse.Mode).
+				WithSuggestion("Key IDs, encryption context and bucket keys need SSE-KMS")
+		}
+		if sse.CustomerKey == "" {
+			return s3cerrors.NewMissingFieldError(field + ".customerKey")
+		}
+		return validateSSECustomerKey(field+".customerKey", sse.CustomerKey)
+	default:
+		return s3cerrors.NewInvalidInputError(field+".mode", sse.Mode).
+			WithSuggestion("Use SSE-S3, SSE-KMS or SSE-C")
+	}
+	return nil
+}
+
+// validateSSECustomerKey checks that an SSE-C key, when given, is a base64 encoded 256-bit key
+func validateSSECustomerKey(field, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != sseCustomerKeySize {
+		// The key is a secret, so only the field is reported back
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidFormat, "SSE-C key must be a base64 encoded 256-bit key").
+			WithDetails(map[string]any{"field": field}).
+			WithSuggestion("Generate a key with: openssl rand -base64 32")
+	}
+	return nil
+}
+
+// minClientPassphraseLength is the shortest passphrase accepted for client-side encryption
+const minClientPassphraseLength = 8
+
+// validateClientEncryption checks that a client-side encryption option names a key source
+// and carries a passphrase only when that source needs one
+func validateClientEncryption(field string, enc *service.ClientEncryption) error {
+	if enc == nil {
+		return nil
+	}
+
+	switch enc.KeySource {
+	case service.ClientKeySourcePassphrase:
+		if enc.Passphrase == "" {
+			return s3cerrors.NewMissingFieldError(field + ".passphrase")
+		}
+		if len([]rune(enc.Passphrase)) < minClientPassphraseLength {
+			// The passphrase is a secret, so only the field is reported back
+			return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Passphrase is too short").
+				WithDetails(map[string]any{"field": field + ".passphrase", "minLength": minClientPassphraseLength}).
+				WithSuggestion(fmt.Sprintf("Use a passphrase of at least %d characters", minClientPassphraseLength))
+		}
+	case service.ClientKeySourceKeyFile:
+		if enc.Passphrase != "" {
+			return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "A passphrase cannot be combined with the key-file key source").
+				WithDetails(map[string]any{"field": field + ".passphrase"})
+		}
+	default:
+		return s3cerrors.NewInvalidInputError(field+".keySource", enc.KeySource).
+			WithSuggestion("Use passphrase or key-file")
+	}
+	return nil
+}
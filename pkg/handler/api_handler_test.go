@@ -1,17 +1,24 @@
 package handler
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
 	"github.com/tenkoh/s3c/pkg/service"
 )
 
@@ -30,15 +37,64 @@ type mockS3Service struct {
 	listBucketsResult []string
 	listBucketsErr    error
 	createBucketErr   error
+	deleteBucketErr   error
+	deleteBucketInput *service.DeleteBucketInput
+	versioningInput   *service.PutBucketVersioningInput
+	versioningErr     error
+	bucketInfoErr     error
+	bucketPolicy      string
+	bucketPolicyErr   error
+	corsRules         []service.CORSRule
+	corsErr           error
+	lifecycleRules    []service.LifecycleRule
+	lifecycleErr      error
+	previewInput      *service.LifecyclePreviewInput
+	headResult        *service.ObjectDetails
+	headErr           error
+	metadataInput     *service.UpdateMetadataInput
+	metadataOutput    *service.UpdateMetadataOutput
+	metadataErr       error
+	versionsResult    *service.ListObjectVersionsOutput
+	versionErr        error
+	presignInput      *service.PresignGetObjectInput
+	presignPutInput   *service.PresignPutObjectInput
+	presignPostInput  *service.PresignPostObjectInput
+	objectTags        map[string]map[string]string // tags per key
+	taggingErr        error
+	tagPrefixInput    *service.TagPrefixInput
+	restoredVersion   string
 	listObjectsResult *service.ListObjectsOutput
+	listObjectsPages  map[string]*service.ListObjectsOutput // keyed by continuation token
 	listObjectsErr    error
 	deleteObjectErr   error
 	deleteObjectsErr  error
 	uploadResult      *service.UploadObjectOutput
 	uploadErr         error
+	uploadedBodies    map[string]string
+	uploadEncryption  map[string]*service.ServerSideEncryption // encryption per uploaded key
+	uploadClientEnc   map[string]*service.ClientEncryption     // client-side encryption per uploaded key
+	uploadStorage     map[string]string                        // storage class per uploaded key
+	storageClassInput *service.ChangeStorageClassInput
+	storageClassOut   *service.ChangeStorageClassOutput
+	restoreInput      *service.RestoreObjectInput
+	restoreOutput     *service.RestoreObjectOutput
+	restoreErr        error
+	retention         *service.ObjectRetention
+	retentionInput    *service.PutObjectRetentionInput
+	legalHoldInput    *service.PutObjectLegalHoldInput
+	objectLockErr     error
 	downloadResult    *service.DownloadObjectOutput
+	downloadContents  map[string]string // per-key bodies, served fresh on every call
+	downloadFunc      func(input service.DownloadObjectInput) (*service.DownloadObjectOutput, error)
 	downloadErr       error
 	createFolderErr   error
+	copyFunc          func(input service.CopyObjectInput) (*service.CopyObjectOutput, error)
+	movedKeys         []string
+	prefixOutput      *service.CopyPrefixOutput
+	prefixErr         error
+	prefixInput       *service.CopyPrefixInput
+	prefixKeys        []string
+	deniedKeys        map[string]bool
 }
 
 func (m *mockS3Service) TestConnection(ctx context.Context) error {
@@ -53,7 +109,139 @@ func (m *mockS3Service) CreateBucket(ctx context.Context, bucketName string) err
 	return m.createBucketErr
 }
 
+func (m *mockS3Service) DeleteBucket(ctx context.Context, input service.DeleteBucketInput) (*service.DeleteBucketOutput, error) {
+	m.deleteBucketInput = &input
+	if m.deleteBucketErr != nil {
+		return nil, m.deleteBucketErr
+	}
+	return &service.DeleteBucketOutput{Bucket: input.Bucket}, nil
+}
+
+func (m *mockS3Service) GetBucketVersioning(ctx context.Context, bucket string) (*service.BucketVersioning, error) {
+	if m.versioningErr != nil {
+		return nil, m.versioningErr
+	}
+	return &service.BucketVersioning{Bucket: bucket, Status: service.VersioningSuspended, MFADelete: "Disabled"}, nil
+}
+
+func (m *mockS3Service) PutBucketVersioning(ctx context.Context, input service.PutBucketVersioningInput) error {
+	m.versioningInput = &input
+	return m.versioningErr
+}
+
+func (m *mockS3Service) GetBucketInfo(ctx context.Context, bucket string) (*service.BucketInfo, error) {
+	if m.bucketInfoErr != nil {
+		return nil, m.bucketInfoErr
+	}
+	return &service.BucketInfo{
+		Bucket:            bucket,
+		Region:            service.BucketInfoSection{Status: service.SectionOK, Value: "eu-west-1"},
+		Versioning:        service.BucketInfoSection{Status: service.SectionOK, Value: &service.BucketVersioning{Bucket: bucket, Status: service.VersioningEnabled}},
+		Encryption:        service.BucketInfoSection{Status: service.SectionNotConfigured},
+		PublicAccessBlock: service.BucketInfoSection{Status: service.SectionNotPermitted},
+		OwnershipControls: service.BucketInfoSection{Status: service.SectionNotSupported},
+	}, nil
+}
+
+func (m *mockS3Service) GetBucketPolicy(ctx context.Context, bucket string) (*service.BucketPolicy, error) {
+	if m.bucketPolicyErr != nil {
+		return nil, m.bucketPolicyErr
+	}
+	return &service.BucketPolicy{Bucket: bucket, Exists: m.bucketPolicy != "", Policy: m.bucketPolicy}, nil
+}
+
+func (m *mockS3Service) PutBucketPolicy(ctx context.Context, bucket, policy string) error {
+	if m.bucketPolicyErr != nil {
+		return m.bucketPolicyErr
+	}
+	m.bucketPolicy = policy
+	return nil
+}
+
+func (m *mockS3Service) DeleteBucketPolicy(ctx context.Context, bucket string) error {
+	if m.bucketPolicyErr != nil {
+		return m.bucketPolicyErr
+	}
+	m.bucketPolicy = ""
+	return nil
+}
+
+func (m *mockS3Service) GetBucketPolicyStatus(ctx context.Context, bucket string) (*service.BucketPolicyStatus, error) {
+	if m.bucketPolicyErr != nil {
+		return nil, m.bucketPolicyErr
+	}
+	return &service.BucketPolicyStatus{Bucket: bucket, IsPublic: strings.Contains(m.bucketPolicy, `"Principal":"*"`)}, nil
+}
+
+func (m *mockS3Service) GetBucketCors(ctx context.Context, bucket string) (*service.BucketCors, error) {
+	if m.corsErr != nil {
+		return nil, m.corsErr
+	}
+	return &service.BucketCors{Bucket: bucket, Rules: m.corsRules}, nil
+}
+
+func (m *mockS3Service) PutBucketCors(ctx context.Context, bucket string, rules []service.CORSRule) error {
+	if m.corsErr != nil {
+		return m.corsErr
+	}
+	m.corsRules = rules
+	return nil
+}
+
+func (m *mockS3Service) DeleteBucketCors(ctx context.Context, bucket string) error {
+	if m.corsErr != nil {
+		return m.corsErr
+	}
+	m.corsRules = nil
+	return nil
+}
+
+func (m *mockS3Service) GetBucketLifecycle(ctx context.Context, bucket string) (*service.BucketLifecycle, error) {
+	if m.lifecycleErr != nil {
+		return nil, m.lifecycleErr
+	}
+	return &service.BucketLifecycle{Bucket: bucket, Rules: m.lifecycleRules}, nil
+}
+
+func (m *mockS3Service) PutBucketLifecycle(ctx context.Context, bucket string, rules []service.LifecycleRule) error {
+	if m.lifecycleErr != nil {
+		return m.lifecycleErr
+	}
+	m.lifecycleRules = rules
+	return nil
+}
+
+func (m *mockS3Service) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	if m.lifecycleErr != nil {
+		return m.lifecycleErr
+	}
+	m.lifecycleRules = nil
+	return nil
+}
+
+func (m *mockS3Service) PreviewLifecycleRule(ctx context.Context, input service.LifecyclePreviewInput) (*service.LifecyclePreviewOutput, error) {
+	m.previewInput = &input
+	if m.lifecycleErr != nil {
+		return nil, m.lifecycleErr
+	}
+	output := &service.LifecyclePreviewOutput{Matched: []service.LifecycleMatch{}}
+	for _, object := range m.listObjectsResult.Objects {
+		output.Checked++
+		if strings.HasPrefix(object.Key, input.Rule.Prefix) {
+			output.Matched = append(output.Matched, service.LifecycleMatch{Key: object.Key, Size: object.Size})
+		}
+	}
+	return output, nil
+}
+
 func (m *mockS3Service) ListObjects(ctx context.Context, input service.ListObjectsInput) (*service.ListObjectsOutput, error) {
+	if m.listObjectsPages != nil {
+		page, ok := m.listObjectsPages[input.ContinuationToken]
+		if !ok {
+			return nil, errors.New("unknown continuation token")
+		}
+		return page, nil
+	}
 	return m.listObjectsResult, m.listObjectsErr
 }
 
@@ -61,15 +249,71 @@ func (m *mockS3Service) DeleteObject(ctx context.Context, bucket, key string) er
 	return m.deleteObjectErr
 }
 
-func (m *mockS3Service) DeleteObjects(ctx context.Context, bucket string, keys []string) error {
-	return m.deleteObjectsErr
+func (m *mockS3Service) DeleteObjects(ctx context.Context, bucket string, keys []string) (*service.DeleteObjectsOutput, error) {
+	if m.deleteObjectsErr != nil {
+		return nil, m.deleteObjectsErr
+	}
+	output := &service.DeleteObjectsOutput{Deleted: []string{}}
+	for _, key := range keys {
+		if m.deniedKeys[key] {
+			output.Failed = append(output.Failed, service.ObjectError{Key: key, Code: "AccessDenied", Message: "Access Denied"})
+			continue
+		}
+		output.Deleted = append(output.Deleted, key)
+	}
+	return output, nil
 }
 
 func (m *mockS3Service) UploadObject(ctx context.Context, input service.UploadObjectInput) (*service.UploadObjectOutput, error) {
+	if m.uploadedBodies != nil && input.Body != nil {
+		body, err := io.ReadAll(input.Body)
+		if err != nil {
+			return nil, err
+		}
+		m.uploadedBodies[input.Key] = string(body)
+	}
+	if input.Tags != nil {
+		if m.objectTags == nil {
+			m.objectTags = make(map[string]map[string]string)
+		}
+		m.objectTags[input.Key] = input.Tags
+	}
+	if input.Encryption != nil {
+		if m.uploadEncryption == nil {
+			m.uploadEncryption = make(map[string]*service.ServerSideEncryption)
+		}
+		m.uploadEncryption[input.Key] = input.Encryption
+	}
+	if input.StorageClass != "" {
+		if m.uploadStorage == nil {
+			m.uploadStorage = make(map[string]string)
+		}
+		m.uploadStorage[input.Key] = input.StorageClass
+	}
+	if input.ClientEncryption != nil {
+		if m.uploadClientEnc == nil {
+			m.uploadClientEnc = make(map[string]*service.ClientEncryption)
+		}
+		m.uploadClientEnc[input.Key] = input.ClientEncryption
+	}
 	return m.uploadResult, m.uploadErr
 }
 
 func (m *mockS3Service) DownloadObject(ctx context.Context, input service.DownloadObjectInput) (*service.DownloadObjectOutput, error) {
+	if m.downloadFunc != nil {
+		return m.downloadFunc(input)
+	}
+	if m.downloadContents != nil {
+		content, ok := m.downloadContents[input.Key]
+		if !ok {
+			return nil, errors.New("NoSuchKey")
+		}
+		return &service.DownloadObjectOutput{
+			Body:          io.NopCloser(strings.NewReader(content)),
+			ContentType:   "application/octet-stream",
+			ContentLength: int64(len(content)),
+		}, nil
+	}
 	return m.downloadResult, m.downloadErr
 }
 
@@ -81,6 +325,225 @@ func (m *mockS3Service) CreateFolder(ctx context.Context, bucket, prefix string)
 	return nil
 }
 
+func (m *mockS3Service) CopyObject(ctx context.Context, input service.CopyObjectInput) (*service.CopyObjectOutput, error) {
+	if m.copyFunc != nil {
+		return m.copyFunc(input)
+	}
+	return &service.CopyObjectOutput{SourceKey: input.SourceKey, DestinationKey: input.DestinationKey}, nil
+}
+
+func (m *mockS3Service) MoveObject(ctx context.Context, input service.CopyObjectInput) (*service.CopyObjectOutput, error) {
+	output, err := m.CopyObject(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	m.movedKeys = append(m.movedKeys, input.SourceKey)
+	return output, nil
+}
+
+func (m *mockS3Service) CopyPrefix(ctx context.Context, input service.CopyPrefixInput) (*service.CopyPrefixOutput, error) {
+	m.prefixInput = &input
+	if m.prefixErr != nil {
+		return nil, m.prefixErr
+	}
+	if m.prefixOutput != nil {
+		return m.prefixOutput, nil
+	}
+	return &service.CopyPrefixOutput{SourcePrefix: input.SourcePrefix, DestinationPrefix: input.DestinationPrefix}, nil
+}
+
+func (m *mockS3Service) MovePrefix(ctx context.Context, input service.CopyPrefixInput) (*service.CopyPrefixOutput, error) {
+	output, err := m.CopyPrefix(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range output.Objects {
+		m.movedKeys = append(m.movedKeys, obj.SourceKey)
+	}
+	return output, nil
+}
+
+func (m *mockS3Service) DeletePrefix(ctx context.Context, input service.DeletePrefixInput) (*service.DeletePrefixOutput, error) {
+	if m.prefixErr != nil {
+		return nil, m.prefixErr
+	}
+	output := &service.DeletePrefixOutput{Prefix: input.Prefix, Deleted: []string{}, DryRun: input.DryRun}
+	for _, key := range m.prefixKeys {
+		if !input.DryRun && m.deniedKeys[key] {
+			output.Failed = append(output.Failed, service.ObjectError{Key: key, Code: "AccessDenied", Message: "Access Denied"})
+			continue
+		}
+		output.Deleted = append(output.Deleted, key)
+	}
+	return output, nil
+}
+
+func (m *mockS3Service) HeadObject(ctx context.Context, input service.HeadObjectInput) (*service.ObjectDetails, error) {
+	if m.headErr != nil {
+		return nil, m.headErr
+	}
+	if m.headResult != nil {
+		return m.headResult, nil
+	}
+	return &service.ObjectDetails{Bucket: input.Bucket, Key: input.Key, Metadata: map[string]string{}}, nil
+}
+
+func (m *mockS3Service) UpdateMetadata(ctx context.Context, input service.UpdateMetadataInput) (*service.UpdateMetadataOutput, error) {
+	m.metadataInput = &input
+	if m.metadataErr != nil {
+		return nil, m.metadataErr
+	}
+	if m.metadataOutput != nil {
+		return m.metadataOutput, nil
+	}
+	return &service.UpdateMetadataOutput{Updated: []string{input.Key}}, nil
+}
+
+func (m *mockS3Service) ListObjectVersions(ctx context.Context, input service.ListObjectVersionsInput) (*service.ListObjectVersionsOutput, error) {
+	if m.versionErr != nil {
+		return nil, m.versionErr
+	}
+	if m.versionsResult != nil {
+		return m.versionsResult, nil
+	}
+	return &service.ListObjectVersionsOutput{Versions: []service.ObjectVersion{}, CommonPrefixes: []string{}}, nil
+}
+
+func (m *mockS3Service) RestoreObjectVersion(ctx context.Context, bucket, key, versionID string) (*service.CopyObjectOutput, error) {
+	if m.versionErr != nil {
+		return nil, m.versionErr
+	}
+	m.restoredVersion = versionID
+	return &service.CopyObjectOutput{SourceKey: key, DestinationKey: key}, nil
+}
+
+func (m *mockS3Service) UndeleteObject(ctx context.Context, bucket, key string) (*service.ObjectVersion, error) {
+	if m.versionErr != nil {
+		return nil, m.versionErr
+	}
+	return &service.ObjectVersion{Key: key, VersionID: "marker-1", IsLatest: true, IsDeleteMarker: true}, nil
+}
+
+func (m *mockS3Service) PresignGetObject(ctx context.Context, input service.PresignGetObjectInput) (*service.PresignedURL, error) {
+	m.presignInput = &input
+	return &service.PresignedURL{
+		URL:       "https://" + input.Bucket + ".s3.amazonaws.com/" + input.Key + "?X-Amz-Signature=test",
+		Method:    http.MethodGet,
+		ExpiresAt: time.Now().Add(input.Expires),
+	}, nil
+}
+
+func (m *mockS3Service) PresignPutObject(ctx context.Context, input service.PresignPutObjectInput) (*service.PresignedURL, error) {
+	m.presignPutInput = &input
+	return &service.PresignedURL{
+		URL:          "https://" + input.Bucket + ".s3.amazonaws.com/" + input.Key + "?X-Amz-Signature=test",
+		Method:       http.MethodPut,
+		ExpiresAt:    time.Now().Add(input.Expires),
+		SignedHeader: map[string][]string{"Content-Type": {input.ContentType}},
+	}, nil
+}
+
+func (m *mockS3Service) PresignPostObject(ctx context.Context, input service.PresignPostObjectInput) (*service.PresignedPost, error) {
+	m.presignPostInput = &input
+	return &service.PresignedPost{
+		URL: "https://" + input.Bucket + ".s3.amazonaws.com/",
+		Fields: map[string]string{
+			"key":             input.KeyPrefix + "${filename}",
+			"policy":          "eyJjb25kaXRpb25zIjpbXX0=",
+			"X-Amz-Signature": "test",
+		},
+		ExpiresAt: time.Now().Add(input.Expires),
+	}, nil
+}
+
+func (m *mockS3Service) GetObjectTagging(ctx context.Context, input service.ObjectTaggingInput) (*service.ObjectTags, error) {
+	if m.taggingErr != nil {
+		return nil, m.taggingErr
+	}
+	return &service.ObjectTags{Bucket: input.Bucket, Key: input.Key, Tags: m.objectTags[input.Key]}, nil
+}
+
+func (m *mockS3Service) PutObjectTagging(ctx context.Context, input service.PutObjectTaggingInput) error {
+	if m.taggingErr != nil {
+		return m.taggingErr
+	}
+	if m.objectTags == nil {
+		m.objectTags = make(map[string]map[string]string)
+	}
+	m.objectTags[input.Key] = input.Tags
+	return nil
+}
+
+func (m *mockS3Service) DeleteObjectTagging(ctx context.Context, input service.ObjectTaggingInput) error {
+	if m.taggingErr != nil {
+		return m.taggingErr
+	}
+	delete(m.objectTags, input.Key)
+	return nil
+}
+
+func (m *mockS3Service) ChangeStorageClass(ctx context.Context, input service.ChangeStorageClassInput) (*service.ChangeStorageClassOutput, error) {
+	m.storageClassInput = &input
+	if m.storageClassOut != nil {
+		return m.storageClassOut, nil
+	}
+	return &service.ChangeStorageClassOutput{Changed: input.Keys}, nil
+}
+
+func (m *mockS3Service) RestoreObject(ctx context.Context, input service.RestoreObjectInput) (*service.RestoreObjectOutput, error) {
+	m.restoreInput = &input
+	if m.restoreErr != nil {
+		return nil, m.restoreErr
+	}
+	if m.restoreOutput != nil {
+		return m.restoreOutput, nil
+	}
+	return &service.RestoreObjectOutput{Requested: []string{input.Key}}, nil
+}
+
+func (m *mockS3Service) GetObjectRetention(ctx context.Context, input service.ObjectLockInput) (*service.ObjectRetention, error) {
+	if m.objectLockErr != nil {
+		return nil, m.objectLockErr
+	}
+	if m.retention != nil {
+		return m.retention, nil
+	}
+	return &service.ObjectRetention{Bucket: input.Bucket, Key: input.Key, VersionID: input.VersionID}, nil
+}
+
+func (m *mockS3Service) PutObjectRetention(ctx context.Context, input service.PutObjectRetentionInput) error {
+	m.retentionInput = &input
+	return m.objectLockErr
+}
+
+func (m *mockS3Service) GetObjectLegalHold(ctx context.Context, input service.ObjectLockInput) (*service.ObjectLegalHold, error) {
+	if m.objectLockErr != nil {
+		return nil, m.objectLockErr
+	}
+	return &service.ObjectLegalHold{Bucket: input.Bucket, Key: input.Key, VersionID: input.VersionID, Status: service.LegalHoldOff}, nil
+}
+
+func (m *mockS3Service) PutObjectLegalHold(ctx context.Context, input service.PutObjectLegalHoldInput) error {
+	m.legalHoldInput = &input
+	return m.objectLockErr
+}
+
+func (m *mockS3Service) TagPrefix(ctx context.Context, input service.TagPrefixInput) (*service.TagPrefixOutput, error) {
+	m.tagPrefixInput = &input
+	if m.taggingErr != nil {
+		return nil, m.taggingErr
+	}
+	output := &service.TagPrefixOutput{Tagged: []string{}}
+	for _, key := range m.prefixKeys {
+		if m.deniedKeys[key] {
+			output.Failed = append(output.Failed, service.ObjectError{Key: key, Code: "S3_ACCESS_DENIED", Message: "Access denied"})
+			continue
+		}
+		output.Tagged = append(output.Tagged, key)
+	}
+	return output, nil
+}
+
 // Integration tests using real ServeMux to test POST-unified API
 func TestAPIHandler_Integration(t *testing.T) {
 	tests := []struct {
@@ -148,7 +611,7 @@ func TestAPIHandler_Integration(t *testing.T) {
 			setupHandler: func(h *APIHandler) {
 				h.s3Service = &mockS3Service{
 					downloadResult: &service.DownloadObjectOutput{
-						Body:          []byte("content"),
+						Body:          io.NopCloser(strings.NewReader("content")),
 						ContentType:   "text/plain",
 						ContentLength: 7,
 					},
@@ -410,7 +873,10 @@ func TestAPIHandler_HandleObjectsDelete(t *testing.T) {
 		requestBody    DeleteObjectsRequest
 		hasS3Service   bool
 		deleteError    error
+		deniedKeys     map[string]bool
 		expectedStatus int
+		expectedKeys   []string
+		expectedFailed []string
 	}{
 		{
 			name: "successful objects deletion",
@@ -448,6 +914,30 @@ func TestAPIHandler_HandleObjectsDelete(t *testing.T) {
 			deleteError:    errors.New("delete failed"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "some keys refused by S3",
+			requestBody: DeleteObjectsRequest{
+				Bucket: "test-bucket",
+				Keys:   []string{"file1.txt", "locked.txt", "file2.txt"},
+			},
+			hasS3Service:   true,
+			deniedKeys:     map[string]bool{"locked.txt": true},
+			expectedStatus: http.StatusPartialContent,
+			expectedKeys:   []string{"file1.txt", "file2.txt"},
+			expectedFailed: []string{"locked.txt"},
+		},
+		{
+			name: "all keys refused by S3",
+			requestBody: DeleteObjectsRequest{
+				Bucket: "test-bucket",
+				Keys:   []string{"locked1.txt", "locked2.txt"},
+			},
+			hasS3Service:   true,
+			deniedKeys:     map[string]bool{"locked1.txt": true, "locked2.txt": true},
+			expectedStatus: http.StatusInternalServerError,
+			expectedKeys:   []string{},
+			expectedFailed: []string{"locked1.txt", "locked2.txt"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -458,6 +948,7 @@ func TestAPIHandler_HandleObjectsDelete(t *testing.T) {
 				handler.s3Service = &mockS3Service{
 					deleteObjectErr:  tt.deleteError,
 					deleteObjectsErr: tt.deleteError,
+					deniedKeys:       tt.deniedKeys,
 				}
 			}
 
@@ -472,6 +963,122 @@ func TestAPIHandler_HandleObjectsDelete(t *testing.T) {
 			if w.Code != tt.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
 			}
+			if tt.expectedFailed == nil {
+				return
+			}
+
+			var response struct {
+				Data struct {
+					DeletedKeys []string         `json:"deletedKeys"`
+					Errors      []BatchItemError `json:"errors"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if !slices.Equal(response.Data.DeletedKeys, tt.expectedKeys) {
+				t.Errorf("Expected deleted keys %v, got %v", tt.expectedKeys, response.Data.DeletedKeys)
+			}
+			var failedKeys []string
+			for _, itemErr := range response.Data.Errors {
+				failedKeys = append(failedKeys, itemErr.Key)
+				if itemErr.Code != "AccessDenied" {
+					t.Errorf("Expected S3 error code AccessDenied for %s, got %s", itemErr.Key, itemErr.Code)
+				}
+			}
+			if !slices.Equal(failedKeys, tt.expectedFailed) {
+				t.Errorf("Expected failed keys %v, got %v", tt.expectedFailed, failedKeys)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsDelete_Folder(t *testing.T) {
+	keys := []string{"reports/", "reports/a.csv", "reports/2024/b.csv"}
+
+	tests := []struct {
+		name           string
+		requestBody    DeleteObjectsRequest
+		denied         map[string]bool
+		expectedStatus int
+		expectedKeys   int
+		expectedFailed int
+	}{
+		{
+			name:           "delete folder recursively",
+			requestBody:    DeleteObjectsRequest{Bucket: "test-bucket", Type: "folder", Prefix: "reports/"},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   3,
+		},
+		{
+			name:           "dry run lists keys",
+			requestBody:    DeleteObjectsRequest{Bucket: "test-bucket", Type: "folder", Prefix: "reports/", DryRun: true},
+			denied:         map[string]bool{"reports/a.csv": true},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   3,
+		},
+		{
+			name:           "partial failure",
+			requestBody:    DeleteObjectsRequest{Bucket: "test-bucket", Type: "folder", Prefix: "reports/"},
+			denied:         map[string]bool{"reports/a.csv": true},
+			expectedStatus: http.StatusPartialContent,
+			expectedKeys:   2,
+			expectedFailed: 1,
+		},
+		{
+			name:           "missing prefix",
+			requestBody:    DeleteObjectsRequest{Bucket: "test-bucket", Type: "folder"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid type",
+			requestBody:    DeleteObjectsRequest{Bucket: "test-bucket", Type: "bucket", Prefix: "reports/"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{prefixKeys: keys, deniedKeys: tt.denied}
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/api/objects/delete", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsDelete(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusBadRequest {
+				return
+			}
+
+			var response APIResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			data := response.Data.(map[string]any)
+
+			keysField, countField := "deletedKeys", "deleted"
+			if tt.requestBody.DryRun {
+				keysField, countField = "keys", "count"
+			}
+			if got, _ := data[keysField].([]any); len(got) != tt.expectedKeys {
+				t.Errorf("Expected %d keys in %s, got %v", tt.expectedKeys, keysField, data[keysField])
+			}
+			if got, _ := data[countField].(float64); int(got) != tt.expectedKeys {
+				t.Errorf("Expected %s %d, got %v", countField, tt.expectedKeys, data[countField])
+			}
+			if !tt.requestBody.DryRun {
+				if got, _ := data["failed"].(float64); int(got) != tt.expectedFailed {
+					t.Errorf("Expected failed %d, got %v", tt.expectedFailed, data["failed"])
+				}
+			}
 		})
 	}
 }
@@ -518,14 +1125,22 @@ func TestAPIHandler_HandleObjectsUpload(t *testing.T) {
 		}
 	})
 
-	t.Run("missing bucket parameter", func(t *testing.T) {
+	t.Run("streams file contents to the uploader", func(t *testing.T) {
 		// Arrange
+		mockService := &mockS3Service{
+			uploadResult:   &service.UploadObjectOutput{Key: "file1.txt", ETag: "etag-123"},
+			uploadedBodies: make(map[string]string),
+		}
+
 		handler := NewAPIHandler(nil, nil, slog.Default())
-		handler.s3Service = &mockS3Service{}
+		handler.s3Service = mockService
 
 		var body bytes.Buffer
 		writer := multipart.NewWriter(&body)
-		writer.WriteField("uploads", `[{"key": "file1.txt", "file": "file1"}]`)
+		writer.WriteField("bucket", "test-bucket")
+		writer.WriteField("uploads", `[{"key": "dir/file1.txt", "file": "file1"}]`)
+		fileWriter, _ := writer.CreateFormFile("file1", "test1.txt")
+		fileWriter.Write([]byte("streamed content"))
 		writer.Close()
 
 		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
@@ -536,36 +1151,270 @@ func TestAPIHandler_HandleObjectsUpload(t *testing.T) {
 		handler.HandleObjectsUpload(w, req)
 
 		// Assert
-		if w.Code != http.StatusBadRequest {
-			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if got := mockService.uploadedBodies["dir/file1.txt"]; got != "streamed content" {
+			t.Errorf("Expected uploaded body %q, got %q", "streamed content", got)
 		}
 	})
-}
 
-func TestAPIHandler_HandleObjectsDownload(t *testing.T) {
-	tests := []struct {
-		name           string
-		requestBody    DownloadObjectRequest
-		hasS3Service   bool
-		downloadResult *service.DownloadObjectOutput
-		downloadError  error
-		expectedStatus int
-	}{
-		{
-			name: "successful single file download",
-			requestBody: DownloadObjectRequest{
-				Bucket: "test-bucket",
-				Type:   "files",
-				Keys:   []string{"file.txt"},
-			},
-			hasS3Service: true,
-			downloadResult: &service.DownloadObjectOutput{
-				Body:          []byte("content"),
-				ContentType:   "text/plain",
-				ContentLength: 7,
-			},
-			expectedStatus: http.StatusOK,
-		},
+	t.Run("encryption is passed to the uploader", func(t *testing.T) {
+		// Arrange
+		mockService := &mockS3Service{uploadResult: &service.UploadObjectOutput{Key: "audit/report.csv"}}
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = mockService
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("bucket", "test-bucket")
+		writer.WriteField("uploads", `[{"key": "audit/report.csv", "file": "file1", "encryption": {"mode": "SSE-KMS", "kmsKeyId": "alias/audit", "kmsContext": {"team": "compliance"}}}]`)
+		fileWriter, _ := writer.CreateFormFile("file1", "report.csv")
+		fileWriter.Write([]byte("a,b"))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		sse := mockService.uploadEncryption["audit/report.csv"]
+		if sse == nil || sse.Mode != service.SSEModeKMS || sse.KMSKeyID != "alias/audit" || sse.KMSContext["team"] != "compliance" {
+			t.Errorf("Expected SSE-KMS with key and context, got %+v", sse)
+		}
+	})
+
+	t.Run("storage class is passed to the uploader", func(t *testing.T) {
+		// Arrange
+		mockService := &mockS3Service{uploadResult: &service.UploadObjectOutput{Key: "archive/2020.tar"}}
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = mockService
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("bucket", "test-bucket")
+		writer.WriteField("uploads", `[{"key": "archive/2020.tar", "file": "file1", "storageClass": "DEEP_ARCHIVE"}]`)
+		fileWriter, _ := writer.CreateFormFile("file1", "2020.tar")
+		fileWriter.Write([]byte("tar"))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if got := mockService.uploadStorage["archive/2020.tar"]; got != "DEEP_ARCHIVE" {
+			t.Errorf("Expected storage class DEEP_ARCHIVE, got %q", got)
+		}
+	})
+
+	t.Run("unknown storage class is rejected before any upload", func(t *testing.T) {
+		// Arrange
+		mockService := &mockS3Service{uploadedBodies: make(map[string]string)}
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = mockService
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("bucket", "test-bucket")
+		writer.WriteField("uploads", `[{"key": "archive/2020.tar", "file": "file1", "storageClass": "COLD"}]`)
+		fileWriter, _ := writer.CreateFormFile("file1", "2020.tar")
+		fileWriter.Write([]byte("tar"))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+		if len(mockService.uploadedBodies) != 0 {
+			t.Errorf("Expected no uploads, got %v", mockService.uploadedBodies)
+		}
+	})
+
+	t.Run("client-side encryption is passed to the uploader", func(t *testing.T) {
+		// Arrange
+		mockService := &mockS3Service{uploadResult: &service.UploadObjectOutput{Key: "hr/salaries.csv"}}
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = mockService
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("bucket", "test-bucket")
+		writer.WriteField("uploads", `[{"key": "hr/salaries.csv", "file": "file1", "clientEncryption": {"keySource": "passphrase", "passphrase": "correct horse"}}]`)
+		fileWriter, _ := writer.CreateFormFile("file1", "salaries.csv")
+		fileWriter.Write([]byte("a,b"))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		enc := mockService.uploadClientEnc["hr/salaries.csv"]
+		if enc == nil || enc.KeySource != service.ClientKeySourcePassphrase || enc.Passphrase != "correct horse" {
+			t.Errorf("Expected passphrase client-side encryption, got %+v", enc)
+		}
+	})
+
+	t.Run("invalid encryption is rejected before any upload", func(t *testing.T) {
+		// Arrange
+		mockService := &mockS3Service{uploadedBodies: make(map[string]string)}
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = mockService
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("bucket", "test-bucket")
+		writer.WriteField("uploads", `[{"key": "secret.bin", "file": "file1", "encryption": {"mode": "SSE-C", "customerKey": "c2hvcnQ="}}]`)
+		fileWriter, _ := writer.CreateFormFile("file1", "secret.bin")
+		fileWriter.Write([]byte("data"))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), "c2hvcnQ=") {
+			t.Errorf("Expected the customer key to be left out of the error, got %s", w.Body.String())
+		}
+		if len(mockService.uploadedBodies) != 0 {
+			t.Errorf("Expected no uploads, got %v", mockService.uploadedBodies)
+		}
+	})
+
+	t.Run("missing file part reports partial success", func(t *testing.T) {
+		// Arrange
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = &mockS3Service{
+			uploadResult: &service.UploadObjectOutput{Key: "file1.txt"},
+		}
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("bucket", "test-bucket")
+		writer.WriteField("uploads", `[{"key": "file1.txt", "file": "file1"}, {"key": "file2.txt", "file": "file2"}]`)
+		fileWriter, _ := writer.CreateFormFile("file1", "test1.txt")
+		fileWriter.Write([]byte("content1"))
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusPartialContent {
+			t.Errorf("Expected status %d, got %d", http.StatusPartialContent, w.Code)
+		}
+	})
+
+	t.Run("file part before configuration fields", func(t *testing.T) {
+		// Arrange
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = &mockS3Service{}
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		fileWriter, _ := writer.CreateFormFile("file1", "test1.txt")
+		fileWriter.Write([]byte("content1"))
+		writer.WriteField("bucket", "test-bucket")
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("missing bucket parameter", func(t *testing.T) {
+		// Arrange
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = &mockS3Service{}
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("uploads", `[{"key": "file1.txt", "file": "file1"}]`)
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsUpload(w, req)
+
+		// Assert
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
+func TestAPIHandler_HandleObjectsDownload(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    DownloadObjectRequest
+		hasS3Service   bool
+		downloadResult *service.DownloadObjectOutput
+		downloadError  error
+		expectedStatus int
+	}{
+		{
+			name: "successful single file download",
+			requestBody: DownloadObjectRequest{
+				Bucket: "test-bucket",
+				Type:   "files",
+				Keys:   []string{"file.txt"},
+			},
+			hasS3Service: true,
+			downloadResult: &service.DownloadObjectOutput{
+				Body:          io.NopCloser(strings.NewReader("content")),
+				ContentType:   "text/plain",
+				ContentLength: 7,
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name: "missing bucket",
 			requestBody: DownloadObjectRequest{
@@ -585,14 +1434,425 @@ func TestAPIHandler_HandleObjectsDownload(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: "missing keys for files download",
-			requestBody: DownloadObjectRequest{
-				Bucket: "test-bucket",
-				Type:   "files",
-				Keys:   []string{},
-			},
-			hasS3Service:   true,
-			expectedStatus: http.StatusBadRequest,
+			name: "missing keys for files download",
+			requestBody: DownloadObjectRequest{
+				Bucket: "test-bucket",
+				Type:   "files",
+				Keys:   []string{},
+			},
+			hasS3Service:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			if tt.hasS3Service {
+				handler.s3Service = &mockS3Service{
+					downloadResult: tt.downloadResult,
+					downloadErr:    tt.downloadError,
+				}
+			}
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsDownload(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+				t.Logf("Response: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsDownload_Streaming(t *testing.T) {
+	t.Run("single file body is copied to the response", func(t *testing.T) {
+		// Arrange
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = &mockS3Service{
+			downloadContents: map[string]string{"dir/file.txt": "streamed content"},
+		}
+
+		body, _ := json.Marshal(DownloadObjectRequest{Bucket: "test-bucket", Type: "files", Keys: []string{"dir/file.txt"}})
+		req := httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsDownload(w, req)
+
+		// Assert
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if got := w.Body.String(); got != "streamed content" {
+			t.Errorf("Expected body %q, got %q", "streamed content", got)
+		}
+	})
+
+	t.Run("multiple files are streamed into a ZIP", func(t *testing.T) {
+		// Arrange
+		contents := map[string]string{"a.txt": "alpha", "b/c.txt": "charlie"}
+		handler := NewAPIHandler(nil, nil, slog.Default())
+		handler.s3Service = &mockS3Service{downloadContents: contents}
+
+		body, _ := json.Marshal(DownloadObjectRequest{Bucket: "test-bucket", Type: "files", Keys: []string{"a.txt", "b/c.txt"}})
+		req := httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		// Act
+		handler.HandleObjectsDownload(w, req)
+
+		// Assert
+		zipReader, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		if err != nil {
+			t.Fatalf("Failed to read ZIP response: %v", err)
+		}
+		for _, file := range zipReader.File {
+			rc, err := file.Open()
+			if err != nil {
+				t.Fatalf("Failed to open ZIP entry %s: %v", file.Name, err)
+			}
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			if string(data) != contents[file.Name] {
+				t.Errorf("Entry %s: expected %q, got %q", file.Name, contents[file.Name], string(data))
+			}
+		}
+		if len(zipReader.File) != len(contents) {
+			t.Errorf("Expected %d ZIP entries, got %d", len(contents), len(zipReader.File))
+		}
+	})
+}
+
+func TestAPIHandler_HandleObjectsDownload_SSECustomerKey(t *testing.T) {
+	customerKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+
+	tests := []struct {
+		name           string
+		request        func() *http.Request
+		expectedStatus int
+		expectedKey    string
+	}{
+		{
+			name: "POST body key",
+			request: func() *http.Request {
+				body, _ := json.Marshal(DownloadObjectRequest{Bucket: "test-bucket", Type: "files", Keys: []string{"secret.bin"}, SSECustomerKey: customerKey})
+				return httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+			},
+			expectedStatus: http.StatusOK,
+			expectedKey:    customerKey,
+		},
+		{
+			name: "GET header key",
+			request: func() *http.Request {
+				req := httptest.NewRequest("GET", "/api/objects/download?bucket=test-bucket&key=secret.bin", nil)
+				req.Header.Set(sseCustomerKeyHeader, customerKey)
+				return req
+			},
+			expectedStatus: http.StatusOK,
+			expectedKey:    customerKey,
+		},
+		{
+			name: "key of the wrong length",
+			request: func() *http.Request {
+				body, _ := json.Marshal(DownloadObjectRequest{Bucket: "test-bucket", Type: "files", Keys: []string{"secret.bin"}, SSECustomerKey: "c2hvcnQ="})
+				return httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var received string
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{downloadFunc: func(input service.DownloadObjectInput) (*service.DownloadObjectOutput, error) {
+				received = input.SSECustomerKey
+				return &service.DownloadObjectOutput{Body: io.NopCloser(strings.NewReader("secret"))}, nil
+			}}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsDownload(w, tt.request())
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if received != tt.expectedKey {
+				t.Errorf("Expected key %q to reach the service, got %q", tt.expectedKey, received)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsDownload_Passphrase(t *testing.T) {
+	tests := []struct {
+		name               string
+		request            func() *http.Request
+		downloadErr        error
+		expectedStatus     int
+		expectedPassphrase string
+	}{
+		{
+			name: "POST body passphrase",
+			request: func() *http.Request {
+				body, _ := json.Marshal(DownloadObjectRequest{Bucket: "test-bucket", Type: "files", Keys: []string{"a.csv", "b.csv"}, Passphrase: "correct horse"})
+				return httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+			},
+			expectedStatus:     http.StatusOK,
+			expectedPassphrase: "correct horse",
+		},
+		{
+			name: "GET header passphrase",
+			request: func() *http.Request {
+				req := httptest.NewRequest("GET", "/api/objects/download?bucket=test-bucket&key=a.csv", nil)
+				req.Header.Set(clientPassphraseHeader, "correct horse")
+				return req
+			},
+			expectedStatus:     http.StatusOK,
+			expectedPassphrase: "correct horse",
+		},
+		{
+			name: "missing passphrase",
+			request: func() *http.Request {
+				return httptest.NewRequest("GET", "/api/objects/download?bucket=test-bucket&key=a.csv", nil)
+			},
+			downloadErr:    s3cerrors.NewEncryptionKeyMissingError(service.ClientKeySourcePassphrase),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "wrong passphrase",
+			request: func() *http.Request {
+				req := httptest.NewRequest("GET", "/api/objects/download?bucket=test-bucket&key=a.csv", nil)
+				req.Header.Set(clientPassphraseHeader, "battery staple")
+				return req
+			},
+			downloadErr:        s3cerrors.NewDecryptionFailedError(errors.New("message authentication failed")),
+			expectedStatus:     http.StatusBadRequest,
+			expectedPassphrase: "battery staple",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var received []string
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{downloadFunc: func(input service.DownloadObjectInput) (*service.DownloadObjectOutput, error) {
+				received = append(received, input.ClientPassphrase)
+				if tt.downloadErr != nil {
+					return nil, tt.downloadErr
+				}
+				return &service.DownloadObjectOutput{Body: io.NopCloser(strings.NewReader("a,b"))}, nil
+			}}
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsDownload(w, tt.request())
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			for _, passphrase := range received {
+				if passphrase != tt.expectedPassphrase {
+					t.Errorf("Expected passphrase %q to reach the service, got %q", tt.expectedPassphrase, passphrase)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsDownload_FolderPagination(t *testing.T) {
+	// Arrange: two listing pages, one object that fails to download
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = &mockS3Service{
+		listObjectsPages: map[string]*service.ListObjectsOutput{
+			"": {
+				Objects: []service.S3Object{
+					{Key: "reports/", IsFolder: true},
+					{Key: "reports/a.txt"},
+					{Key: "reports/missing.txt"},
+				},
+				IsTruncated:           true,
+				NextContinuationToken: "page-2",
+			},
+			"page-2": {
+				Objects: []service.S3Object{
+					{Key: "reports/nested/b.txt"},
+				},
+			},
+		},
+		downloadContents: map[string]string{
+			"reports/a.txt":        "alpha",
+			"reports/nested/b.txt": "bravo",
+		},
+	}
+
+	body, _ := json.Marshal(DownloadObjectRequest{Bucket: "test-bucket", Type: "folder", Prefix: "reports/"})
+	req := httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.HandleObjectsDownload(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read ZIP response: %v", err)
+	}
+
+	entries := make(map[string]string)
+	for _, file := range zipReader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open ZIP entry %s: %v", file.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[file.Name] = string(data)
+	}
+
+	if entries["reports/a.txt"] != "alpha" || entries["reports/nested/b.txt"] != "bravo" {
+		t.Errorf("Expected objects from both pages, got entries %v", entries)
+	}
+	manifest, ok := entries[zipErrorManifestName]
+	if !ok {
+		t.Fatalf("Expected %s in archive", zipErrorManifestName)
+	}
+	if !strings.Contains(manifest, "reports/missing.txt") {
+		t.Errorf("Expected manifest to list failed key, got %q", manifest)
+	}
+}
+
+func TestAPIHandler_HandleObjectsDownload_Range(t *testing.T) {
+	const content = "0123456789"
+	const etag = `"v1"`
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// rangeDownload emulates S3 GetObject range, If-Match and If-Unmodified-Since handling for a single object
+	rangeDownload := func(input service.DownloadObjectInput) (*service.DownloadObjectOutput, error) {
+		if input.IfMatch != "" && input.IfMatch != etag {
+			return nil, s3cerrors.NewS3PreconditionFailedError("download object")
+		}
+		if !input.IfUnmodifiedSince.IsZero() && lastModified.After(input.IfUnmodifiedSince) {
+			return nil, s3cerrors.NewS3PreconditionFailedError("download object")
+		}
+		output := &service.DownloadObjectOutput{ETag: etag, ContentType: "text/plain", LastModified: lastModified.Format(time.RFC3339)}
+		body := content
+		if input.Range != "" {
+			var start, end int
+			if _, err := fmt.Sscanf(input.Range, "bytes=%d-%d", &start, &end); err != nil || start >= len(content) {
+				return nil, s3cerrors.NewS3InvalidRangeError(input.Range)
+			}
+			end = min(end, len(content)-1)
+			body = content[start : end+1]
+			output.ContentRange = fmt.Sprintf("bytes %d-%d/%d", start, end, len(content))
+		}
+		output.Body = io.NopCloser(strings.NewReader(body))
+		output.ContentLength = int64(len(body))
+		return output, nil
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		headers        map[string]string
+		expectedStatus int
+		expectedBody   string
+		expectedRange  string
+	}{
+		{
+			name:           "no range returns full object",
+			method:         "POST",
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "single range returns partial content",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=2-5"},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "2345",
+			expectedRange:  "bytes 2-5/10",
+		},
+		{
+			name:           "GET with query parameters supports range",
+			method:         "GET",
+			headers:        map[string]string{"Range": "bytes=8-20"},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "89",
+			expectedRange:  "bytes 8-9/10",
+		},
+		{
+			name:           "matching If-Range keeps the range",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=0-1", "If-Range": etag},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "01",
+			expectedRange:  "bytes 0-1/10",
+		},
+		{
+			name:           "stale If-Range returns full object",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=0-1", "If-Range": `"v0"`},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "If-Range with the Last-Modified date keeps the range",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=0-1", "If-Range": lastModified.Format(http.TimeFormat)},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "01",
+			expectedRange:  "bytes 0-1/10",
+		},
+		{
+			name:           "If-Range with a later date returns full object",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=0-1", "If-Range": lastModified.Add(24 * time.Hour).Format(http.TimeFormat)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "If-Range with an earlier date returns full object",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=0-1", "If-Range": lastModified.Add(-time.Hour).Format(http.TimeFormat)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "weak If-Range returns full object",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=0-1", "If-Range": `W/"v1"`},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "multiple ranges return full object",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=0-1,4-5"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   content,
+		},
+		{
+			name:           "unsatisfiable range",
+			method:         "POST",
+			headers:        map[string]string{"Range": "bytes=50-60"},
+			expectedStatus: http.StatusRequestedRangeNotSatisfiable,
 		},
 	}
 
@@ -600,15 +1860,18 @@ func TestAPIHandler_HandleObjectsDownload(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
 			handler := NewAPIHandler(nil, nil, slog.Default())
-			if tt.hasS3Service {
-				handler.s3Service = &mockS3Service{
-					downloadResult: tt.downloadResult,
-					downloadErr:    tt.downloadError,
-				}
-			}
+			handler.s3Service = &mockS3Service{downloadFunc: rangeDownload}
 
-			body, _ := json.Marshal(tt.requestBody)
-			req := httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+			var req *http.Request
+			if tt.method == "GET" {
+				req = httptest.NewRequest("GET", "/api/objects/download?bucket=test-bucket&key=file.txt", nil)
+			} else {
+				body, _ := json.Marshal(DownloadObjectRequest{Bucket: "test-bucket", Type: "files", Keys: []string{"file.txt"}})
+				req = httptest.NewRequest("POST", "/api/objects/download", bytes.NewBuffer(body))
+			}
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
 			w := httptest.NewRecorder()
 
 			// Act
@@ -616,8 +1879,22 @@ func TestAPIHandler_HandleObjectsDownload(t *testing.T) {
 
 			// Assert
 			if w.Code != tt.expectedStatus {
-				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
-				t.Logf("Response: %s", w.Body.String())
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus >= 400 {
+				return
+			}
+			if got := w.Body.String(); got != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, got)
+			}
+			if got := w.Header().Get("Content-Range"); got != tt.expectedRange {
+				t.Errorf("Expected Content-Range %q, got %q", tt.expectedRange, got)
+			}
+			if got := w.Header().Get("Accept-Ranges"); got != "bytes" {
+				t.Errorf("Expected Accept-Ranges bytes, got %q", got)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("Expected ETag %s, got %q", etag, got)
 			}
 		})
 	}
@@ -741,11 +2018,13 @@ func TestValidateBucketName(t *testing.T) {
 
 func TestAPIHandler_HandleBucketCreate(t *testing.T) {
 	tests := []struct {
-		name           string
-		requestBody    CreateBucketRequest
-		hasS3Service   bool
-		createError    error
-		expectedStatus int
+		name             string
+		requestBody      CreateBucketRequest
+		hasS3Service     bool
+		createError      error
+		versioningError  error
+		expectedStatus   int
+		expectVersioning bool
 	}{
 		{
 			name: "successful bucket creation",
@@ -755,6 +2034,27 @@ func TestAPIHandler_HandleBucketCreate(t *testing.T) {
 			hasS3Service:   true,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name: "create with versioning enabled",
+			requestBody: CreateBucketRequest{
+				Name:       "test-bucket-123",
+				Versioning: true,
+			},
+			hasS3Service:     true,
+			expectedStatus:   http.StatusOK,
+			expectVersioning: true,
+		},
+		{
+			name: "versioning fails after creation",
+			requestBody: CreateBucketRequest{
+				Name:       "test-bucket-123",
+				Versioning: true,
+			},
+			hasS3Service:     true,
+			versioningError:  s3cerrors.NewS3AccessDeniedError("put bucket versioning", "test-bucket-123"),
+			expectedStatus:   http.StatusForbidden,
+			expectVersioning: true,
+		},
 		{
 			name: "missing bucket name",
 			requestBody: CreateBucketRequest{
@@ -794,11 +2094,12 @@ func TestAPIHandler_HandleBucketCreate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Arrange
 			handler := NewAPIHandler(nil, nil, slog.Default())
-
+			mockService := &mockS3Service{
+				createBucketErr: tt.createError,
+				versioningErr:   tt.versioningError,
+			}
 			if tt.hasS3Service {
-				handler.s3Service = &mockS3Service{
-					createBucketErr: tt.createError,
-				}
+				handler.s3Service = mockService
 			}
 
 			bodyBytes, _ := json.Marshal(tt.requestBody)
@@ -814,6 +2115,9 @@ func TestAPIHandler_HandleBucketCreate(t *testing.T) {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
 				t.Logf("Response body: %s", w.Body.String())
 			}
+			if got := mockService.versioningInput != nil; got != tt.expectVersioning {
+				t.Errorf("Expected versioning enabled=%v, got %+v", tt.expectVersioning, mockService.versioningInput)
+			}
 
 			// For successful creation, verify response structure
 			if tt.expectedStatus == http.StatusOK {
@@ -834,3 +2138,368 @@ func TestAPIHandler_HandleBucketCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIHandler_HandleBucketDelete(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    DeleteBucketRequest
+		hasS3Service   bool
+		deleteError    error
+		expectedStatus int
+		expectedEmpty  bool
+	}{
+		{
+			name:           "successful bucket deletion",
+			requestBody:    DeleteBucketRequest{Name: "scratch-bucket", ConfirmName: "scratch-bucket"},
+			hasS3Service:   true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "empty bucket first",
+			requestBody:    DeleteBucketRequest{Name: "scratch-bucket", ConfirmName: "scratch-bucket", Empty: true},
+			hasS3Service:   true,
+			expectedStatus: http.StatusOK,
+			expectedEmpty:  true,
+		},
+		{
+			name:           "confirmation mismatch",
+			requestBody:    DeleteBucketRequest{Name: "scratch-bucket", ConfirmName: "scratch"},
+			hasS3Service:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing name",
+			requestBody:    DeleteBucketRequest{},
+			hasS3Service:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bucket not empty",
+			requestBody:    DeleteBucketRequest{Name: "scratch-bucket", ConfirmName: "scratch-bucket"},
+			hasS3Service:   true,
+			deleteError:    s3cerrors.NewS3BucketNotEmptyError("scratch-bucket"),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "no S3 service configured",
+			requestBody:    DeleteBucketRequest{Name: "scratch-bucket", ConfirmName: "scratch-bucket"},
+			hasS3Service:   false,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			mockService := &mockS3Service{deleteBucketErr: tt.deleteError}
+			if tt.hasS3Service {
+				handler.s3Service = mockService
+			}
+
+			bodyBytes, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/api/buckets/delete", bytes.NewBuffer(bodyBytes))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketDelete(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK {
+				if mockService.deleteBucketInput == nil || mockService.deleteBucketInput.Empty != tt.expectedEmpty {
+					t.Errorf("Expected delete with empty=%v, got %+v", tt.expectedEmpty, mockService.deleteBucketInput)
+				}
+			} else if tt.deleteError == nil && mockService.deleteBucketInput != nil {
+				t.Error("Expected bucket not to be deleted")
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketVersioning(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		versioningErr  error
+		expectedStatus int
+	}{
+		{
+			name:           "get versioning state",
+			body:           `{"bucket":"test-bucket"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bucket not found",
+			body:           `{"bucket":"missing-bucket"}`,
+			versioningErr:  s3cerrors.NewS3BucketNotFoundError("missing-bucket"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{versioningErr: tt.versioningErr}
+
+			req := httptest.NewRequest("POST", "/api/buckets/versioning", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketVersioning(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data service.BucketVersioning `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Data.Status != service.VersioningSuspended || response.Data.MFADelete != "Disabled" {
+				t.Errorf("Unexpected versioning state: %+v", response.Data)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketVersioningUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "enable versioning",
+			body:           `{"bucket":"test-bucket","status":"Enabled"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "suspend versioning and enable MFA delete",
+			body:           `{"bucket":"test-bucket","status":"Suspended","mfaDelete":"Enabled","mfa":"arn:aws:iam::123456789012:mfa/root 123456"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "cannot return to unversioned",
+			body:           `{"bucket":"test-bucket","status":"Unversioned"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "MFA delete without MFA code",
+			body:           `{"bucket":"test-bucket","status":"Enabled","mfaDelete":"Enabled"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid MFA delete value",
+			body:           `{"bucket":"test-bucket","status":"Enabled","mfaDelete":"On","mfa":"serial 123456"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"status":"Enabled"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			req := httptest.NewRequest("POST", "/api/buckets/versioning/update", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketVersioningUpdate(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if updated := mockService.versioningInput != nil; updated != (tt.expectedStatus == http.StatusOK) {
+				t.Errorf("Expected versioning update only on success, got %+v", mockService.versioningInput)
+			}
+		})
+	}
+}
+
+func TestValidateEncryption(t *testing.T) {
+	customerKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+
+	tests := []struct {
+		name          string
+		sse           *service.ServerSideEncryption
+		expectedCode  s3cerrors.ErrorCode
+		expectedField string
+	}{
+		{
+			name: "no encryption",
+		},
+		{
+			name: "SSE-S3",
+			sse:  &service.ServerSideEncryption{Mode: service.SSEModeS3},
+		},
+		{
+			name: "SSE-KMS with key and context",
+			sse:  &service.ServerSideEncryption{Mode: service.SSEModeKMS, KMSKeyID: "alias/audit", KMSContext: map[string]string{"team": "compliance"}, BucketKeyEnabled: true},
+		},
+		{
+			name: "SSE-C",
+			sse:  &service.ServerSideEncryption{Mode: service.SSEModeC, CustomerKey: customerKey},
+		},
+		{
+			name:          "unknown mode",
+			sse:           &service.ServerSideEncryption{Mode: "aws:kms"},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "encryption.mode",
+		},
+		{
+			name:          "SSE-S3 with KMS key",
+			sse:           &service.ServerSideEncryption{Mode: service.SSEModeS3, KMSKeyID: "alias/audit"},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "encryption.mode",
+		},
+		{
+			name:          "SSE-KMS with reserved context key",
+			sse:           &service.ServerSideEncryption{Mode: service.SSEModeKMS, KMSContext: map[string]string{"aws:s3:arn": "x"}},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "encryption.kmsContext",
+		},
+		{
+			name:          "SSE-C without key",
+			sse:           &service.ServerSideEncryption{Mode: service.SSEModeC},
+			expectedCode:  s3cerrors.CodeMissingField,
+			expectedField: "encryption.customerKey",
+		},
+		{
+			name:          "SSE-C with invalid base64",
+			sse:           &service.ServerSideEncryption{Mode: service.SSEModeC, CustomerKey: "not base64!"},
+			expectedCode:  s3cerrors.CodeInvalidFormat,
+			expectedField: "encryption.customerKey",
+		},
+		{
+			name:          "SSE-C with KMS key",
+			sse:           &service.ServerSideEncryption{Mode: service.SSEModeC, CustomerKey: customerKey, KMSKeyID: "alias/audit"},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "encryption.mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := validateEncryption("encryption", tt.sse)
+
+			// Assert
+			if tt.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("Expected valid encryption, got %v", err)
+				}
+				return
+			}
+
+			var s3cErr *s3cerrors.S3CError
+			if !errors.As(err, &s3cErr) {
+				t.Fatalf("Expected S3CError, got %v", err)
+			}
+			if s3cErr.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, s3cErr.Code)
+			}
+			details := s3cErr.Details.(map[string]any)
+			if details["field"] != tt.expectedField {
+				t.Errorf("Expected field %q, got %v", tt.expectedField, details["field"])
+			}
+		})
+	}
+}
+
+func TestValidateClientEncryption(t *testing.T) {
+	tests := []struct {
+		name          string
+		enc           *service.ClientEncryption
+		expectedCode  s3cerrors.ErrorCode
+		expectedField string
+	}{
+		{
+			name: "no client-side encryption",
+		},
+		{
+			name: "passphrase",
+			enc:  &service.ClientEncryption{KeySource: service.ClientKeySourcePassphrase, Passphrase: "correct horse"},
+		},
+		{
+			name: "key file",
+			enc:  &service.ClientEncryption{KeySource: service.ClientKeySourceKeyFile},
+		},
+		{
+			name:          "unknown key source",
+			enc:           &service.ClientEncryption{KeySource: "kms"},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "clientEncryption.keySource",
+		},
+		{
+			name:          "passphrase missing",
+			enc:           &service.ClientEncryption{KeySource: service.ClientKeySourcePassphrase},
+			expectedCode:  s3cerrors.CodeMissingField,
+			expectedField: "clientEncryption.passphrase",
+		},
+		{
+			name:          "passphrase too short",
+			enc:           &service.ClientEncryption{KeySource: service.ClientKeySourcePassphrase, Passphrase: "secret"},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "clientEncryption.passphrase",
+		},
+		{
+			name:          "key file with passphrase",
+			enc:           &service.ClientEncryption{KeySource: service.ClientKeySourceKeyFile, Passphrase: "correct horse"},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "clientEncryption.passphrase",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := validateClientEncryption("clientEncryption", tt.enc)
+
+			// Assert
+			if tt.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("Expected valid client-side encryption, got %v", err)
+				}
+				return
+			}
+
+			var s3cErr *s3cerrors.S3CError
+			if !errors.As(err, &s3cErr) {
+				t.Fatalf("Expected S3CError, got %v", err)
+			}
+			if s3cErr.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, s3cErr.Code)
+			}
+			details := s3cErr.Details.(map[string]any)
+			if details["field"] != tt.expectedField {
+				t.Errorf("Expected field %q, got %v", tt.expectedField, details["field"])
+			}
+			if strings.Contains(s3cErr.Error(), "correct horse") {
+				t.Errorf("Expected the passphrase to stay out of the error, got %v", s3cErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// BucketInfoRequest represents the request for the bucket properties panel
+type BucketInfoRequest struct {
+	Bucket string `json:"bucket"`
+}
+
+// HandleBucketInfo handles POST /api/buckets/info
+//
+// Sections the credentials or endpoint cannot provide are marked as such instead
+// of failing the request; only a missing bucket is reported as an error.
+func (h *APIHandler) HandleBucketInfo(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "get_bucket_info", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketInfoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		opLogger.Error("Failed to decode bucket info request", "error", err)
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	info, err := h.s3Service.GetBucketInfo(ctx, req.Bucket)
+	if err != nil {
+		opLogger.Error("Failed to get bucket info", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      info,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleBucketInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		bucketInfoErr  error
+		expectedStatus int
+	}{
+		{
+			name:           "bucket info with degraded sections",
+			body:           `{"bucket":"test-bucket"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bucket not found",
+			body:           `{"bucket":"missing-bucket"}`,
+			bucketInfoErr:  s3cerrors.NewS3BucketNotFoundError("missing-bucket"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{bucketInfoErr: tt.bucketInfoErr}
+
+			req := httptest.NewRequest("POST", "/api/buckets/info", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketInfo(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data struct {
+					Region            service.BucketInfoSection `json:"region"`
+					Encryption        service.BucketInfoSection `json:"encryption"`
+					PublicAccessBlock service.BucketInfoSection `json:"publicAccessBlock"`
+					OwnershipControls service.BucketInfoSection `json:"ownershipControls"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			sections := response.Data
+			if sections.Region.Status != service.SectionOK || sections.Region.Value != "eu-west-1" {
+				t.Errorf("Expected region eu-west-1, got %+v", sections.Region)
+			}
+			if sections.Encryption.Status != service.SectionNotConfigured ||
+				sections.PublicAccessBlock.Status != service.SectionNotPermitted ||
+				sections.OwnershipControls.Status != service.SectionNotSupported {
+				t.Errorf("Expected degraded sections, got %+v", sections)
+			}
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// maxBucketPolicySize is the S3 limit on the size of a bucket policy document
+const maxBucketPolicySize = 20 * 1024
+
+// Elements allowed in an IAM policy document and in each of its statements
+var (
+	policyElements    = []string{"Version", "Id", "Statement"}
+	statementElements = []string{"Sid", "Effect", "Principal", "NotPrincipal", "Action", "NotAction", "Resource", "NotResource", "Condition"}
+	principalTypes    = []string{"AWS", "Service", "Federated", "CanonicalUser"}
+	policyVersions    = []string{"2012-10-17", "2008-10-17"}
+)
+
+// BucketPolicyRequest represents the request for reading, replacing or removing a bucket policy
+type BucketPolicyRequest struct {
+	Bucket string `json:"bucket"`
+	Policy string `json:"policy,omitempty"` // JSON document, only used when updating
+}
+
+// HandleBucketPolicy handles POST /api/buckets/policy
+func (h *APIHandler) HandleBucketPolicy(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "get_bucket_policy", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	policy, err := h.s3Service.GetBucketPolicy(ctx, req.Bucket)
+	if err != nil {
+		opLogger.Error("Failed to get bucket policy", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      policy,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketPolicyUpdate handles POST /api/buckets/policy/update
+//
+// The document is checked against the IAM policy grammar before it is sent, so
+// mistakes are reported per statement instead of as S3's generic MalformedPolicy.
+func (h *APIHandler) HandleBucketPolicyUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_bucket_policy", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validatePolicyDocument(req.Policy); err != nil {
+		opLogger.Warn("Rejected invalid bucket policy", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.s3Service.PutBucketPolicy(ctx, req.Bucket, req.Policy); err != nil {
+		opLogger.Error("Failed to update bucket policy", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated bucket policy", "bucketName", req.Bucket)
+
+	// The document was validated above, so indenting cannot fail
+	var pretty bytes.Buffer
+	_ = json.Indent(&pretty, []byte(req.Policy), "", "  ")
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Bucket policy updated successfully",
+			"bucket":  req.Bucket,
+			"policy":  pretty.String(),
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketPolicyDelete handles POST /api/buckets/policy/delete
+func (h *APIHandler) HandleBucketPolicyDelete(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "delete_bucket_policy", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.s3Service.DeleteBucketPolicy(ctx, req.Bucket); err != nil {
+		opLogger.Error("Failed to delete bucket policy", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Deleted bucket policy", "bucketName", req.Bucket)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Bucket policy deleted successfully",
+			"bucket":  req.Bucket,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketPolicyStatus handles POST /api/buckets/policy/status
+func (h *APIHandler) HandleBucketPolicyStatus(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	status, err := h.s3Service.GetBucketPolicyStatus(ctx, req.Bucket)
+	if err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      status,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// validatePolicyDocument checks that a bucket policy is a well-formed IAM policy.
+// Errors use CodeInvalidFormat and name the offending statement and element.
+func validatePolicyDocument(policy string) error {
+	if strings.TrimSpace(policy) == "" {
+		return s3cerrors.NewMissingFieldError("policy")
+	}
+	if len(policy) > maxBucketPolicySize {
+		return s3cerrors.NewValidationError(s3cerrors.CodeOutOfRange, "Bucket policies can be at most 20 KB").
+			WithDetails(map[string]any{"field": "policy", "size": len(policy)})
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		details := map[string]any{"field": "policy"}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			details["line"], details["column"] = textPosition(policy, syntaxErr.Offset)
+		}
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidFormat, "Policy is not a valid JSON object").
+			WithDetails(details).
+			WithWrapped(err)
+	}
+
+	for element := range document {
+		if !slices.Contains(policyElements, element) {
+			return policyFormatError(fmt.Sprintf("Unknown policy element %q", element), map[string]any{"element": element})
+		}
+	}
+
+	version, _ := document["Version"].(string)
+	if !slices.Contains(policyVersions, version) {
+		return policyFormatError(`Version must be "2012-10-17"`, map[string]any{"element": "Version"})
+	}
+
+	var statements []any
+	switch statement := document["Statement"].(type) {
+	case map[string]any:
+		statements = []any{statement}
+	case []any:
+		statements = statement
+	}
+	if len(statements) == 0 {
+		return policyFormatError("Statement must be an object or a non-empty list of objects", map[string]any{"element": "Statement"})
+	}
+
+	sids := make(map[string]bool)
+	for i, raw := range statements {
+		statement, ok := raw.(map[string]any)
+		if !ok {
+			return policyFormatError(fmt.Sprintf("Statement %d must be an object", i+1), map[string]any{"statement": i, "element": "Statement"})
+		}
+
+		sid, _ := statement["Sid"].(string)
+		if sid != "" {
+			if sids[sid] {
+				return statementError(i, sid, "Sid", "duplicates the Sid of an earlier statement")
+			}
+			sids[sid] = true
+		}
+
+		if err := validatePolicyStatement(i, sid, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePolicyStatement checks the elements of a single policy statement
+func validatePolicyStatement(index int, sid string, statement map[string]any) error {
+	for element := range statement {
+		if !slices.Contains(statementElements, element) {
+			return statementError(index, sid, element, "is not a statement element")
+		}
+	}
+
+	if effect := statement["Effect"]; effect != "Allow" && effect != "Deny" {
+		return statementError(index, sid, "Effect", `must be "Allow" or "Deny"`)
+	}
+
+	// Each pair must be set exactly once, e.g. Action or NotAction
+	checks := []struct {
+		element string
+		valid   func(any) bool
+		problem string
+	}{
+		{"Principal", isPolicyPrincipal, `must be "*" or map AWS, Service, Federated or CanonicalUser to principals`},
+		{"Action", isPolicyAction, `must be "*" or service:action names, e.g. "s3:GetObject"`},
+		{"Resource", isPolicyResource, `must be "*" or ARNs, e.g. "arn:aws:s3:::bucket/*"`},
+	}
+	for _, check := range checks {
+		value, hasElement := statement[check.element]
+		notValue, hasNot := statement["Not"+check.element]
+		switch {
+		case hasElement && hasNot:
+			return statementError(index, sid, check.element, fmt.Sprintf("cannot be combined with Not%s", check.element))
+		case !hasElement && !hasNot:
+			return statementError(index, sid, check.element, "is required")
+		case hasNot:
+			value = notValue
+		}
+		if !check.valid(value) {
+			return statementError(index, sid, check.element, check.problem)
+		}
+	}
+
+	if condition, ok := statement["Condition"]; ok {
+		operators, ok := condition.(map[string]any)
+		if !ok {
+			return statementError(index, sid, "Condition", "must map condition operators to key-value objects")
+		}
+		for _, keys := range operators {
+			if _, ok := keys.(map[string]any); !ok {
+				return statementError(index, sid, "Condition", "must map condition operators to key-value objects")
+			}
+		}
+	}
+	return nil
+}
+
+// isPolicyPrincipal accepts "*" or a map of principal types to one or more principals
+func isPolicyPrincipal(value any) bool {
+	if value == "*" {
+		return true
+	}
+	principals, ok := value.(map[string]any)
+	if !ok || len(principals) == 0 {
+		return false
+	}
+	for principalType, ids := range principals {
+		if !slices.Contains(principalTypes, principalType) {
+			return false
+		}
+		if _, ok := policyStrings(ids); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isPolicyAction accepts "*" or service-prefixed action names, which may use wildcards
+func isPolicyAction(value any) bool {
+	actions, ok := policyStrings(value)
+	if !ok {
+		return false
+	}
+	for _, action := range actions {
+		if action != "*" && !strings.Contains(action, ":") {
+			return false
+		}
+	}
+	return true
+}
+
+// isPolicyResource accepts "*" or ARNs
+func isPolicyResource(value any) bool {
+	resources, ok := policyStrings(value)
+	if !ok {
+		return false
+	}
+	for _, resource := range resources {
+		if resource != "*" && !strings.HasPrefix(resource, "arn:") {
+			return false
+		}
+	}
+	return true
+}
+
+// policyStrings reads an element that holds a string or a non-empty list of strings
+func policyStrings(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, v != ""
+	case []any:
+		values := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok || s == "" {
+				return nil, false
+			}
+			values[i] = s
+		}
+		return values, len(values) > 0
+	default:
+		return nil, false
+	}
+}
+
+// policyFormatError reports a problem with the policy document as a whole
+func policyFormatError(message string, details map[string]any) error {
+	details["field"] = "policy"
+	return s3cerrors.NewValidationError(s3cerrors.CodeInvalidFormat, message).
+		WithDetails(details).
+		WithSuggestion("Check the document against the IAM policy grammar")
+}
+
+// statementError reports a problem with one statement, numbered from 1 as editors show them
+func statementError(index int, sid, element, problem string) error {
+	label := fmt.Sprintf("Statement %d", index+1)
+	if sid != "" {
+		label += fmt.Sprintf(" (%s)", sid)
+	}
+	return policyFormatError(fmt.Sprintf("%s: %s %s", label, element, problem), map[string]any{
+		"statement": index,
+		"sid":       sid,
+		"element":   element,
+	})
+}
+
+// textPosition converts a json.SyntaxError offset, which points just past the
+// offending byte, into the 1-based line and column of that byte
+func textPosition(text string, offset int64) (line, column int) {
+	before := text[:min(int(offset), len(text))]
+	line = strings.Count(before, "\n") + 1
+	column = len(before) - strings.LastIndex(before, "\n") - 1
+	return line, column
+}
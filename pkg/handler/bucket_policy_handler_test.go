@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+const publicReadPolicy = `{"Version":"2012-10-17","Statement":[{"Sid":"PublicRead","Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::test-bucket/*"}]}`
+
+func TestValidatePolicyDocument(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       string
+		expectedCode s3cerrors.ErrorCode
+		statement    any // expected statement index in the error details
+		element      string
+	}{
+		{
+			name:   "valid public read policy",
+			policy: publicReadPolicy,
+		},
+		{
+			name: "valid single statement with not elements and condition",
+			policy: `{"Version":"2012-10-17","Statement":{"Effect":"Deny","NotPrincipal":{"AWS":["arn:aws:iam::123456789012:root"]},` +
+				`"NotAction":["s3:Get*","s3:List*"],"Resource":["arn:aws:s3:::b","arn:aws:s3:::b/*"],"Condition":{"Bool":{"aws:SecureTransport":"false"}}}}`,
+		},
+		{
+			name:         "empty policy",
+			policy:       "  ",
+			expectedCode: s3cerrors.CodeMissingField,
+		},
+		{
+			name:         "not json",
+			policy:       "{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": [}\n",
+			expectedCode: s3cerrors.CodeInvalidFormat,
+		},
+		{
+			name:         "too large",
+			policy:       `{"Id":"` + strings.Repeat("x", maxBucketPolicySize) + `"}`,
+			expectedCode: s3cerrors.CodeOutOfRange,
+		},
+		{
+			name:         "missing version",
+			policy:       `{"Statement":[]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			element:      "Version",
+		},
+		{
+			name:         "unknown top level element",
+			policy:       `{"Version":"2012-10-17","Statements":[]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			element:      "Statements",
+		},
+		{
+			name:         "empty statement list",
+			policy:       `{"Version":"2012-10-17","Statement":[]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			element:      "Statement",
+		},
+		{
+			name:         "invalid effect in second statement",
+			policy:       `{"Version":"2012-10-17","Statement":[` + statementJSON(`"Allow"`) + `,` + statementJSON(`"Permit"`) + `]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			statement:    float64(1),
+			element:      "Effect",
+		},
+		{
+			name:         "missing principal",
+			policy:       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			statement:    float64(0),
+			element:      "Principal",
+		},
+		{
+			name:         "unknown principal type",
+			policy:       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"User":"bob"},"Action":"s3:GetObject","Resource":"*"}]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			statement:    float64(0),
+			element:      "Principal",
+		},
+		{
+			name:         "action without service prefix",
+			policy:       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["GetObject"],"Resource":"*"}]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			statement:    float64(0),
+			element:      "Action",
+		},
+		{
+			name:         "action and not action together",
+			policy:       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:*","NotAction":"s3:Delete*","Resource":"*"}]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			statement:    float64(0),
+			element:      "Action",
+		},
+		{
+			name:         "resource is not an arn",
+			policy:       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"test-bucket/*"}]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			statement:    float64(0),
+			element:      "Resource",
+		},
+		{
+			name:         "duplicate sid",
+			policy:       `{"Version":"2012-10-17","Statement":[{"Sid":"A",` + statementJSON(`"Allow"`)[1:] + `,{"Sid":"A",` + statementJSON(`"Deny"`)[1:] + `]}`,
+			expectedCode: s3cerrors.CodeInvalidFormat,
+			statement:    float64(1),
+			element:      "Sid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := validatePolicyDocument(tt.policy)
+
+			// Assert
+			if tt.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("Expected valid policy, got %v", err)
+				}
+				return
+			}
+
+			var s3cErr *s3cerrors.S3CError
+			if !errors.As(err, &s3cErr) {
+				t.Fatalf("Expected S3CError, got %v", err)
+			}
+			if s3cErr.Code != tt.expectedCode {
+				t.Fatalf("Expected code %s, got %s: %s", tt.expectedCode, s3cErr.Code, s3cErr.Message)
+			}
+			if tt.element == "" {
+				return
+			}
+
+			// Round trip through JSON so the details look like the API response
+			encoded, _ := json.Marshal(s3cErr.Details)
+			var details map[string]any
+			if err := json.Unmarshal(encoded, &details); err != nil {
+				t.Fatalf("Failed to decode details: %v", err)
+			}
+			if details["element"] != tt.element {
+				t.Errorf("Expected element %q, got %v (%s)", tt.element, details["element"], s3cErr.Message)
+			}
+			if details["statement"] != tt.statement {
+				t.Errorf("Expected statement %v, got %v (%s)", tt.statement, details["statement"], s3cErr.Message)
+			}
+		})
+	}
+}
+
+// statementJSON returns a minimal valid statement with the given Effect value
+func statementJSON(effect string) string {
+	return `{"Effect":` + effect + `,"Principal":"*","Action":"s3:GetObject","Resource":"*"}`
+}
+
+func TestValidatePolicyDocument_SyntaxErrorPosition(t *testing.T) {
+	// Arrange
+	policy := "{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": [}\n"
+
+	// Act
+	err := validatePolicyDocument(policy)
+
+	// Assert
+	var s3cErr *s3cerrors.S3CError
+	if !errors.As(err, &s3cErr) {
+		t.Fatalf("Expected S3CError, got %v", err)
+	}
+	details := s3cErr.Details.(map[string]any)
+	if details["line"] != 3 || details["column"] != 17 {
+		t.Errorf("Expected error at line 3 column 17, got line %v column %v", details["line"], details["column"])
+	}
+}
+
+func TestAPIHandler_HandleBucketPolicyUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           any
+		serviceErr     error
+		expectedStatus int
+		expectedPolicy string
+	}{
+		{
+			name:           "valid policy is stored",
+			body:           BucketPolicyRequest{Bucket: "test-bucket", Policy: publicReadPolicy},
+			expectedStatus: http.StatusOK,
+			expectedPolicy: publicReadPolicy,
+		},
+		{
+			name:           "invalid policy is not sent",
+			body:           BucketPolicyRequest{Bucket: "test-bucket", Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow"}]}`},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           BucketPolicyRequest{Policy: publicReadPolicy},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "access denied",
+			body:           BucketPolicyRequest{Bucket: "test-bucket", Policy: publicReadPolicy},
+			serviceErr:     s3cerrors.NewS3AccessDeniedError("put bucket policy", "test-bucket"),
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{bucketPolicyErr: tt.serviceErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest("POST", "/api/buckets/policy/update", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketPolicyUpdate(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if mock.bucketPolicy != tt.expectedPolicy {
+				t.Errorf("Expected stored policy %q, got %q", tt.expectedPolicy, mock.bucketPolicy)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data struct {
+					Policy string `json:"policy"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if !strings.Contains(response.Data.Policy, "\n  \"Version\": \"2012-10-17\"") {
+				t.Errorf("Expected pretty-printed policy, got %s", response.Data.Policy)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         string
+		expectedExists bool
+	}{
+		{
+			name:           "bucket with policy",
+			policy:         publicReadPolicy,
+			expectedExists: true,
+		},
+		{
+			name:           "bucket without policy",
+			expectedExists: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{bucketPolicy: tt.policy}
+
+			req := httptest.NewRequest("POST", "/api/buckets/policy", bytes.NewBufferString(`{"bucket":"test-bucket"}`))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketPolicy(w, req)
+
+			// Assert
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response struct {
+				Data struct {
+					Exists bool   `json:"exists"`
+					Policy string `json:"policy"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Data.Exists != tt.expectedExists || response.Data.Policy != tt.policy {
+				t.Errorf("Expected exists=%v policy %q, got %+v", tt.expectedExists, tt.policy, response.Data)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketPolicyDeleteAndStatus(t *testing.T) {
+	// Arrange
+	mock := &mockS3Service{bucketPolicy: publicReadPolicy}
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = mock
+
+	status := func() bool {
+		req := httptest.NewRequest("POST", "/api/buckets/policy/status", bytes.NewBufferString(`{"bucket":"test-bucket"}`))
+		w := httptest.NewRecorder()
+		handler.HandleBucketPolicyStatus(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data struct {
+				IsPublic bool `json:"isPublic"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		return response.Data.IsPublic
+	}
+
+	if !status() {
+		t.Fatal("Expected public bucket before deleting the policy")
+	}
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/buckets/policy/delete", bytes.NewBufferString(`{"bucket":"test-bucket"}`))
+	w := httptest.NewRecorder()
+	handler.HandleBucketPolicyDelete(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if mock.bucketPolicy != "" {
+		t.Errorf("Expected policy to be removed, got %q", mock.bucketPolicy)
+	}
+	if status() {
+		t.Error("Expected private bucket after deleting the policy")
+	}
+}
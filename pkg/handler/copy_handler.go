@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// CopyObjectsRequest represents the request for copying or moving objects
+type CopyObjectsRequest struct {
+	Bucket            string           `json:"bucket"`                      // source bucket
+	DestinationBucket string           `json:"destinationBucket,omitempty"` // defaults to the source bucket
+	Type              string           `json:"type,omitempty"`              // "files" (default) or "folder"
+	Items             []CopyObjectItem `json:"items,omitempty"`             // for files
+	Prefix            string           `json:"prefix,omitempty"`            // source folder
+	DestinationPrefix string           `json:"destinationPrefix,omitempty"` // destination folder
+
+	// Encryption applies to the copies, which otherwise get the destination bucket default
+	Encryption *service.ServerSideEncryption `json:"encryption,omitempty"`
+	// SourceSSECustomerKey is the base64 key SSE-C sources were uploaded with
+	SourceSSECustomerKey string `json:"sourceSseCustomerKey,omitempty"`
+}
+
+// CopyObjectItem maps a source key to its destination key
+type CopyObjectItem struct {
+	SourceKey      string `json:"sourceKey"`
+	DestinationKey string `json:"destinationKey"`
+}
+
+// HandleObjectsCopy handles POST /api/objects/copy
+func (h *APIHandler) HandleObjectsCopy(w http.ResponseWriter, r *http.Request) {
+	h.handleCopyObjects(w, r, false)
+}
+
+// HandleObjectsMove handles POST /api/objects/move
+//
+// A move is a copy followed by deleting the source, which is also how objects
+// and folders are renamed.
+func (h *APIHandler) HandleObjectsMove(w http.ResponseWriter, r *http.Request) {
+	h.handleCopyObjects(w, r, true)
+}
+
+// handleCopyObjects copies or moves each requested object server-side
+func (h *APIHandler) handleCopyObjects(w http.ResponseWriter, r *http.Request, move bool) {
+	requestID := generateRequestID()
+	operation, verb, action := "copy_objects", "copy", "Copied"
+	if move {
+		operation, verb, action = "move_objects", "move", "Moved"
+	}
+	opLogger := h.logger.With("operation", operation, "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req CopyObjectsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		opLogger.Error("Failed to decode copy request", "error", err)
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Validate request
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateEncryption("encryption", req.Encryption); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+	if err := validateSSECustomerKey("sourceSseCustomerKey", req.SourceSSECustomerKey); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+	destinationBucket := req.DestinationBucket
+	if destinationBucket == "" {
+		destinationBucket = req.Bucket
+	}
+
+	switch req.Type {
+	case "", "files":
+	case "folder":
+		h.copyFolder(w, r, req, destinationBucket, move, requestID)
+		return
+	default:
+		s3cErr := s3cerrors.NewInvalidInputError("type", "must be 'files' or 'folder'")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if len(req.Items) == 0 {
+		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "At least one item is required")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	for _, item := range req.Items {
+		if item.SourceKey == "" {
+			h.writeStructuredError(w, s3cerrors.NewMissingFieldError("sourceKey"), requestID)
+			return
+		}
+		if item.DestinationKey == "" {
+			h.writeStructuredError(w, s3cerrors.NewMissingFieldError("destinationKey"), requestID)
+			return
+		}
+		if req.Bucket == destinationBucket && item.SourceKey == item.DestinationKey {
+			s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Source and destination are the same object").
+				WithDetails(map[string]any{"key": item.SourceKey})
+			h.writeStructuredError(w, s3cErr, requestID)
+			return
+		}
+	}
+
+	// Objects over 5 GiB are copied in parts and can take minutes,
+	// so lift the server deadlines and cancel only when the client goes away
+	h.extendDeadlines(w)
+	ctx := r.Context()
+
+	var results []*service.CopyObjectOutput
+	var failures []BatchItemError
+	for _, item := range req.Items {
+		input := service.CopyObjectInput{
+			SourceBucket:      req.Bucket,
+			SourceKey:         item.SourceKey,
+			DestinationBucket: destinationBucket,
+			DestinationKey:    item.DestinationKey,
+
+			Encryption:           req.Encryption,
+			SourceSSECustomerKey: req.SourceSSECustomerKey,
+		}
+
+		var output *service.CopyObjectOutput
+		var err error
+		if move {
+			output, err = h.s3Service.MoveObject(ctx, input)
+		} else {
+			output, err = h.s3Service.CopyObject(ctx, input)
+		}
+		if err != nil {
+			opLogger.Warn("Failed to "+verb+" object",
+				"error", err,
+				"sourceKey", item.SourceKey,
+				"destinationKey", item.DestinationKey,
+			)
+			failures = append(failures, newBatchItemError(item.SourceKey, err))
+			continue
+		}
+		results = append(results, output)
+	}
+
+	opLogger.Info("Finished server-side "+verb,
+		"bucket", req.Bucket,
+		"destinationBucket", destinationBucket,
+		"succeeded", len(results),
+		"failed", len(failures),
+	)
+
+	data := map[string]any{
+		"bucket":            req.Bucket,
+		"destinationBucket": destinationBucket,
+		"objects":           results,
+	}
+	h.writeBatchResponse(w, requestID, data, len(results), failures, action)
+}
+
+// copyFolder copies or moves every object under a prefix and reports the outcome per key
+func (h *APIHandler) copyFolder(w http.ResponseWriter, r *http.Request, req CopyObjectsRequest, destinationBucket string, move bool, requestID string) {
+	if req.Prefix == "" {
+		h.writeStructuredError(w, s3cerrors.NewMissingFieldError("prefix"), requestID)
+		return
+	}
+	if req.DestinationPrefix == "" {
+		h.writeStructuredError(w, s3cerrors.NewMissingFieldError("destinationPrefix"), requestID)
+		return
+	}
+
+	// Folders may hold thousands of objects, so lift the server deadlines
+	h.extendDeadlines(w)
+	ctx := r.Context()
+
+	input := service.CopyPrefixInput{
+		SourceBucket:      req.Bucket,
+		SourcePrefix:      req.Prefix,
+		DestinationBucket: destinationBucket,
+		DestinationPrefix: req.DestinationPrefix,
+
+		Encryption:           req.Encryption,
+		SourceSSECustomerKey: req.SourceSSECustomerKey,
+	}
+
+	var output *service.CopyPrefixOutput
+	var err error
+	action := "Copied"
+	if move {
+		output, err = h.s3Service.MovePrefix(ctx, input)
+		action = "Moved"
+	} else {
+		output, err = h.s3Service.CopyPrefix(ctx, input)
+	}
+	if err != nil {
+		h.logger.Error("Failed to transfer folder",
+			"error", err,
+			"requestId", requestID,
+			"bucket", req.Bucket,
+			"prefix", req.Prefix,
+			"destinationPrefix", req.DestinationPrefix,
+		)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	failures := make([]BatchItemError, len(output.Failed))
+	for i, failed := range output.Failed {
+		failures[i] = BatchItemError(failed)
+	}
+
+	data := map[string]any{
+		"bucket":            req.Bucket,
+		"destinationBucket": destinationBucket,
+		"prefix":            output.SourcePrefix,
+		"destinationPrefix": output.DestinationPrefix,
+		"objects":           output.Objects,
+	}
+	h.writeBatchResponse(w, requestID, data, len(output.Objects), failures, action)
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectsCopyAndMove(t *testing.T) {
+	// failingCopy rejects one source key to exercise partial success
+	failingCopy := func(input service.CopyObjectInput) (*service.CopyObjectOutput, error) {
+		if input.SourceKey == "denied.txt" {
+			return nil, s3cerrors.NewS3AccessDeniedError("copy object", input.SourceKey)
+		}
+		return &service.CopyObjectOutput{SourceKey: input.SourceKey, DestinationKey: input.DestinationKey}, nil
+	}
+
+	tests := []struct {
+		name           string
+		move           bool
+		requestBody    CopyObjectsRequest
+		expectedStatus int
+		expectedMoved  []string
+	}{
+		{
+			name: "copy within bucket",
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Items:  []CopyObjectItem{{SourceKey: "a.txt", DestinationKey: "b.txt"}},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "copy across buckets",
+			requestBody: CopyObjectsRequest{
+				Bucket:            "test-bucket",
+				DestinationBucket: "other-bucket",
+				Items:             []CopyObjectItem{{SourceKey: "a.txt", DestinationKey: "a.txt"}},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "move renames objects",
+			move: true,
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Items:  []CopyObjectItem{{SourceKey: "old.txt", DestinationKey: "new.txt"}},
+			},
+			expectedStatus: http.StatusOK,
+			expectedMoved:  []string{"old.txt"},
+		},
+		{
+			name: "move keeps sources whose copy failed",
+			move: true,
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Items: []CopyObjectItem{
+					{SourceKey: "ok.txt", DestinationKey: "dest/ok.txt"},
+					{SourceKey: "denied.txt", DestinationKey: "dest/denied.txt"},
+				},
+			},
+			expectedStatus: http.StatusPartialContent,
+			expectedMoved:  []string{"ok.txt"},
+		},
+		{
+			name: "all copies failed",
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Items:  []CopyObjectItem{{SourceKey: "denied.txt", DestinationKey: "x.txt"}},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "same source and destination",
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Items:  []CopyObjectItem{{SourceKey: "a.txt", DestinationKey: "a.txt"}},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing destination key",
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Items:  []CopyObjectItem{{SourceKey: "a.txt"}},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing items",
+			requestBody:    CopyObjectsRequest{Bucket: "test-bucket"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown encryption mode",
+			requestBody: CopyObjectsRequest{
+				Bucket:     "test-bucket",
+				Items:      []CopyObjectItem{{SourceKey: "a.txt", DestinationKey: "b.txt"}},
+				Encryption: &service.ServerSideEncryption{Mode: "AES256"},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{copyFunc: failingCopy}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			body, _ := json.Marshal(tt.requestBody)
+			w := httptest.NewRecorder()
+
+			// Act
+			if tt.move {
+				handler.HandleObjectsMove(w, httptest.NewRequest("POST", "/api/objects/move", bytes.NewBuffer(body)))
+			} else {
+				handler.HandleObjectsCopy(w, httptest.NewRequest("POST", "/api/objects/copy", bytes.NewBuffer(body)))
+			}
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if !slices.Equal(mockService.movedKeys, tt.expectedMoved) {
+				t.Errorf("Expected moved keys %v, got %v", tt.expectedMoved, mockService.movedKeys)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsCopy_Encryption(t *testing.T) {
+	// Arrange
+	sourceKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32))
+	var received service.CopyObjectInput
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = &mockS3Service{copyFunc: func(input service.CopyObjectInput) (*service.CopyObjectOutput, error) {
+		received = input
+		return &service.CopyObjectOutput{SourceKey: input.SourceKey, DestinationKey: input.DestinationKey}, nil
+	}}
+
+	body, _ := json.Marshal(CopyObjectsRequest{
+		Bucket:               "test-bucket",
+		Items:                []CopyObjectItem{{SourceKey: "secret.bin", DestinationKey: "audit/secret.bin"}},
+		Encryption:           &service.ServerSideEncryption{Mode: service.SSEModeKMS, KMSKeyID: "alias/audit"},
+		SourceSSECustomerKey: sourceKey,
+	})
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.HandleObjectsCopy(w, httptest.NewRequest("POST", "/api/objects/copy", bytes.NewBuffer(body)))
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if received.Encryption == nil || received.Encryption.KMSKeyID != "alias/audit" {
+		t.Errorf("Expected destination encryption to reach the service, got %+v", received.Encryption)
+	}
+	if received.SourceSSECustomerKey != sourceKey {
+		t.Errorf("Expected source key to reach the service, got %q", received.SourceSSECustomerKey)
+	}
+}
+
+func TestAPIHandler_HandleObjectsMove_Folder(t *testing.T) {
+	moved := &service.CopyPrefixOutput{
+		SourcePrefix:      "reports/2024/",
+		DestinationPrefix: "archive/2024/",
+		Objects: []service.CopyObjectOutput{
+			{SourceKey: "reports/2024/", DestinationKey: "archive/2024/"},
+			{SourceKey: "reports/2024/q1.csv", DestinationKey: "archive/2024/q1.csv"},
+		},
+	}
+	partial := &service.CopyPrefixOutput{
+		SourcePrefix:      "reports/2024/",
+		DestinationPrefix: "archive/2024/",
+		Objects:           moved.Objects[:1],
+		Failed:            []service.ObjectError{{Key: "reports/2024/q1.csv", Code: "AccessDenied", Message: "Access Denied"}},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    CopyObjectsRequest
+		output         *service.CopyPrefixOutput
+		serviceErr     error
+		expectedStatus int
+		expectedFailed int
+	}{
+		{
+			name: "move folder",
+			requestBody: CopyObjectsRequest{
+				Bucket:            "test-bucket",
+				Type:              "folder",
+				Prefix:            "reports/2024/",
+				DestinationPrefix: "archive/2024/",
+			},
+			output:         moved,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "partial failure keeps failed keys",
+			requestBody: CopyObjectsRequest{
+				Bucket:            "test-bucket",
+				Type:              "folder",
+				Prefix:            "reports/2024/",
+				DestinationPrefix: "archive/2024/",
+			},
+			output:         partial,
+			expectedStatus: http.StatusPartialContent,
+			expectedFailed: 1,
+		},
+		{
+			name: "destination inside source",
+			requestBody: CopyObjectsRequest{
+				Bucket:            "test-bucket",
+				Type:              "folder",
+				Prefix:            "reports/",
+				DestinationPrefix: "reports/old/",
+			},
+			serviceErr:     s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Destination folder must not be inside the source folder"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing destination prefix",
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Type:   "folder",
+				Prefix: "reports/2024/",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid type",
+			requestBody: CopyObjectsRequest{
+				Bucket: "test-bucket",
+				Type:   "bucket",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{prefixOutput: tt.output, prefixErr: tt.serviceErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/api/objects/move", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsMove(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.output == nil {
+				return
+			}
+			if mockService.prefixInput.DestinationBucket != "test-bucket" {
+				t.Errorf("Expected destination bucket to default to source, got %q", mockService.prefixInput.DestinationBucket)
+			}
+
+			var response APIResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			data := response.Data.(map[string]any)
+			if tt.expectedFailed > 0 {
+				failures, _ := data["errors"].([]any)
+				if len(failures) != tt.expectedFailed {
+					t.Errorf("Expected %d failed keys, got %v", tt.expectedFailed, data["errors"])
+				}
+			} else if _, ok := data["errors"]; ok {
+				t.Errorf("Expected no errors, got %v", data["errors"])
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// S3 CORS configuration limits
+const (
+	maxCORSRules      = 100
+	maxCORSRuleIDSize = 255
+)
+
+// corsMethods are the only methods S3 accepts in AllowedMethods
+var corsMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// BucketCorsRequest identifies the bucket whose CORS rules are read or removed
+type BucketCorsRequest struct {
+	Bucket string `json:"bucket"`
+}
+
+// UpdateBucketCorsRequest represents the request for replacing the CORS rules of a bucket
+type UpdateBucketCorsRequest struct {
+	Bucket string             `json:"bucket"`
+	Rules  []service.CORSRule `json:"rules"`
+}
+
+// HandleBucketCors handles POST /api/buckets/cors
+func (h *APIHandler) HandleBucketCors(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "get_bucket_cors", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketCorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cors, err := h.s3Service.GetBucketCors(ctx, req.Bucket)
+	if err != nil {
+		opLogger.Error("Failed to get bucket CORS", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      cors,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketCorsUpdate handles POST /api/buckets/cors/update
+func (h *APIHandler) HandleBucketCorsUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_bucket_cors", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req UpdateBucketCorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateCORSRules(req.Rules); err != nil {
+		opLogger.Warn("Rejected invalid CORS rules", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.s3Service.PutBucketCors(ctx, req.Bucket, req.Rules); err != nil {
+		opLogger.Error("Failed to update bucket CORS", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated bucket CORS", "bucketName", req.Bucket, "ruleCount", len(req.Rules))
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Bucket CORS rules updated successfully",
+			"bucket":  req.Bucket,
+			"rules":   req.Rules,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketCorsDelete handles POST /api/buckets/cors/delete
+func (h *APIHandler) HandleBucketCorsDelete(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "delete_bucket_cors", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketCorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.s3Service.DeleteBucketCors(ctx, req.Bucket); err != nil {
+		opLogger.Error("Failed to delete bucket CORS", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Deleted bucket CORS", "bucketName", req.Bucket)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Bucket CORS rules deleted successfully",
+			"bucket":  req.Bucket,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// validateCORSRules checks a rule list against the S3 CORS configuration limits
+func validateCORSRules(rules []service.CORSRule) error {
+	if len(rules) == 0 {
+		return s3cerrors.NewMissingFieldError("rules").
+			WithSuggestion("Add at least one rule, or delete the CORS configuration instead")
+	}
+	if len(rules) > maxCORSRules {
+		return s3cerrors.NewValidationError(s3cerrors.CodeOutOfRange, fmt.Sprintf("Buckets can have at most %d CORS rules", maxCORSRules)).
+			WithDetails(map[string]any{"field": "rules", "count": len(rules)})
+	}
+
+	for i, rule := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+
+		if len(rule.ID) > maxCORSRuleIDSize {
+			return s3cerrors.NewInvalidInputError(field+".id", rule.ID).
+				WithSuggestion(fmt.Sprintf("Rule IDs can be at most %d characters long", maxCORSRuleIDSize))
+		}
+
+		if len(rule.AllowedMethods) == 0 {
+			return s3cerrors.NewMissingFieldError(field + ".allowedMethods")
+		}
+		for _, method := range rule.AllowedMethods {
+			if !slices.Contains(corsMethods, method) {
+				return s3cerrors.NewInvalidInputError(field+".allowedMethods", method).
+					WithSuggestion("Allowed methods are GET, PUT, POST, DELETE and HEAD, in upper case")
+			}
+		}
+
+		if len(rule.AllowedOrigins) == 0 {
+			return s3cerrors.NewMissingFieldError(field + ".allowedOrigins")
+		}
+		for _, origin := range rule.AllowedOrigins {
+			if origin == "" || strings.Count(origin, "*") > 1 {
+				return s3cerrors.NewInvalidInputError(field+".allowedOrigins", origin).
+					WithSuggestion(`Origins may contain at most one "*" wildcard, e.g. https://*.example.com`)
+			}
+		}
+
+		for _, header := range rule.AllowedHeaders {
+			if header == "" || strings.Count(header, "*") > 1 {
+				return s3cerrors.NewInvalidInputError(field+".allowedHeaders", header).
+					WithSuggestion(`Allowed headers may contain at most one "*" wildcard`)
+			}
+		}
+
+		// Browsers need literal names for exposed headers
+		for _, header := range rule.ExposeHeaders {
+			if header == "" || strings.Contains(header, "*") {
+				return s3cerrors.NewInvalidInputError(field+".exposeHeaders", header).
+					WithSuggestion("Exposed headers cannot contain wildcards")
+			}
+		}
+
+		if rule.MaxAgeSeconds < 0 {
+			return s3cerrors.NewInvalidInputError(field+".maxAgeSeconds", rule.MaxAgeSeconds).
+				WithSuggestion("Max age must be zero or a positive number of seconds")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestValidateCORSRules(t *testing.T) {
+	valid := service.CORSRule{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET", "HEAD"},
+		AllowedHeaders: []string{"*"},
+		ExposeHeaders:  []string{"ETag"},
+		MaxAgeSeconds:  3600,
+	}
+	with := func(change func(rule *service.CORSRule)) []service.CORSRule {
+		rule := valid
+		change(&rule)
+		return []service.CORSRule{valid, rule}
+	}
+
+	tests := []struct {
+		name          string
+		rules         []service.CORSRule
+		expectedCode  s3cerrors.ErrorCode
+		expectedField string
+	}{
+		{
+			name:  "valid rules",
+			rules: []service.CORSRule{valid},
+		},
+		{
+			name:          "no rules",
+			rules:         nil,
+			expectedCode:  s3cerrors.CodeMissingField,
+			expectedField: "rules",
+		},
+		{
+			name:         "too many rules",
+			rules:        make([]service.CORSRule, maxCORSRules+1),
+			expectedCode: s3cerrors.CodeOutOfRange,
+		},
+		{
+			name:          "lower case method",
+			rules:         with(func(rule *service.CORSRule) { rule.AllowedMethods = []string{"get"} }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].allowedMethods",
+		},
+		{
+			name:          "unsupported method",
+			rules:         with(func(rule *service.CORSRule) { rule.AllowedMethods = []string{"PATCH"} }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].allowedMethods",
+		},
+		{
+			name:          "missing methods",
+			rules:         with(func(rule *service.CORSRule) { rule.AllowedMethods = nil }),
+			expectedCode:  s3cerrors.CodeMissingField,
+			expectedField: "rules[1].allowedMethods",
+		},
+		{
+			name:          "missing origins",
+			rules:         with(func(rule *service.CORSRule) { rule.AllowedOrigins = []string{} }),
+			expectedCode:  s3cerrors.CodeMissingField,
+			expectedField: "rules[1].allowedOrigins",
+		},
+		{
+			name:          "origin with two wildcards",
+			rules:         with(func(rule *service.CORSRule) { rule.AllowedOrigins = []string{"https://*.*.example.com"} }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].allowedOrigins",
+		},
+		{
+			name:          "allowed header with two wildcards",
+			rules:         with(func(rule *service.CORSRule) { rule.AllowedHeaders = []string{"x-*-*"} }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].allowedHeaders",
+		},
+		{
+			name:          "wildcard expose header",
+			rules:         with(func(rule *service.CORSRule) { rule.ExposeHeaders = []string{"x-amz-*"} }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].exposeHeaders",
+		},
+		{
+			name:          "negative max age",
+			rules:         with(func(rule *service.CORSRule) { rule.MaxAgeSeconds = -1 }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].maxAgeSeconds",
+		},
+		{
+			name:          "rule id too long",
+			rules:         with(func(rule *service.CORSRule) { rule.ID = strings.Repeat("a", maxCORSRuleIDSize+1) }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := validateCORSRules(tt.rules)
+
+			// Assert
+			if tt.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("Expected valid rules, got %v", err)
+				}
+				return
+			}
+
+			var s3cErr *s3cerrors.S3CError
+			if !errors.As(err, &s3cErr) {
+				t.Fatalf("Expected S3CError, got %v", err)
+			}
+			if s3cErr.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, s3cErr.Code)
+			}
+			if tt.expectedField != "" {
+				details := s3cErr.Details.(map[string]any)
+				if details["field"] != tt.expectedField {
+					t.Errorf("Expected field %q, got %v", tt.expectedField, details["field"])
+				}
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketCorsUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		corsErr        error
+		expectedStatus int
+		expectStored   bool
+	}{
+		{
+			name:           "valid rules are stored",
+			body:           `{"bucket":"assets","rules":[{"allowedOrigins":["https://app.example.com"],"allowedMethods":["GET"],"maxAgeSeconds":600}]}`,
+			expectedStatus: http.StatusOK,
+			expectStored:   true,
+		},
+		{
+			name:           "invalid method is not sent",
+			body:           `{"bucket":"assets","rules":[{"allowedOrigins":["*"],"allowedMethods":["OPTIONS"]}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"rules":[{"allowedOrigins":["*"],"allowedMethods":["GET"]}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bucket not found",
+			body:           `{"bucket":"missing","rules":[{"allowedOrigins":["*"],"allowedMethods":["GET"]}]}`,
+			corsErr:        s3cerrors.NewS3BucketNotFoundError("missing"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{corsErr: tt.corsErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			req := httptest.NewRequest("POST", "/api/buckets/cors/update", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketCorsUpdate(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if stored := len(mock.corsRules) > 0; stored != tt.expectStored {
+				t.Errorf("Expected stored=%v, got rules %+v", tt.expectStored, mock.corsRules)
+			}
+			if tt.expectStored && mock.corsRules[0].MaxAgeSeconds != 600 {
+				t.Errorf("Expected max age to reach the service, got %+v", mock.corsRules[0])
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketCorsGetAndDelete(t *testing.T) {
+	// Arrange
+	mock := &mockS3Service{corsRules: []service.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}}}
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = mock
+
+	// Act
+	req := httptest.NewRequest("POST", "/api/buckets/cors", bytes.NewBufferString(`{"bucket":"assets"}`))
+	w := httptest.NewRecorder()
+	handler.HandleBucketCors(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data service.BucketCors `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(response.Data.Rules) != 1 || response.Data.Rules[0].AllowedOrigins[0] != "*" {
+		t.Errorf("Expected one rule, got %+v", response.Data.Rules)
+	}
+
+	// Act
+	req = httptest.NewRequest("POST", "/api/buckets/cors/delete", bytes.NewBufferString(`{"bucket":"assets"}`))
+	w = httptest.NewRecorder()
+	handler.HandleBucketCorsDelete(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if mock.corsRules != nil {
+		t.Errorf("Expected rules to be removed, got %+v", mock.corsRules)
+	}
+}
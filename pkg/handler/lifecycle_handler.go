@@ -0,0 +1,370 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// S3 lifecycle configuration limits
+const (
+	maxLifecycleRules      = 1000
+	maxLifecycleRuleIDSize = 255
+	minInfrequentAccessAge = 30 // days before STANDARD_IA and ONEZONE_IA transitions
+)
+
+// transitionStorageClasses are the storage classes lifecycle rules can move objects to
+var transitionStorageClasses = []string{"STANDARD_IA", "ONEZONE_IA", "INTELLIGENT_TIERING", "GLACIER_IR", "GLACIER", "DEEP_ARCHIVE"}
+
+// BucketLifecycleRequest identifies the bucket whose lifecycle rules are read or removed
+type BucketLifecycleRequest struct {
+	Bucket string `json:"bucket"`
+}
+
+// UpdateBucketLifecycleRequest represents the request for replacing the lifecycle rules of a bucket
+type UpdateBucketLifecycleRequest struct {
+	Bucket string                  `json:"bucket"`
+	Rules  []service.LifecycleRule `json:"rules"`
+}
+
+// HandleBucketLifecycle handles POST /api/buckets/lifecycle
+func (h *APIHandler) HandleBucketLifecycle(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "get_bucket_lifecycle", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketLifecycleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	lifecycle, err := h.s3Service.GetBucketLifecycle(ctx, req.Bucket)
+	if err != nil {
+		opLogger.Error("Failed to get bucket lifecycle", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      lifecycle,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketLifecycleUpdate handles POST /api/buckets/lifecycle/update
+func (h *APIHandler) HandleBucketLifecycleUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_bucket_lifecycle", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req UpdateBucketLifecycleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateLifecycleRules(req.Rules); err != nil {
+		opLogger.Warn("Rejected invalid lifecycle rules", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.s3Service.PutBucketLifecycle(ctx, req.Bucket, req.Rules); err != nil {
+		opLogger.Error("Failed to update bucket lifecycle", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated bucket lifecycle", "bucketName", req.Bucket, "ruleCount", len(req.Rules))
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Bucket lifecycle rules updated successfully",
+			"bucket":  req.Bucket,
+			"rules":   req.Rules,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketLifecycleDelete handles POST /api/buckets/lifecycle/delete
+func (h *APIHandler) HandleBucketLifecycleDelete(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "delete_bucket_lifecycle", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BucketLifecycleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := h.s3Service.DeleteBucketLifecycle(ctx, req.Bucket); err != nil {
+		opLogger.Error("Failed to delete bucket lifecycle", "error", err, "bucketName", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Deleted bucket lifecycle", "bucketName", req.Bucket)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Bucket lifecycle rules deleted successfully",
+			"bucket":  req.Bucket,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleBucketLifecyclePreview handles POST /api/buckets/lifecycle/preview
+//
+// Only the rule filter has to be complete, so a rule can be previewed while it
+// is still being edited.
+func (h *APIHandler) HandleBucketLifecyclePreview(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "preview_lifecycle_rule", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req service.LifecyclePreviewInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateLifecycleFilter("rule", req.Rule); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	// Tag filters read the tags of every candidate object
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.PreviewLifecycleRule(r.Context(), req)
+	if err != nil {
+		opLogger.Error("Failed to preview lifecycle rule", "error", err, "bucket", req.Bucket, "prefix", req.Prefix)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      output,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// validateLifecycleRules checks a rule list against the S3 lifecycle configuration rules
+func validateLifecycleRules(rules []service.LifecycleRule) error {
+	if len(rules) == 0 {
+		return s3cerrors.NewMissingFieldError("rules").
+			WithSuggestion("Add at least one rule, or delete the lifecycle configuration instead")
+	}
+	if len(rules) > maxLifecycleRules {
+		return s3cerrors.NewValidationError(s3cerrors.CodeOutOfRange, fmt.Sprintf("Buckets can have at most %d lifecycle rules", maxLifecycleRules)).
+			WithDetails(map[string]any{"field": "rules", "count": len(rules)})
+	}
+
+	ids := make(map[string]bool)
+	for i, rule := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+
+		if len(rule.ID) > maxLifecycleRuleIDSize {
+			return s3cerrors.NewInvalidInputError(field+".id", rule.ID).
+				WithSuggestion(fmt.Sprintf("Rule IDs can be at most %d characters long", maxLifecycleRuleIDSize))
+		}
+		if rule.ID != "" {
+			if ids[rule.ID] {
+				return s3cerrors.NewInvalidInputError(field+".id", rule.ID).
+					WithSuggestion("Rule IDs must be unique within the bucket")
+			}
+			ids[rule.ID] = true
+		}
+
+		if err := validateLifecycleFilter(field, rule); err != nil {
+			return err
+		}
+		if err := validateLifecycleActions(field, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateLifecycleFilter checks the tag and size conditions of a rule
+func validateLifecycleFilter(field string, rule service.LifecycleRule) error {
+	if err := validateTags(rule.Tags); err != nil {
+		return err
+	}
+	if rule.ObjectSizeGreaterThan < 0 || rule.ObjectSizeLessThan < 0 {
+		return s3cerrors.NewInvalidInputError(field+".objectSize", "sizes cannot be negative")
+	}
+	if rule.ObjectSizeGreaterThan > 0 && rule.ObjectSizeLessThan > 0 && rule.ObjectSizeGreaterThan >= rule.ObjectSizeLessThan {
+		return s3cerrors.NewInvalidInputError(field+".objectSizeLessThan", rule.ObjectSizeLessThan).
+			WithSuggestion("The upper size bound must be greater than the lower one")
+	}
+	return nil
+}
+
+// validateLifecycleActions checks that a rule has at least one action and that
+// each action is well-formed
+func validateLifecycleActions(field string, rule service.LifecycleRule) error {
+	if rule.Expiration == nil && len(rule.Transitions) == 0 && rule.NoncurrentVersionExpiration == nil &&
+		len(rule.NoncurrentVersionTransitions) == 0 && rule.AbortIncompleteMultipartUploadDays == 0 {
+		return s3cerrors.NewMissingFieldError(field + ".expiration").
+			WithSuggestion("Rules need an expiration, transition, noncurrent version or incomplete upload action")
+	}
+
+	var lastTransitionDays int32
+	for i, transition := range rule.Transitions {
+		transitionField := fmt.Sprintf("%s.transitions[%d]", field, i)
+		if err := validateTransitionStorageClass(transitionField, transition.StorageClass, transition.Days, transition.Date != nil); err != nil {
+			return err
+		}
+		if transition.Date == nil {
+			if transition.Days < 0 {
+				return s3cerrors.NewInvalidInputError(transitionField+".days", transition.Days)
+			}
+			lastTransitionDays = max(lastTransitionDays, transition.Days)
+		}
+	}
+
+	if expiration := rule.Expiration; expiration != nil {
+		set := 0
+		for _, ok := range []bool{expiration.Days != 0, expiration.Date != nil, expiration.ExpiredObjectDeleteMarker} {
+			if ok {
+				set++
+			}
+		}
+		switch {
+		case set != 1:
+			return s3cerrors.NewInvalidInputError(field+".expiration", "set exactly one of days, date or expiredObjectDeleteMarker")
+		case expiration.Days < 0:
+			return s3cerrors.NewInvalidInputError(field+".expiration.days", expiration.Days)
+		case expiration.ExpiredObjectDeleteMarker && len(rule.Tags) > 0:
+			return s3cerrors.NewInvalidInputError(field+".expiration.expiredObjectDeleteMarker", "cannot be used with a tag filter")
+		case expiration.Days > 0 && expiration.Days <= lastTransitionDays:
+			return s3cerrors.NewInvalidInputError(field+".expiration.days", expiration.Days).
+				WithSuggestion("Objects must expire after their last transition")
+		}
+	}
+
+	if expiration := rule.NoncurrentVersionExpiration; expiration != nil {
+		if expiration.NoncurrentDays < 1 {
+			return s3cerrors.NewInvalidInputError(field+".noncurrentVersionExpiration.noncurrentDays", expiration.NoncurrentDays).
+				WithSuggestion("Noncurrent versions expire at least one day after they stop being current")
+		}
+		if expiration.NewerNoncurrentVersions < 0 {
+			return s3cerrors.NewInvalidInputError(field+".noncurrentVersionExpiration.newerNoncurrentVersions", expiration.NewerNoncurrentVersions)
+		}
+	}
+	for i, transition := range rule.NoncurrentVersionTransitions {
+		transitionField := fmt.Sprintf("%s.noncurrentVersionTransitions[%d]", field, i)
+		if transition.NoncurrentDays < 1 {
+			return s3cerrors.NewInvalidInputError(transitionField+".noncurrentDays", transition.NoncurrentDays)
+		}
+		if err := validateTransitionStorageClass(transitionField, transition.StorageClass, transition.NoncurrentDays, false); err != nil {
+			return err
+		}
+	}
+
+	if days := rule.AbortIncompleteMultipartUploadDays; days != 0 {
+		switch {
+		case days < 0:
+			return s3cerrors.NewInvalidInputError(field+".abortIncompleteMultipartUploadDays", days)
+		case len(rule.Tags) > 0:
+			// Uploads in progress have no tags, so S3 refuses the combination
+			return s3cerrors.NewInvalidInputError(field+".abortIncompleteMultipartUploadDays", "cannot be used with a tag filter")
+		}
+	}
+	return nil
+}
+
+// validateTransitionStorageClass checks the target class of a transition and its minimum age
+func validateTransitionStorageClass(field, storageClass string, days int32, hasDate bool) error {
+	if !slices.Contains(transitionStorageClasses, storageClass) {
+		return s3cerrors.NewInvalidInputError(field+".storageClass", storageClass).
+			WithSuggestion("Transitions can target STANDARD_IA, ONEZONE_IA, INTELLIGENT_TIERING, GLACIER_IR, GLACIER or DEEP_ARCHIVE")
+	}
+	if !hasDate && (storageClass == "STANDARD_IA" || storageClass == "ONEZONE_IA") && days < minInfrequentAccessAge {
+		return s3cerrors.NewInvalidInputError(field+".days", days).
+			WithSuggestion(fmt.Sprintf("Objects must be at least %d days old to move to %s", minInfrequentAccessAge, storageClass))
+	}
+	return nil
+}
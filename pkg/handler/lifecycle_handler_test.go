@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestValidateLifecycleRules(t *testing.T) {
+	date := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	valid := service.LifecycleRule{
+		ID:          "logs",
+		Enabled:     true,
+		Prefix:      "logs/",
+		Transitions: []service.LifecycleTransition{{Days: 30, StorageClass: "STANDARD_IA"}, {Days: 90, StorageClass: "GLACIER"}},
+		Expiration:  &service.LifecycleExpiration{Days: 365},
+	}
+	with := func(change func(rule *service.LifecycleRule)) []service.LifecycleRule {
+		rule := valid
+		rule.ID = "changed"
+		change(&rule)
+		return []service.LifecycleRule{valid, rule}
+	}
+
+	tests := []struct {
+		name          string
+		rules         []service.LifecycleRule
+		expectedCode  s3cerrors.ErrorCode
+		expectedField string
+	}{
+		{
+			name:  "valid transition and expiration",
+			rules: []service.LifecycleRule{valid},
+		},
+		{
+			name: "valid version cleanup rule",
+			rules: []service.LifecycleRule{{
+				Enabled:                            true,
+				NoncurrentVersionExpiration:        &service.NoncurrentVersionExpiration{NoncurrentDays: 30, NewerNoncurrentVersions: 3},
+				NoncurrentVersionTransitions:       []service.NoncurrentVersionTransition{{NoncurrentDays: 7, StorageClass: "GLACIER_IR"}},
+				AbortIncompleteMultipartUploadDays: 7,
+				Expiration:                         &service.LifecycleExpiration{ExpiredObjectDeleteMarker: true},
+			}},
+		},
+		{
+			name: "valid tag filter with expiration date",
+			rules: with(func(rule *service.LifecycleRule) {
+				rule.Tags = map[string]string{"temp": "true"}
+				rule.Transitions = nil
+				rule.Expiration = &service.LifecycleExpiration{Date: &date}
+			}),
+		},
+		{
+			name:          "no rules",
+			expectedCode:  s3cerrors.CodeMissingField,
+			expectedField: "rules",
+		},
+		{
+			name:          "duplicate id",
+			rules:         []service.LifecycleRule{valid, valid},
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].id",
+		},
+		{
+			name:          "no actions",
+			rules:         with(func(rule *service.LifecycleRule) { rule.Transitions = nil; rule.Expiration = nil }),
+			expectedCode:  s3cerrors.CodeMissingField,
+			expectedField: "rules[1].expiration",
+		},
+		{
+			name: "unknown storage class",
+			rules: with(func(rule *service.LifecycleRule) {
+				rule.Transitions = []service.LifecycleTransition{{Days: 30, StorageClass: "COLD"}}
+			}),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].transitions[0].storageClass",
+		},
+		{
+			name: "infrequent access too early",
+			rules: with(func(rule *service.LifecycleRule) {
+				rule.Transitions = []service.LifecycleTransition{{Days: 7, StorageClass: "STANDARD_IA"}}
+			}),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].transitions[0].days",
+		},
+		{
+			name:          "expiration before last transition",
+			rules:         with(func(rule *service.LifecycleRule) { rule.Expiration = &service.LifecycleExpiration{Days: 60} }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].expiration.days",
+		},
+		{
+			name: "expiration with days and date",
+			rules: with(func(rule *service.LifecycleRule) {
+				rule.Expiration = &service.LifecycleExpiration{Days: 400, Date: &date}
+			}),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].expiration",
+		},
+		{
+			name: "noncurrent expiration without days",
+			rules: with(func(rule *service.LifecycleRule) {
+				rule.NoncurrentVersionExpiration = &service.NoncurrentVersionExpiration{}
+			}),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].noncurrentVersionExpiration.noncurrentDays",
+		},
+		{
+			name: "abort uploads with tag filter",
+			rules: with(func(rule *service.LifecycleRule) {
+				rule.Tags = map[string]string{"temp": "true"}
+				rule.AbortIncompleteMultipartUploadDays = 7
+			}),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].abortIncompleteMultipartUploadDays",
+		},
+		{
+			name: "inverted size range",
+			rules: with(func(rule *service.LifecycleRule) {
+				rule.ObjectSizeGreaterThan = 1024
+				rule.ObjectSizeLessThan = 512
+			}),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "rules[1].objectSizeLessThan",
+		},
+		{
+			name:          "reserved tag key",
+			rules:         with(func(rule *service.LifecycleRule) { rule.Tags = map[string]string{"aws:createdBy": "me"} }),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedField: "tags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := validateLifecycleRules(tt.rules)
+
+			// Assert
+			if tt.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("Expected valid rules, got %v", err)
+				}
+				return
+			}
+
+			var s3cErr *s3cerrors.S3CError
+			if !errors.As(err, &s3cErr) {
+				t.Fatalf("Expected S3CError, got %v", err)
+			}
+			if s3cErr.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, s3cErr.Code)
+			}
+			details := s3cErr.Details.(map[string]any)
+			if details["field"] != tt.expectedField {
+				t.Errorf("Expected field %q, got %v", tt.expectedField, details["field"])
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketLifecycleUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		lifecycleErr   error
+		expectedStatus int
+		expectStored   bool
+	}{
+		{
+			name:           "valid rules are stored",
+			body:           `{"bucket":"logs","rules":[{"id":"expire-tmp","enabled":true,"prefix":"tmp/","expiration":{"days":7}}]}`,
+			expectedStatus: http.StatusOK,
+			expectStored:   true,
+		},
+		{
+			name:           "rule without action is not sent",
+			body:           `{"bucket":"logs","rules":[{"id":"noop","enabled":true,"prefix":"tmp/"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"rules":[{"enabled":true,"expiration":{"days":7}}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "access denied",
+			body:           `{"bucket":"logs","rules":[{"enabled":true,"expiration":{"days":7}}]}`,
+			lifecycleErr:   s3cerrors.NewS3AccessDeniedError("put bucket lifecycle", "logs"),
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{lifecycleErr: tt.lifecycleErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			req := httptest.NewRequest("POST", "/api/buckets/lifecycle/update", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketLifecycleUpdate(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if stored := len(mock.lifecycleRules) > 0; stored != tt.expectStored {
+				t.Errorf("Expected stored=%v, got rules %+v", tt.expectStored, mock.lifecycleRules)
+			}
+			if tt.expectStored && mock.lifecycleRules[0].Expiration.Days != 7 {
+				t.Errorf("Expected expiration to reach the service, got %+v", mock.lifecycleRules[0])
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleBucketLifecyclePreview(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedKeys   []string
+	}{
+		{
+			name:           "rule filter is matched against the listing",
+			body:           `{"bucket":"logs","prefix":"app/","rule":{"prefix":"app/2024"}}`,
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"app/2024-01.log", "app/2024-02.log"},
+		},
+		{
+			name:           "incomplete rule can be previewed",
+			body:           `{"bucket":"logs","rule":{"prefix":"app/"}}`,
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"app/2024-01.log", "app/2024-02.log", "app/2025-01.log"},
+		},
+		{
+			name:           "invalid tag filter",
+			body:           `{"bucket":"logs","rule":{"tags":{"aws:x":"y"}}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"rule":{"prefix":"app/"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{listObjectsResult: &service.ListObjectsOutput{Objects: []service.S3Object{
+				{Key: "app/2024-01.log", Size: 10},
+				{Key: "app/2024-02.log", Size: 20},
+				{Key: "app/2025-01.log", Size: 30},
+			}}}
+
+			req := httptest.NewRequest("POST", "/api/buckets/lifecycle/preview", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketLifecyclePreview(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data service.LifecyclePreviewOutput `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if len(response.Data.Matched) != len(tt.expectedKeys) {
+				t.Fatalf("Expected %d matches, got %+v", len(tt.expectedKeys), response.Data.Matched)
+			}
+			for i, key := range tt.expectedKeys {
+				if response.Data.Matched[i].Key != key {
+					t.Errorf("Expected match %d to be %s, got %s", i, key, response.Data.Matched[i].Key)
+				}
+			}
+		})
+	}
+}
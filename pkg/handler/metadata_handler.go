@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// HeadObjectRequest represents the request for inspecting an object
+type HeadObjectRequest struct {
+	Bucket         string `json:"bucket"`
+	Key            string `json:"key"`
+	VersionID      string `json:"versionId,omitempty"`
+	SSECustomerKey string `json:"sseCustomerKey,omitempty"` // required for SSE-C objects
+}
+
+// UpdateMetadataRequest represents the request for editing object headers and metadata.
+// Header fields that are omitted keep their current value.
+type UpdateMetadataRequest struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key,omitempty"`    // single object
+	Prefix string `json:"prefix,omitempty"` // every object under a folder
+	service.MetadataUpdate
+}
+
+// HandleObjectsHead handles POST /api/objects/head
+func (h *APIHandler) HandleObjectsHead(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req HeadObjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Validate request
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if req.Key == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("key")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateSSECustomerKey("sseCustomerKey", req.SSECustomerKey); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	details, err := h.s3Service.HeadObject(ctx, service.HeadObjectInput{
+		Bucket:         req.Bucket,
+		Key:            req.Key,
+		VersionID:      req.VersionID,
+		SSECustomerKey: req.SSECustomerKey,
+	})
+	if err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      details,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectsMetadata handles POST /api/objects/metadata
+//
+// Objects are copied onto themselves with replaced headers, so nothing is re-uploaded.
+func (h *APIHandler) HandleObjectsMetadata(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_metadata", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req UpdateMetadataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Validate request
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if (req.Key == "") == (req.Prefix == "") {
+		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Exactly one of key or prefix is required")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if isEmptyMetadataUpdate(req.MetadataUpdate) {
+		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "No metadata changes requested")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateTags(req.Tags); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	// Prefixes may cover many objects, so lift the server deadlines
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.UpdateMetadata(r.Context(), service.UpdateMetadataInput{
+		Bucket: req.Bucket,
+		Key:    req.Key,
+		Prefix: req.Prefix,
+		Update: req.MetadataUpdate,
+	})
+	if err != nil {
+		opLogger.Error("Failed to update metadata", "error", err, "bucket", req.Bucket, "key", req.Key, "prefix", req.Prefix)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated object metadata",
+		"bucket", req.Bucket,
+		"key", req.Key,
+		"prefix", req.Prefix,
+		"updated", len(output.Updated),
+		"failed", len(output.Failed),
+	)
+
+	failures := make([]BatchItemError, len(output.Failed))
+	for i, failed := range output.Failed {
+		failures[i] = BatchItemError(failed)
+	}
+
+	data := map[string]any{
+		"bucket":      req.Bucket,
+		"updatedKeys": output.Updated,
+	}
+	h.writeBatchResponse(w, requestID, data, len(output.Updated), failures, "Updated")
+}
+
+// isEmptyMetadataUpdate reports whether an update would leave objects unchanged
+func isEmptyMetadataUpdate(update service.MetadataUpdate) bool {
+	return update.ContentType == nil &&
+		update.ContentEncoding == nil &&
+		update.ContentDisposition == nil &&
+		update.ContentLanguage == nil &&
+		update.CacheControl == nil &&
+		update.WebsiteRedirectLocation == nil &&
+		update.Metadata == nil &&
+		update.StorageClass == "" &&
+		update.ServerSideEncryption == "" &&
+		update.SSEKMSKeyID == "" &&
+		update.Tags == nil
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectsHead(t *testing.T) {
+	details := &service.ObjectDetails{
+		Bucket:               "test-bucket",
+		Key:                  "docs/report.pdf",
+		Size:                 2048,
+		ETag:                 `"abc123"`,
+		ContentType:          "application/pdf",
+		CacheControl:         "max-age=3600",
+		StorageClass:         "STANDARD_IA",
+		ServerSideEncryption: "aws:kms",
+		SSEKMSKeyID:          "arn:aws:kms:us-east-1:123456789012:key/test",
+		Checksums:            &service.ObjectChecksums{CRC32: "AAAAAA=="},
+		ObjectLock:           &service.ObjectLockState{Mode: "GOVERNANCE", LegalHold: "OFF"},
+		Metadata:             map[string]string{"original-filename": "report.pdf"},
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    HeadObjectRequest
+		headErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "inspect object",
+			requestBody:    HeadObjectRequest{Bucket: "test-bucket", Key: "docs/report.pdf"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "object not found",
+			requestBody:    HeadObjectRequest{Bucket: "test-bucket", Key: "missing.txt"},
+			headErr:        s3cerrors.NewS3ObjectNotFoundError("test-bucket", "missing.txt"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing key",
+			requestBody:    HeadObjectRequest{Bucket: "test-bucket"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			requestBody:    HeadObjectRequest{Key: "docs/report.pdf"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{headResult: details, headErr: tt.headErr}
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("POST", "/api/objects/head", bytes.NewBuffer(body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsHead(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data service.ObjectDetails `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			got := response.Data
+			if got.ContentType != "application/pdf" || got.StorageClass != "STANDARD_IA" || got.SSEKMSKeyID == "" {
+				t.Errorf("Unexpected object details: %+v", got)
+			}
+			if got.Checksums == nil || got.Checksums.CRC32 != "AAAAAA==" {
+				t.Errorf("Expected CRC32 checksum, got %+v", got.Checksums)
+			}
+			if got.ObjectLock == nil || got.ObjectLock.Mode != "GOVERNANCE" {
+				t.Errorf("Expected object lock state, got %+v", got.ObjectLock)
+			}
+			if got.Metadata["original-filename"] != "report.pdf" {
+				t.Errorf("Expected user metadata, got %v", got.Metadata)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsMetadata(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		output         *service.UpdateMetadataOutput
+		serviceErr     error
+		expectedStatus int
+		checkInput     func(t *testing.T, input *service.UpdateMetadataInput)
+	}{
+		{
+			name:           "fix content type on a single object",
+			body:           `{"bucket":"test-bucket","key":"index.html","contentType":"text/html","cacheControl":""}`,
+			expectedStatus: http.StatusOK,
+			checkInput: func(t *testing.T, input *service.UpdateMetadataInput) {
+				if input.Key != "index.html" || input.Update.ContentType == nil || *input.Update.ContentType != "text/html" {
+					t.Errorf("Expected content type update for index.html, got %+v", input)
+				}
+				if input.Update.CacheControl == nil || *input.Update.CacheControl != "" {
+					t.Error("Expected empty cache control to request header removal")
+				}
+				if input.Update.ContentEncoding != nil || input.Update.Metadata != nil || input.Update.Tags != nil {
+					t.Errorf("Expected omitted fields to stay unset, got %+v", input.Update)
+				}
+			},
+		},
+		{
+			name: "prefix with partial failure",
+			body: `{"bucket":"test-bucket","prefix":"assets/","cacheControl":"max-age=86400"}`,
+			output: &service.UpdateMetadataOutput{
+				Updated: []string{"assets/app.js"},
+				Failed:  []service.ObjectError{{Key: "assets/app.css", Code: "S3_ACCESS_DENIED", Message: "Access denied"}},
+			},
+			expectedStatus: http.StatusPartialContent,
+			checkInput: func(t *testing.T, input *service.UpdateMetadataInput) {
+				if input.Prefix != "assets/" || input.Key != "" {
+					t.Errorf("Expected prefix update, got %+v", input)
+				}
+			},
+		},
+		{
+			name:           "object not found",
+			body:           `{"bucket":"test-bucket","key":"missing.txt","contentType":"text/plain"}`,
+			serviceErr:     s3cerrors.NewS3ObjectNotFoundError("test-bucket", "missing.txt"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "no changes requested",
+			body:           `{"bucket":"test-bucket","key":"index.html"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "key and prefix together",
+			body:           `{"bucket":"test-bucket","key":"index.html","prefix":"assets/","contentType":"text/html"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"key":"index.html","contentType":"text/html"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{metadataOutput: tt.output, metadataErr: tt.serviceErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			req := httptest.NewRequest("POST", "/api/objects/metadata", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsMetadata(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.checkInput != nil {
+				tt.checkInput(t, mockService.metadataInput)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// ObjectLockRequest identifies the object whose retention or legal hold is read
+type ObjectLockRequest struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+// UpdateObjectRetentionRequest represents the request for changing the retention of one object
+type UpdateObjectRetentionRequest struct {
+	Bucket          string `json:"bucket"`
+	Key             string `json:"key"`
+	VersionID       string `json:"versionId,omitempty"`
+	Mode            string `json:"mode,omitempty"`            // GOVERNANCE or COMPLIANCE, empty to remove the retention
+	RetainUntilDate string `json:"retainUntilDate,omitempty"` // RFC3339, in the future
+
+	// BypassGovernanceRetention must be set explicitly to shorten or remove GOVERNANCE retention
+	BypassGovernanceRetention bool `json:"bypassGovernanceRetention,omitempty"`
+}
+
+// UpdateObjectLegalHoldRequest represents the request for placing or removing a legal hold
+type UpdateObjectLegalHoldRequest struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"`
+	Status    string `json:"status"` // ON or OFF
+}
+
+// HandleObjectRetention handles POST /api/objects/retention
+func (h *APIHandler) HandleObjectRetention(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ObjectLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectTarget(req.Bucket, req.Key); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	retention, err := h.s3Service.GetObjectRetention(ctx, service.ObjectLockInput{
+		Bucket:    req.Bucket,
+		Key:       req.Key,
+		VersionID: req.VersionID,
+	})
+	if err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      retention,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectRetentionUpdate handles POST /api/objects/retention/update
+//
+// Retention can always be extended. Shortening or removing it is only attempted for
+// GOVERNANCE retention, and only when the request opts in to the governance bypass.
+func (h *APIHandler) HandleObjectRetentionUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_object_retention", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req UpdateObjectRetentionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	retainUntil, err := validateRetentionRequest(req, time.Now())
+	if err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err = h.s3Service.PutObjectRetention(ctx, service.PutObjectRetentionInput{
+		Bucket:                    req.Bucket,
+		Key:                       req.Key,
+		VersionID:                 req.VersionID,
+		Mode:                      req.Mode,
+		RetainUntilDate:           retainUntil,
+		BypassGovernanceRetention: req.BypassGovernanceRetention,
+	})
+	if err != nil {
+		opLogger.Error("Failed to update object retention", "error", err, "bucket", req.Bucket, "key", req.Key)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated object retention",
+		"bucket", req.Bucket,
+		"key", req.Key,
+		"versionId", req.VersionID,
+		"mode", req.Mode,
+		"retainUntilDate", req.RetainUntilDate,
+		"bypassGovernance", req.BypassGovernanceRetention,
+	)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message":         "Object retention updated successfully",
+			"bucket":          req.Bucket,
+			"key":             req.Key,
+			"versionId":       req.VersionID,
+			"mode":            req.Mode,
+			"retainUntilDate": req.RetainUntilDate,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectLegalHold handles POST /api/objects/legal-hold
+func (h *APIHandler) HandleObjectLegalHold(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ObjectLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectTarget(req.Bucket, req.Key); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	hold, err := h.s3Service.GetObjectLegalHold(ctx, service.ObjectLockInput{
+		Bucket:    req.Bucket,
+		Key:       req.Key,
+		VersionID: req.VersionID,
+	})
+	if err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      hold,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectLegalHoldUpdate handles POST /api/objects/legal-hold/update
+func (h *APIHandler) HandleObjectLegalHoldUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_object_legal_hold", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req UpdateObjectLegalHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectTarget(req.Bucket, req.Key); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+	if req.Status != service.LegalHoldOn && req.Status != service.LegalHoldOff {
+		s3cErr := s3cerrors.NewInvalidInputError("status", req.Status).
+			WithSuggestion("Use ON or OFF")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := h.s3Service.PutObjectLegalHold(ctx, service.PutObjectLegalHoldInput{
+		Bucket:    req.Bucket,
+		Key:       req.Key,
+		VersionID: req.VersionID,
+		Status:    req.Status,
+	})
+	if err != nil {
+		opLogger.Error("Failed to update object legal hold", "error", err, "bucket", req.Bucket, "key", req.Key)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated object legal hold", "bucket", req.Bucket, "key", req.Key, "versionId", req.VersionID, "status", req.Status)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message":   "Object legal hold updated successfully",
+			"bucket":    req.Bucket,
+			"key":       req.Key,
+			"versionId": req.VersionID,
+			"status":    req.Status,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// validateRetentionRequest checks the target, mode and date of a retention change and
+// returns the parsed retain-until date, zero when the retention is removed
+func validateRetentionRequest(req UpdateObjectRetentionRequest, now time.Time) (time.Time, error) {
+	if err := validateObjectTarget(req.Bucket, req.Key); err != nil {
+		return time.Time{}, err
+	}
+
+	switch req.Mode {
+	case "":
+		if req.RetainUntilDate != "" {
+			return time.Time{}, s3cerrors.NewMissingFieldError("mode")
+		}
+		if !req.BypassGovernanceRetention {
+			return time.Time{}, s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Removing retention requires the governance bypass").
+				WithDetails(map[string]any{"field": "bypassGovernanceRetention"}).
+				WithSuggestion("Confirm the governance bypass to remove GOVERNANCE retention. COMPLIANCE retention cannot be removed")
+		}
+		return time.Time{}, nil
+	case service.RetentionModeGovernance:
+	case service.RetentionModeCompliance:
+		if req.BypassGovernanceRetention {
+			return time.Time{}, s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "The governance bypass does not apply to COMPLIANCE retention").
+				WithDetails(map[string]any{"field": "bypassGovernanceRetention"})
+		}
+	default:
+		return time.Time{}, s3cerrors.NewInvalidInputError("mode", req.Mode).
+			WithSuggestion("Use GOVERNANCE or COMPLIANCE")
+	}
+
+	if req.RetainUntilDate == "" {
+		return time.Time{}, s3cerrors.NewMissingFieldError("retainUntilDate")
+	}
+	retainUntil, err := time.Parse(time.RFC3339, req.RetainUntilDate)
+	if err != nil {
+		return time.Time{}, s3cerrors.NewInvalidInputError("retainUntilDate", req.RetainUntilDate).
+			WithSuggestion("Use an RFC3339 date, e.g. 2030-01-01T00:00:00Z")
+	}
+	if !retainUntil.After(now) {
+		return time.Time{}, s3cerrors.NewInvalidInputError("retainUntilDate", req.RetainUntilDate).
+			WithSuggestion("The retain-until date must be in the future")
+	}
+	return retainUntil, nil
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectRetention(t *testing.T) {
+	// Arrange
+	mock := &mockS3Service{retention: &service.ObjectRetention{
+		Bucket:          "records",
+		Key:             "2024/ledger.csv",
+		Mode:            service.RetentionModeGovernance,
+		RetainUntilDate: "2030-01-01T00:00:00Z",
+		BucketDefault:   &service.BucketObjectLock{Enabled: true, Mode: service.RetentionModeCompliance, Years: 7},
+	}}
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = mock
+
+	req := httptest.NewRequest("POST", "/api/objects/retention", bytes.NewBufferString(`{"bucket":"records","key":"2024/ledger.csv"}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.HandleObjectRetention(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data service.ObjectRetention `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response.Data.Mode != service.RetentionModeGovernance || response.Data.RetainUntilDate != "2030-01-01T00:00:00Z" {
+		t.Errorf("Unexpected retention: %+v", response.Data)
+	}
+	if response.Data.BucketDefault == nil || response.Data.BucketDefault.Years != 7 {
+		t.Errorf("Expected the bucket default retention, got %+v", response.Data.BucketDefault)
+	}
+}
+
+func TestAPIHandler_HandleObjectRetentionUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		lockErr        error
+		expectedStatus int
+		expectCalled   bool
+		expectBypass   bool
+	}{
+		{
+			name:           "governance retention",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"GOVERNANCE","retainUntilDate":"2099-01-01T00:00:00Z"}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name:           "compliance retention on a version",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","versionId":"v1","mode":"COMPLIANCE","retainUntilDate":"2099-01-01T00:00:00Z"}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name:           "shorten governance retention with bypass",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"GOVERNANCE","retainUntilDate":"2099-01-01T00:00:00Z","bypassGovernanceRetention":true}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+			expectBypass:   true,
+		},
+		{
+			name:           "remove retention with bypass",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","bypassGovernanceRetention":true}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+			expectBypass:   true,
+		},
+		{
+			name:           "remove retention without bypass",
+			body:           `{"bucket":"records","key":"2024/ledger.csv"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "bypass with compliance",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"COMPLIANCE","retainUntilDate":"2099-01-01T00:00:00Z","bypassGovernanceRetention":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "shortening refused by S3",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"COMPLIANCE","retainUntilDate":"2099-01-01T00:00:00Z"}`,
+			lockErr:        s3cerrors.NewS3ObjectLockedError("records", "2024/ledger.csv"),
+			expectedStatus: http.StatusConflict,
+			expectCalled:   true,
+		},
+		{
+			name:           "date in the past",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"GOVERNANCE","retainUntilDate":"2001-01-01T00:00:00Z"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "date not RFC3339",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"GOVERNANCE","retainUntilDate":"2099-01-01"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing date",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"GOVERNANCE"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown mode",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","mode":"LEGAL","retainUntilDate":"2099-01-01T00:00:00Z"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing key",
+			body:           `{"bucket":"records","mode":"GOVERNANCE","retainUntilDate":"2099-01-01T00:00:00Z"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{objectLockErr: tt.lockErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			req := httptest.NewRequest("POST", "/api/objects/retention/update", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectRetentionUpdate(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if called := mock.retentionInput != nil; called != tt.expectCalled {
+				t.Fatalf("Expected service called=%v, got input %+v", tt.expectCalled, mock.retentionInput)
+			}
+			if tt.expectCalled && mock.retentionInput.BypassGovernanceRetention != tt.expectBypass {
+				t.Errorf("Expected governance bypass %v, got %v", tt.expectBypass, mock.retentionInput.BypassGovernanceRetention)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectLegalHoldUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectCalled   bool
+	}{
+		{
+			name:           "place legal hold",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","status":"ON"}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name:           "remove legal hold from a version",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","versionId":"v1","status":"OFF"}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name:           "lower case status",
+			body:           `{"bucket":"records","key":"2024/ledger.csv","status":"on"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing status",
+			body:           `{"bucket":"records","key":"2024/ledger.csv"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"key":"2024/ledger.csv","status":"ON"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			req := httptest.NewRequest("POST", "/api/objects/legal-hold/update", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectLegalHoldUpdate(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if called := mock.legalHoldInput != nil; called != tt.expectCalled {
+				t.Fatalf("Expected service called=%v, got input %+v", tt.expectCalled, mock.legalHoldInput)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsDelete_Locked(t *testing.T) {
+	// Arrange
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = &mockS3Service{deleteObjectErr: s3cerrors.NewS3ObjectLockedError("records", "2024/ledger.csv")}
+
+	req := httptest.NewRequest("POST", "/api/objects/delete", bytes.NewBufferString(`{"bucket":"records","keys":["2024/ledger.csv"]}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.HandleObjectsDelete(w, req)
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(s3cerrors.CodeS3ObjectLocked)) {
+		t.Errorf("Expected the locked error code in the response, got %s", w.Body.String())
+	}
+}
+
+func TestAPIHandler_HandleBucketDelete_GovernanceBypass(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectBypass   bool
+	}{
+		{
+			name:           "bypass while emptying",
+			body:           `{"name":"records","confirmName":"records","empty":true,"bypassGovernanceRetention":true}`,
+			expectedStatus: http.StatusOK,
+			expectBypass:   true,
+		},
+		{
+			name:           "emptying without bypass",
+			body:           `{"name":"records","confirmName":"records","empty":true}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bypass without emptying",
+			body:           `{"name":"records","confirmName":"records","bypassGovernanceRetention":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			req := httptest.NewRequest("POST", "/api/buckets/delete", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleBucketDelete(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				if mock.deleteBucketInput != nil {
+					t.Fatalf("Expected no service call, got input %+v", mock.deleteBucketInput)
+				}
+				return
+			}
+			if mock.deleteBucketInput.BypassGovernanceRetention != tt.expectBypass {
+				t.Errorf("Expected governance bypass %v, got %v", tt.expectBypass, mock.deleteBucketInput.BypassGovernanceRetention)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// PresignObjectRequest represents the request for a presigned link or upload policy
+type PresignObjectRequest struct {
+	Bucket    string `json:"bucket"`
+	Method    string `json:"method,omitempty"`    // GET (default), PUT or POST
+	Key       string `json:"key,omitempty"`       // GET and PUT
+	KeyPrefix string `json:"keyPrefix,omitempty"` // POST, uploads are restricted to this prefix
+	VersionID string `json:"versionId,omitempty"` // GET
+	ExpiresIn int64  `json:"expiresIn,omitempty"` // seconds, one hour when omitted
+
+	ContentDisposition string `json:"contentDisposition,omitempty"` // GET, e.g. attachment; filename="report.pdf"
+	ContentType        string `json:"contentType,omitempty"`        // PUT and POST, uploads must use this type
+	MinContentLength   int64  `json:"minContentLength,omitempty"`   // POST
+	MaxContentLength   int64  `json:"maxContentLength,omitempty"`   // POST, no limit when omitted
+}
+
+// PresignPostResponse is a POST policy along with ready-to-use upload snippets
+type PresignPostResponse struct {
+	*service.PresignedPost
+	Curl     string `json:"curl"`
+	HTMLForm string `json:"htmlForm"`
+}
+
+// HandleObjectsPresign handles POST /api/objects/presign
+func (h *APIHandler) HandleObjectsPresign(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "presign_object", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req PresignObjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		opLogger.Error("Failed to decode presign request", "error", err)
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+
+	// Validate request
+	if err := validatePresignRequest(req); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+	expires, err := presignExpiry(req.ExpiresIn)
+	if err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var data any
+	switch req.Method {
+	case http.MethodGet:
+		data, err = h.s3Service.PresignGetObject(ctx, service.PresignGetObjectInput{
+			Bucket:             req.Bucket,
+			Key:                req.Key,
+			VersionID:          req.VersionID,
+			Expires:            expires,
+			ContentDisposition: req.ContentDisposition,
+		})
+	case http.MethodPut:
+		data, err = h.s3Service.PresignPutObject(ctx, service.PresignPutObjectInput{
+			Bucket:      req.Bucket,
+			Key:         req.Key,
+			Expires:     expires,
+			ContentType: req.ContentType,
+		})
+	case http.MethodPost:
+		var post *service.PresignedPost
+		post, err = h.s3Service.PresignPostObject(ctx, service.PresignPostObjectInput{
+			Bucket:           req.Bucket,
+			KeyPrefix:        req.KeyPrefix,
+			Expires:          expires,
+			ContentType:      req.ContentType,
+			MinContentLength: req.MinContentLength,
+			MaxContentLength: req.MaxContentLength,
+		})
+		if err == nil {
+			data = PresignPostResponse{
+				PresignedPost: post,
+				Curl:          postPolicyCurl(post),
+				HTMLForm:      postPolicyHTMLForm(post),
+			}
+		}
+	}
+	if err != nil {
+		opLogger.Error("Failed to presign object", "error", err, "method", req.Method, "bucket", req.Bucket, "key", req.Key, "keyPrefix", req.KeyPrefix)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Presigned object request", "method", req.Method, "bucket", req.Bucket, "key", req.Key, "keyPrefix", req.KeyPrefix, "expiresIn", expires)
+
+	response := APIResponse{
+		Success:   true,
+		Data:      data,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// validatePresignRequest checks the fields each presign method needs
+func validatePresignRequest(req PresignObjectRequest) error {
+	if req.Bucket == "" {
+		return s3cerrors.NewMissingFieldError("bucket")
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodPut:
+		if req.Key == "" {
+			return s3cerrors.NewMissingFieldError("key")
+		}
+	case http.MethodPost:
+		// An empty prefix would open the whole bucket to uploads
+		if req.KeyPrefix == "" {
+			return s3cerrors.NewMissingFieldError("keyPrefix")
+		}
+		if req.MinContentLength < 0 || req.MaxContentLength < 0 ||
+			(req.MaxContentLength > 0 && req.MinContentLength > req.MaxContentLength) {
+			return s3cerrors.NewInvalidInputError("maxContentLength", req.MaxContentLength).
+				WithSuggestion("The size limits must be positive, with minContentLength not above maxContentLength")
+		}
+		if req.MinContentLength > 0 && req.MaxContentLength == 0 {
+			return s3cerrors.NewMissingFieldError("maxContentLength")
+		}
+	default:
+		return s3cerrors.NewInvalidInputError("method", "must be GET, PUT or POST")
+	}
+	return nil
+}
+
+// postPolicyCurl renders a POST policy as a curl command, with the file field last as S3 requires
+func postPolicyCurl(post *service.PresignedPost) string {
+	var b strings.Builder
+	b.WriteString("curl")
+	for _, name := range slices.Sorted(maps.Keys(post.Fields)) {
+		fmt.Fprintf(&b, " -F %s", shellQuote(name+"="+post.Fields[name]))
+	}
+	fmt.Fprintf(&b, " -F 'file=@<path-to-file>' %s", shellQuote(post.URL))
+	return b.String()
+}
+
+// postPolicyHTMLForm renders a POST policy as an HTML upload form
+func postPolicyHTMLForm(post *service.PresignedPost) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<form action=\"%s\" method=\"post\" enctype=\"multipart/form-data\">\n", html.EscapeString(post.URL))
+	for _, name := range slices.Sorted(maps.Keys(post.Fields)) {
+		fmt.Fprintf(&b, "  <input type=\"hidden\" name=\"%s\" value=\"%s\">\n", html.EscapeString(name), html.EscapeString(post.Fields[name]))
+	}
+	b.WriteString("  <input type=\"file\" name=\"file\">\n")
+	b.WriteString("  <input type=\"submit\" value=\"Upload\">\n")
+	b.WriteString("</form>\n")
+	return b.String()
+}
+
+// shellQuote wraps a value in single quotes for POSIX shells
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// presignExpiry converts a requested lifetime in seconds, zero meaning the default
+func presignExpiry(seconds int64) (time.Duration, error) {
+	maxSeconds := int64(service.MaxPresignExpiry / time.Second)
+	if seconds < 0 || seconds > maxSeconds {
+		return 0, s3cerrors.NewInvalidInputError("expiresIn", seconds).
+			WithSuggestion(fmt.Sprintf("Presigned URLs can be valid for 1 to %d seconds (7 days)", maxSeconds))
+	}
+	if seconds == 0 {
+		return service.DefaultPresignExpiry, nil
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
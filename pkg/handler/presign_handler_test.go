@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectsPresign(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedExpiry time.Duration
+	}{
+		{
+			name:           "default expiry",
+			body:           `{"bucket":"test-bucket","key":"docs/report.pdf"}`,
+			expectedStatus: http.StatusOK,
+			expectedExpiry: service.DefaultPresignExpiry,
+		},
+		{
+			name:           "custom expiry with version and disposition",
+			body:           `{"bucket":"test-bucket","key":"docs/report.pdf","versionId":"v1","expiresIn":900,"contentDisposition":"attachment; filename=\"report.pdf\""}`,
+			expectedStatus: http.StatusOK,
+			expectedExpiry: 15 * time.Minute,
+		},
+		{
+			name:           "maximum expiry",
+			body:           `{"bucket":"test-bucket","key":"docs/report.pdf","expiresIn":604800}`,
+			expectedStatus: http.StatusOK,
+			expectedExpiry: service.MaxPresignExpiry,
+		},
+		{
+			name:           "expiry beyond seven days",
+			body:           `{"bucket":"test-bucket","key":"docs/report.pdf","expiresIn":604801}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative expiry",
+			body:           `{"bucket":"test-bucket","key":"docs/report.pdf","expiresIn":-1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing key",
+			body:           `{"bucket":"test-bucket"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			req := httptest.NewRequest("POST", "/api/objects/presign", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsPresign(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				if mockService.presignInput != nil {
+					t.Error("Expected no URL to be presigned")
+				}
+				return
+			}
+			if mockService.presignInput.Expires != tt.expectedExpiry {
+				t.Errorf("Expected expiry %v, got %v", tt.expectedExpiry, mockService.presignInput.Expires)
+			}
+
+			var response struct {
+				Data service.PresignedURL `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Data.URL == "" || response.Data.ExpiresAt.IsZero() {
+				t.Errorf("Expected URL and expiry time, got %+v", response.Data)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsPresign_Upload(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		check          func(t *testing.T, m *mockS3Service, body []byte)
+	}{
+		{
+			name:           "PUT URL with content type",
+			body:           `{"bucket":"test-bucket","method":"PUT","key":"inbox/data.csv","contentType":"text/csv","expiresIn":600}`,
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, m *mockS3Service, body []byte) {
+				if m.presignPutInput == nil || m.presignPutInput.ContentType != "text/csv" || m.presignPutInput.Expires != 10*time.Minute {
+					t.Errorf("Unexpected PUT presign input: %+v", m.presignPutInput)
+				}
+			},
+		},
+		{
+			name:           "POST policy",
+			body:           `{"bucket":"test-bucket","method":"POST","keyPrefix":"partner/","contentType":"application/pdf","maxContentLength":10485760}`,
+			expectedStatus: http.StatusOK,
+			check: func(t *testing.T, m *mockS3Service, body []byte) {
+				input := m.presignPostInput
+				if input == nil || input.KeyPrefix != "partner/" || input.MaxContentLength != 10485760 || input.ContentType != "application/pdf" {
+					t.Errorf("Unexpected POST presign input: %+v", input)
+				}
+
+				var response struct {
+					Data PresignPostResponse `json:"data"`
+				}
+				if err := json.Unmarshal(body, &response); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				if response.Data.PresignedPost == nil || response.Data.Fields["key"] != "partner/${filename}" {
+					t.Errorf("Expected form fields in response, got %+v", response.Data.PresignedPost)
+				}
+				if !strings.Contains(response.Data.Curl, "-F 'key=partner/${filename}'") {
+					t.Errorf("Expected curl command with key field, got %s", response.Data.Curl)
+				}
+				if !strings.Contains(response.Data.HTMLForm, `<input type="file" name="file">`) {
+					t.Errorf("Expected HTML form with file input, got %s", response.Data.HTMLForm)
+				}
+			},
+		},
+		{
+			name:           "POST without key prefix",
+			body:           `{"bucket":"test-bucket","method":"POST"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "POST with inverted size range",
+			body:           `{"bucket":"test-bucket","method":"POST","keyPrefix":"partner/","minContentLength":100,"maxContentLength":10}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "PUT without key",
+			body:           `{"bucket":"test-bucket","method":"PUT"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported method",
+			body:           `{"bucket":"test-bucket","method":"DELETE","key":"inbox/data.csv"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			req := httptest.NewRequest("POST", "/api/objects/presign", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsPresign(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.check != nil {
+				tt.check(t, mockService, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestPostPolicySnippets(t *testing.T) {
+	post := &service.PresignedPost{
+		URL: "http://localhost:4566/test-bucket",
+		Fields: map[string]string{
+			"key":    "it's/${filename}",
+			"policy": "abc=",
+		},
+	}
+
+	wantCurl := `curl -F 'key=it'\''s/${filename}' -F 'policy=abc=' -F 'file=@<path-to-file>' 'http://localhost:4566/test-bucket'`
+	if got := postPolicyCurl(post); got != wantCurl {
+		t.Errorf("postPolicyCurl() = %s, want %s", got, wantCurl)
+	}
+
+	form := postPolicyHTMLForm(post)
+	if !strings.Contains(form, `<input type="hidden" name="key" value="it&#39;s/${filename}">`) {
+		t.Errorf("Expected escaped hidden key field, got %s", form)
+	}
+	if strings.Index(form, `name="policy"`) > strings.Index(form, `name="file"`) {
+		t.Error("Expected the file input after all policy fields")
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// restoreTiers are the retrieval tiers S3 accepts for archived objects
+var restoreTiers = []string{service.RestoreTierExpedited, service.RestoreTierStandard, service.RestoreTierBulk}
+
+// RestoreObjectRequest represents the request for restoring archived objects
+type RestoreObjectRequest struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key,omitempty"`       // single object
+	VersionID string `json:"versionId,omitempty"` // single object only
+	Prefix    string `json:"prefix,omitempty"`    // every object under a folder
+	Days      int32  `json:"days"`
+	Tier      string `json:"tier,omitempty"` // Standard when omitted
+}
+
+// HandleObjectsRestore handles POST /api/objects/restore
+//
+// Restores run in the background on S3, so this only starts them. Their progress
+// shows up in the restore status of object details and listings.
+func (h *APIHandler) HandleObjectsRestore(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "restore_objects", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req RestoreObjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateRestoreRequest(req); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	// Prefixes may cover many objects, so lift the server deadlines
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.RestoreObject(r.Context(), service.RestoreObjectInput{
+		Bucket:    req.Bucket,
+		Key:       req.Key,
+		VersionID: req.VersionID,
+		Prefix:    req.Prefix,
+		Days:      req.Days,
+		Tier:      req.Tier,
+	})
+	if err != nil {
+		opLogger.Error("Failed to restore objects", "error", err, "bucket", req.Bucket, "key", req.Key, "prefix", req.Prefix)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Requested object restores",
+		"bucket", req.Bucket,
+		"key", req.Key,
+		"prefix", req.Prefix,
+		"tier", req.Tier,
+		"days", req.Days,
+		"requested", len(output.Requested),
+		"inProgress", len(output.InProgress),
+		"skipped", len(output.Skipped),
+		"failed", len(output.Failed),
+	)
+
+	failures := make([]BatchItemError, len(output.Failed))
+	for i, failed := range output.Failed {
+		failures[i] = BatchItemError(failed)
+	}
+
+	data := map[string]any{
+		"bucket":         req.Bucket,
+		"requestedKeys":  output.Requested,
+		"inProgressKeys": output.InProgress,
+		"skippedKeys":    output.Skipped,
+	}
+	succeeded := len(output.Requested) + len(output.InProgress) + len(output.Skipped)
+	h.writeBatchResponse(w, requestID, data, succeeded, failures, "Restored")
+}
+
+// validateRestoreRequest checks the target, tier and duration of a restore request
+func validateRestoreRequest(req RestoreObjectRequest) error {
+	if req.Bucket == "" {
+		return s3cerrors.NewMissingFieldError("bucket")
+	}
+	if (req.Key == "") == (req.Prefix == "") {
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Exactly one of key or prefix is required")
+	}
+	if req.VersionID != "" && req.Key == "" {
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "A version ID can only be given for a single object").
+			WithDetails(map[string]any{"field": "versionId"})
+	}
+	if req.Days < 1 {
+		return s3cerrors.NewInvalidInputError("days", req.Days).
+			WithSuggestion("Keep the restored copy for at least one day")
+	}
+	if req.Tier != "" && !slices.Contains(restoreTiers, req.Tier) {
+		return s3cerrors.NewInvalidInputError("tier", req.Tier).
+			WithSuggestion("Use Expedited, Standard or Bulk")
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectsRestore(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		output         *service.RestoreObjectOutput
+		restoreErr     error
+		expectedStatus int
+		expectCalled   bool
+	}{
+		{
+			name:           "single key with tier",
+			body:           `{"bucket":"archive","key":"2019/backup.tar","days":7,"tier":"Bulk"}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name: "prefix with objects already restoring",
+			body: `{"bucket":"archive","prefix":"2019/","days":3}`,
+			output: &service.RestoreObjectOutput{
+				Requested:  []string{"2019/a.tar"},
+				InProgress: []string{"2019/b.tar"},
+				Skipped:    []string{"2019/readme.txt"},
+			},
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name: "some keys fail",
+			body: `{"bucket":"archive","prefix":"2019/","days":3}`,
+			output: &service.RestoreObjectOutput{
+				Requested: []string{"2019/a.tar"},
+				Failed:    []service.ObjectError{{Key: "2019/b.tar", Code: "S3_ACCESS_DENIED", Message: "Access denied"}},
+			},
+			expectedStatus: http.StatusPartialContent,
+			expectCalled:   true,
+		},
+		{
+			name:           "access denied",
+			body:           `{"bucket":"archive","key":"2019/backup.tar","days":7}`,
+			restoreErr:     s3cerrors.NewS3AccessDeniedError("restore object", "archive"),
+			expectedStatus: http.StatusForbidden,
+			expectCalled:   true,
+		},
+		{
+			name:           "key and prefix together",
+			body:           `{"bucket":"archive","key":"2019/backup.tar","prefix":"2019/","days":7}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "version id with prefix",
+			body:           `{"bucket":"archive","prefix":"2019/","versionId":"v1","days":7}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing days",
+			body:           `{"bucket":"archive","key":"2019/backup.tar"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown tier",
+			body:           `{"bucket":"archive","key":"2019/backup.tar","days":7,"tier":"Instant"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"key":"2019/backup.tar","days":7}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{restoreOutput: tt.output, restoreErr: tt.restoreErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			req := httptest.NewRequest("POST", "/api/objects/restore", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsRestore(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if called := mock.restoreInput != nil; called != tt.expectCalled {
+				t.Fatalf("Expected service called=%v, got input %+v", tt.expectCalled, mock.restoreInput)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsDownload_Archived(t *testing.T) {
+	// Arrange
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = &mockS3Service{downloadErr: s3cerrors.NewS3ObjectArchivedError("archive", "2019/backup.tar")}
+
+	req := httptest.NewRequest("GET", "/api/objects/download?bucket=archive&key=2019/backup.tar", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.HandleObjectsDownload(w, req)
+
+	// Assert
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(s3cerrors.CodeS3ObjectArchived)) {
+		t.Errorf("Expected the archived error code in the response, got %s", w.Body.String())
+	}
+}
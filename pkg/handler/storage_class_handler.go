@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// objectStorageClasses are the storage classes an object can be written in
+var objectStorageClasses = []string{"STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA", "ONEZONE_IA", "INTELLIGENT_TIERING", "GLACIER_IR", "GLACIER", "DEEP_ARCHIVE"}
+
+// ChangeStorageClassRequest represents the request for moving objects to another storage class
+type ChangeStorageClassRequest struct {
+	Bucket       string   `json:"bucket"`
+	Keys         []string `json:"keys,omitempty"`   // selected objects
+	Prefix       string   `json:"prefix,omitempty"` // every object under a folder
+	StorageClass string   `json:"storageClass"`
+}
+
+// HandleObjectsStorageClass handles POST /api/objects/storage-class
+//
+// Objects are copied onto themselves in the new class, so nothing is re-uploaded.
+func (h *APIHandler) HandleObjectsStorageClass(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "change_storage_class", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ChangeStorageClassRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Validate request
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if (len(req.Keys) == 0) == (req.Prefix == "") {
+		s3cErr := s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Exactly one of keys or prefix is required")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if req.StorageClass == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("storageClass")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateStorageClass("storageClass", req.StorageClass); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	// Prefixes may cover many objects, so lift the server deadlines
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.ChangeStorageClass(r.Context(), service.ChangeStorageClassInput{
+		Bucket:       req.Bucket,
+		Keys:         req.Keys,
+		Prefix:       req.Prefix,
+		StorageClass: req.StorageClass,
+	})
+	if err != nil {
+		opLogger.Error("Failed to change storage class", "error", err, "bucket", req.Bucket, "prefix", req.Prefix)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Changed storage class",
+		"bucket", req.Bucket,
+		"prefix", req.Prefix,
+		"storageClass", req.StorageClass,
+		"changed", len(output.Changed),
+		"unchanged", len(output.Unchanged),
+		"failed", len(output.Failed),
+	)
+
+	failures := make([]BatchItemError, len(output.Failed))
+	for i, failed := range output.Failed {
+		failures[i] = BatchItemError(failed)
+	}
+
+	data := map[string]any{
+		"bucket":        req.Bucket,
+		"storageClass":  req.StorageClass,
+		"changedKeys":   output.Changed,
+		"unchangedKeys": output.Unchanged,
+	}
+	h.writeBatchResponse(w, requestID, data, len(output.Changed)+len(output.Unchanged), failures, "Changed")
+}
+
+// validateStorageClass checks that a storage class, when given, is one objects can be written in
+func validateStorageClass(field, storageClass string) error {
+	if storageClass == "" || slices.Contains(objectStorageClasses, storageClass) {
+		return nil
+	}
+	return s3cerrors.NewInvalidInputError(field, storageClass).
+		WithSuggestion("Use STANDARD, REDUCED_REDUNDANCY, STANDARD_IA, ONEZONE_IA, INTELLIGENT_TIERING, GLACIER_IR, GLACIER or DEEP_ARCHIVE")
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectsStorageClass(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		output         *service.ChangeStorageClassOutput
+		expectedStatus int
+		expectCalled   bool
+	}{
+		{
+			name:           "selected keys",
+			body:           `{"bucket":"logs","keys":["a.log","b.log"],"storageClass":"STANDARD_IA"}`,
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name:           "prefix with objects already in the class",
+			body:           `{"bucket":"logs","prefix":"2020/","storageClass":"GLACIER"}`,
+			output:         &service.ChangeStorageClassOutput{Changed: []string{"2020/a.log"}, Unchanged: []string{"2020/b.log"}},
+			expectedStatus: http.StatusOK,
+			expectCalled:   true,
+		},
+		{
+			name: "some keys fail",
+			body: `{"bucket":"logs","keys":["a.log","b.log"],"storageClass":"STANDARD"}`,
+			output: &service.ChangeStorageClassOutput{
+				Changed: []string{"a.log"},
+				Failed:  []service.ObjectError{{Key: "b.log", Code: "S3_ACCESS_DENIED", Message: "Access denied"}},
+			},
+			expectedStatus: http.StatusPartialContent,
+			expectCalled:   true,
+		},
+		{
+			name:           "keys and prefix together",
+			body:           `{"bucket":"logs","keys":["a.log"],"prefix":"2020/","storageClass":"GLACIER"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing storage class",
+			body:           `{"bucket":"logs","keys":["a.log"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown storage class",
+			body:           `{"bucket":"logs","keys":["a.log"],"storageClass":"COLD"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"keys":["a.log"],"storageClass":"GLACIER"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mock := &mockS3Service{storageClassOut: tt.output}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mock
+
+			req := httptest.NewRequest("POST", "/api/objects/storage-class", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsStorageClass(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if called := mock.storageClassInput != nil; called != tt.expectCalled {
+				t.Fatalf("Expected service called=%v, got input %+v", tt.expectCalled, mock.storageClassInput)
+			}
+			if !tt.expectCalled {
+				return
+			}
+
+			var response struct {
+				Data map[string]any `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Data["storageClass"] != mock.storageClassInput.StorageClass {
+				t.Errorf("Expected storage class %s in the response, got %v", mock.storageClassInput.StorageClass, response.Data["storageClass"])
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// S3 object tag limits
+const (
+	maxObjectTags     = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// ObjectTagsRequest identifies the object whose tags are read or removed
+type ObjectTagsRequest struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+// UpdateObjectTagsRequest represents the request for replacing the tags of one object
+type UpdateObjectTagsRequest struct {
+	Bucket    string            `json:"bucket"`
+	Key       string            `json:"key"`
+	VersionID string            `json:"versionId,omitempty"`
+	Tags      map[string]string `json:"tags"`
+}
+
+// BulkTagRequest represents the request for tagging every object under a prefix
+type BulkTagRequest struct {
+	Bucket string            `json:"bucket"`
+	Prefix string            `json:"prefix"`
+	Tags   map[string]string `json:"tags"`
+	Merge  bool              `json:"merge,omitempty"` // keep existing tags that are not overwritten
+}
+
+// HandleObjectTags handles POST /api/objects/tags
+func (h *APIHandler) HandleObjectTags(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ObjectTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectTarget(req.Bucket, req.Key); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tags, err := h.s3Service.GetObjectTagging(ctx, service.ObjectTaggingInput{
+		Bucket:    req.Bucket,
+		Key:       req.Key,
+		VersionID: req.VersionID,
+	})
+	if err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      tags,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectTagsUpdate handles POST /api/objects/tags/update
+func (h *APIHandler) HandleObjectTagsUpdate(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "update_object_tags", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req UpdateObjectTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectTarget(req.Bucket, req.Key); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+	if req.Tags == nil {
+		s3cErr := s3cerrors.NewMissingFieldError("tags")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateTags(req.Tags); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := h.s3Service.PutObjectTagging(ctx, service.PutObjectTaggingInput{
+		Bucket:    req.Bucket,
+		Key:       req.Key,
+		VersionID: req.VersionID,
+		Tags:      req.Tags,
+	})
+	if err != nil {
+		opLogger.Error("Failed to update object tags", "error", err, "bucket", req.Bucket, "key", req.Key)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Updated object tags", "bucket", req.Bucket, "key", req.Key, "tagCount", len(req.Tags))
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Object tags updated successfully",
+			"bucket":  req.Bucket,
+			"key":     req.Key,
+			"tags":    req.Tags,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectTagsDelete handles POST /api/objects/tags/delete
+func (h *APIHandler) HandleObjectTagsDelete(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "delete_object_tags", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ObjectTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectTarget(req.Bucket, req.Key); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := h.s3Service.DeleteObjectTagging(ctx, service.ObjectTaggingInput{
+		Bucket:    req.Bucket,
+		Key:       req.Key,
+		VersionID: req.VersionID,
+	})
+	if err != nil {
+		opLogger.Error("Failed to delete object tags", "error", err, "bucket", req.Bucket, "key", req.Key)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Deleted object tags", "bucket", req.Bucket, "key", req.Key)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message": "Object tags deleted successfully",
+			"bucket":  req.Bucket,
+			"key":     req.Key,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectTagsBulk handles POST /api/objects/tags/bulk
+func (h *APIHandler) HandleObjectTagsBulk(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "bulk_tag_objects", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req BulkTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Validate request
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if req.Prefix == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("prefix")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if len(req.Tags) == 0 {
+		s3cErr := s3cerrors.NewMissingFieldError("tags")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+	if err := validateTags(req.Tags); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	// Prefixes may cover many objects, so lift the server deadlines
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.TagPrefix(r.Context(), service.TagPrefixInput{
+		Bucket: req.Bucket,
+		Prefix: req.Prefix,
+		Tags:   req.Tags,
+		Merge:  req.Merge,
+	})
+	if err != nil {
+		opLogger.Error("Failed to tag objects", "error", err, "bucket", req.Bucket, "prefix", req.Prefix)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Tagged objects under prefix",
+		"bucket", req.Bucket,
+		"prefix", req.Prefix,
+		"tagged", len(output.Tagged),
+		"failed", len(output.Failed),
+	)
+
+	failures := make([]BatchItemError, len(output.Failed))
+	for i, failed := range output.Failed {
+		failures[i] = BatchItemError(failed)
+	}
+
+	data := map[string]any{
+		"bucket":     req.Bucket,
+		"taggedKeys": output.Tagged,
+	}
+	h.writeBatchResponse(w, requestID, data, len(output.Tagged), failures, "Tagged")
+}
+
+// validateObjectTarget checks the bucket and key of a single-object request
+func validateObjectTarget(bucket, key string) error {
+	if bucket == "" {
+		return s3cerrors.NewMissingFieldError("bucket")
+	}
+	if key == "" {
+		return s3cerrors.NewMissingFieldError("key")
+	}
+	return nil
+}
+
+// validateTags checks a tag set against the S3 object tagging limits
+func validateTags(tags map[string]string) error {
+	if len(tags) > maxObjectTags {
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, fmt.Sprintf("Objects can have at most %d tags", maxObjectTags)).
+			WithDetails(map[string]any{"field": "tags", "count": len(tags)})
+	}
+
+	for key, value := range tags {
+		switch {
+		case key == "" || utf8.RuneCountInString(key) > maxTagKeyLength:
+			return s3cerrors.NewInvalidInputError("tags", key).
+				WithSuggestion(fmt.Sprintf("Tag keys must be 1 to %d characters long", maxTagKeyLength))
+		case utf8.RuneCountInString(value) > maxTagValueLength:
+			return s3cerrors.NewInvalidInputError("tags", key).
+				WithSuggestion(fmt.Sprintf("Tag values can be at most %d characters long", maxTagValueLength))
+		case strings.HasPrefix(strings.ToLower(key), "aws:"):
+			return s3cerrors.NewInvalidInputError("tags", key).
+				WithSuggestion("The aws: prefix is reserved for AWS-generated tags")
+		case !isValidTagText(key) || !isValidTagText(value):
+			return s3cerrors.NewInvalidInputError("tags", key).
+				WithSuggestion("Tags may contain letters, numbers, spaces and + - = . _ : / @")
+		}
+	}
+	return nil
+}
+
+// isValidTagText reports whether s only uses characters S3 allows in tags
+func isValidTagText(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && !strings.ContainsRune("+-=._:/@", r) {
+			return false
+		}
+	}
+	return true
+}
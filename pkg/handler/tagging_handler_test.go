@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectTags(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		taggingErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "read tags",
+			body:           `{"bucket":"test-bucket","key":"logs/app.log"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "object not found",
+			body:           `{"bucket":"test-bucket","key":"missing.log"}`,
+			taggingErr:     s3cerrors.NewS3ObjectNotFoundError("test-bucket", "missing.log"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing key",
+			body:           `{"bucket":"test-bucket"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{
+				objectTags: map[string]map[string]string{"logs/app.log": {"team": "platform"}},
+				taggingErr: tt.taggingErr,
+			}
+
+			req := httptest.NewRequest("POST", "/api/objects/tags", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectTags(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data service.ObjectTags `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Data.Tags["team"] != "platform" {
+				t.Errorf("Expected team tag, got %v", response.Data.Tags)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectTagsUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "replace tags",
+			body:           `{"bucket":"test-bucket","key":"logs/app.log","tags":{"team":"data","cost-center":"42"}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "clear tags with empty set",
+			body:           `{"bucket":"test-bucket","key":"logs/app.log","tags":{}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing tags",
+			body:           `{"bucket":"test-bucket","key":"logs/app.log"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "reserved prefix",
+			body:           `{"bucket":"test-bucket","key":"logs/app.log","tags":{"aws:createdBy":"me"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			req := httptest.NewRequest("POST", "/api/objects/tags/update", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectTagsUpdate(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			_, updated := mockService.objectTags["logs/app.log"]
+			if updated != (tt.expectedStatus == http.StatusOK) {
+				t.Errorf("Expected tags to be written only on success, got %v", mockService.objectTags)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectTagsDelete(t *testing.T) {
+	// Arrange
+	mockService := &mockS3Service{objectTags: map[string]map[string]string{"logs/app.log": {"team": "platform"}}}
+	handler := NewAPIHandler(nil, nil, slog.Default())
+	handler.s3Service = mockService
+
+	req := httptest.NewRequest("POST", "/api/objects/tags/delete", bytes.NewBufferString(`{"bucket":"test-bucket","key":"logs/app.log"}`))
+	w := httptest.NewRecorder()
+
+	// Act
+	handler.HandleObjectTagsDelete(w, req)
+
+	// Assert
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if _, ok := mockService.objectTags["logs/app.log"]; ok {
+		t.Error("Expected tags to be removed")
+	}
+}
+
+func TestAPIHandler_HandleObjectTagsBulk(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		deniedKeys     map[string]bool
+		expectedStatus int
+		expectedTagged int
+	}{
+		{
+			name:           "tag every object under prefix",
+			body:           `{"bucket":"test-bucket","prefix":"logs/","tags":{"retention":"30d"},"merge":true}`,
+			expectedStatus: http.StatusOK,
+			expectedTagged: 2,
+		},
+		{
+			name:           "partial failure",
+			body:           `{"bucket":"test-bucket","prefix":"logs/","tags":{"retention":"30d"}}`,
+			deniedKeys:     map[string]bool{"logs/b.log": true},
+			expectedStatus: http.StatusPartialContent,
+			expectedTagged: 1,
+		},
+		{
+			name:           "missing prefix",
+			body:           `{"bucket":"test-bucket","tags":{"retention":"30d"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty tag set",
+			body:           `{"bucket":"test-bucket","prefix":"logs/","tags":{}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{prefixKeys: []string{"logs/a.log", "logs/b.log"}, deniedKeys: tt.deniedKeys}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			req := httptest.NewRequest("POST", "/api/objects/tags/bulk", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectTagsBulk(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusBadRequest {
+				return
+			}
+
+			var response struct {
+				Data struct {
+					TaggedKeys []string `json:"taggedKeys"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if len(response.Data.TaggedKeys) != tt.expectedTagged {
+				t.Errorf("Expected %d tagged keys, got %v", tt.expectedTagged, response.Data.TaggedKeys)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectsUpload_Tags(t *testing.T) {
+	tests := []struct {
+		name           string
+		uploads        string
+		expectedStatus int
+	}{
+		{
+			name:           "upload with tags",
+			uploads:        `[{"key":"reports/q1.csv","file":"file1","tags":{"project":"alpha"}}]`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid tag characters",
+			uploads:        `[{"key":"reports/q1.csv","file":"file1","tags":{"project":"a&b"}}]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{uploadResult: &service.UploadObjectOutput{Key: "reports/q1.csv"}}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			writer.WriteField("bucket", "test-bucket")
+			writer.WriteField("uploads", tt.uploads)
+			fileWriter, _ := writer.CreateFormFile("file1", "q1.csv")
+			fileWriter.Write([]byte("a,b\n"))
+			writer.Close()
+
+			req := httptest.NewRequest("POST", "/api/objects/upload", &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectsUpload(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && mockService.objectTags["reports/q1.csv"]["project"] != "alpha" {
+				t.Errorf("Expected tags to reach the upload, got %v", mockService.objectTags)
+			}
+		})
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tooMany := make(map[string]string)
+	for _, key := range strings.Split("a b c d e f g h i j k", " ") {
+		tooMany[key] = "v"
+	}
+
+	tests := []struct {
+		name    string
+		tags    map[string]string
+		wantErr bool
+	}{
+		{name: "nil tags", tags: nil},
+		{name: "allowed characters", tags: map[string]string{"cost-center": "team/data@eu +1", "名前": "値"}},
+		{name: "too many tags", tags: tooMany, wantErr: true},
+		{name: "empty key", tags: map[string]string{"": "v"}, wantErr: true},
+		{name: "key too long", tags: map[string]string{strings.Repeat("k", 129): "v"}, wantErr: true},
+		{name: "value too long", tags: map[string]string{"k": strings.Repeat("v", 257)}, wantErr: true},
+		{name: "reserved prefix", tags: map[string]string{"AWS:Owner": "v"}, wantErr: true},
+		{name: "invalid character", tags: map[string]string{"k": "a&b"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTags(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+// ListObjectVersionsRequest represents the request for listing object versions
+type ListObjectVersionsRequest struct {
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix,omitempty"`
+	Delimiter       string `json:"delimiter,omitempty"`
+	MaxKeys         int32  `json:"maxKeys,omitempty"`
+	KeyMarker       string `json:"keyMarker,omitempty"`
+	VersionIDMarker string `json:"versionIdMarker,omitempty"`
+}
+
+// ObjectVersionRequest identifies a single object version
+type ObjectVersionRequest struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"`
+
+	// Passphrase decrypts client-side encrypted versions, downloads only
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// HandleObjectVersionsList handles POST /api/objects/versions
+func (h *APIHandler) HandleObjectVersionsList(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "list_object_versions", "requestId", requestID)
+
+	if h.s3Service == nil {
+		opLogger.Warn("S3 service not configured")
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ListObjectVersionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		opLogger.Error("Failed to decode list object versions request", "error", err)
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Validate required fields
+	if req.Bucket == "" {
+		s3cErr := s3cerrors.NewMissingFieldError("bucket")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	// Page size follows the same limits as object listing
+	maxKeys := req.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = 100
+	}
+	if maxKeys > 1000 {
+		maxKeys = 1000
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := h.s3Service.ListObjectVersions(ctx, service.ListObjectVersionsInput{
+		Bucket:          req.Bucket,
+		Prefix:          req.Prefix,
+		Delimiter:       req.Delimiter,
+		MaxKeys:         maxKeys,
+		KeyMarker:       req.KeyMarker,
+		VersionIDMarker: req.VersionIDMarker,
+	})
+	if err != nil {
+		opLogger.Error("Failed to list object versions", "error", err, "bucket", req.Bucket)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	response := APIResponse{
+		Success:   true,
+		Data:      output,
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectVersionDownload handles GET and POST /api/objects/versions/download
+//
+// GET takes bucket, key and versionId query parameters so links and media
+// elements can fetch an old version, with the same Range support as downloads.
+func (h *APIHandler) HandleObjectVersionDownload(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ObjectVersionRequest
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req = ObjectVersionRequest{Bucket: query.Get("bucket"), Key: query.Get("key"), VersionID: query.Get("versionId")}
+		req.Passphrase = r.Header.Get(clientPassphraseHeader)
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectVersionRequest(req, true); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	// Downloads stream for as long as the client keeps reading
+	h.extendDeadlines(w)
+
+	h.downloadSingleFile(w, r.Context(), service.DownloadObjectInput{
+		Bucket:           req.Bucket,
+		Key:              req.Key,
+		VersionID:        req.VersionID,
+		ClientPassphrase: req.Passphrase,
+	}, r.Header, requestID)
+}
+
+// HandleObjectVersionRestore handles POST /api/objects/versions/restore
+//
+// The chosen version is copied over the current object, so it becomes the
+// latest version while the history stays intact.
+func (h *APIHandler) HandleObjectVersionRestore(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "restore_object_version", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ObjectVersionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectVersionRequest(req, true); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	// Versions over 5 GiB are copied in parts
+	h.extendDeadlines(w)
+
+	output, err := h.s3Service.RestoreObjectVersion(r.Context(), req.Bucket, req.Key, req.VersionID)
+	if err != nil {
+		opLogger.Error("Failed to restore object version", "error", err, "bucket", req.Bucket, "key", req.Key, "versionId", req.VersionID)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Restored object version", "bucket", req.Bucket, "key", req.Key, "versionId", req.VersionID)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message":           "Object version restored successfully",
+			"bucket":            req.Bucket,
+			"key":               req.Key,
+			"restoredVersionId": req.VersionID,
+			"object":            output,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// HandleObjectUndelete handles POST /api/objects/versions/undelete
+func (h *APIHandler) HandleObjectUndelete(w http.ResponseWriter, r *http.Request) {
+	requestID := generateRequestID()
+	opLogger := h.logger.With("operation", "undelete_object", "requestId", requestID)
+
+	if h.s3Service == nil {
+		s3cErr := s3cerrors.NewConfigError(s3cerrors.CodeConfigMissing, "S3 service not configured")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	var req ObjectVersionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s3cErr := s3cerrors.NewInvalidInputError("request body", "invalid JSON")
+		h.writeStructuredError(w, s3cErr, requestID)
+		return
+	}
+
+	if err := validateObjectVersionRequest(req, false); err != nil {
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	marker, err := h.s3Service.UndeleteObject(ctx, req.Bucket, req.Key)
+	if err != nil {
+		opLogger.Error("Failed to undelete object", "error", err, "bucket", req.Bucket, "key", req.Key)
+		h.writeStructuredError(w, err, requestID)
+		return
+	}
+
+	opLogger.Info("Undeleted object", "bucket", req.Bucket, "key", req.Key)
+
+	response := APIResponse{
+		Success: true,
+		Data: map[string]any{
+			"message":               "Object undeleted successfully",
+			"bucket":                req.Bucket,
+			"key":                   req.Key,
+			"removedDeleteMarkerId": marker.VersionID,
+		},
+		RequestID: requestID,
+	}
+
+	h.writeResponse(w, response)
+}
+
+// validateObjectVersionRequest checks the fields that identify an object version
+func validateObjectVersionRequest(req ObjectVersionRequest, requireVersion bool) error {
+	if req.Bucket == "" {
+		return s3cerrors.NewMissingFieldError("bucket")
+	}
+	if req.Key == "" {
+		return s3cerrors.NewMissingFieldError("key")
+	}
+	if requireVersion && req.VersionID == "" {
+		return s3cerrors.NewMissingFieldError("versionId")
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+	"github.com/tenkoh/s3c/pkg/service"
+)
+
+func TestAPIHandler_HandleObjectVersionsList(t *testing.T) {
+	versions := &service.ListObjectVersionsOutput{
+		Versions: []service.ObjectVersion{
+			{Key: "report.txt", VersionID: "v3", IsLatest: true, IsDeleteMarker: true},
+			{Key: "report.txt", VersionID: "v2", Size: 12},
+		},
+		CommonPrefixes: []string{},
+		IsTruncated:    true,
+		NextKeyMarker:  "report.txt",
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "list versions",
+			body:           `{"bucket":"test-bucket","prefix":"report"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bucket not found",
+			body:           `{"bucket":"missing-bucket"}`,
+			serviceErr:     s3cerrors.NewS3BucketNotFoundError("missing-bucket"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing bucket",
+			body:           `{"prefix":"report"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"bucket":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{versionsResult: versions, versionErr: tt.serviceErr}
+
+			req := httptest.NewRequest("POST", "/api/objects/versions", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectVersionsList(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data service.ListObjectVersionsOutput `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if len(response.Data.Versions) != 2 || !response.Data.Versions[0].IsDeleteMarker {
+				t.Errorf("Expected delete marker followed by older version, got %+v", response.Data.Versions)
+			}
+			if !response.Data.IsTruncated || response.Data.NextKeyMarker != "report.txt" {
+				t.Errorf("Expected pagination markers, got %+v", response.Data)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectVersionDownload(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "download version via GET",
+			method:         "GET",
+			target:         "/api/objects/versions/download?bucket=test-bucket&key=report.txt&versionId=v2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "download version via POST",
+			method:         "POST",
+			target:         "/api/objects/versions/download",
+			body:           `{"bucket":"test-bucket","key":"report.txt","versionId":"v2"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing version ID",
+			method:         "GET",
+			target:         "/api/objects/versions/download?bucket=test-bucket&key=report.txt",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing key",
+			method:         "POST",
+			target:         "/api/objects/versions/download",
+			body:           `{"bucket":"test-bucket","versionId":"v2"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var requested service.DownloadObjectInput
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{downloadFunc: func(input service.DownloadObjectInput) (*service.DownloadObjectOutput, error) {
+				requested = input
+				return &service.DownloadObjectOutput{
+					Body:          io.NopCloser(strings.NewReader("old content")),
+					ContentType:   "text/plain",
+					ContentLength: 11,
+				}, nil
+			}}
+
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.target, body)
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectVersionDownload(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if requested.VersionID != "v2" || requested.Key != "report.txt" {
+				t.Errorf("Expected version v2 of report.txt to be requested, got %+v", requested)
+			}
+			if w.Body.String() != "old content" {
+				t.Errorf("Expected version content, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectVersionRestore(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "restore version",
+			body:           `{"bucket":"test-bucket","key":"report.txt","versionId":"v1"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "version not found",
+			body:           `{"bucket":"test-bucket","key":"report.txt","versionId":"gone"}`,
+			serviceErr:     s3cerrors.NewS3ObjectNotFoundError("test-bucket", "report.txt"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "missing version ID",
+			body:           `{"bucket":"test-bucket","key":"report.txt"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := &mockS3Service{versionErr: tt.serviceErr}
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = mockService
+
+			req := httptest.NewRequest("POST", "/api/objects/versions/restore", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectVersionRestore(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && mockService.restoredVersion != "v1" {
+				t.Errorf("Expected version v1 to be restored, got %q", mockService.restoredVersion)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_HandleObjectUndelete(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "undelete object",
+			body:           `{"bucket":"test-bucket","key":"report.txt"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "object is not deleted",
+			body:           `{"bucket":"test-bucket","key":"report.txt"}`,
+			serviceErr:     s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Object is not deleted"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing key",
+			body:           `{"bucket":"test-bucket"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAPIHandler(nil, nil, slog.Default())
+			handler.s3Service = &mockS3Service{versionErr: tt.serviceErr}
+
+			req := httptest.NewRequest("POST", "/api/objects/versions/undelete", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			// Act
+			handler.HandleObjectUndelete(w, req)
+
+			// Assert
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if !strings.Contains(w.Body.String(), `"removedDeleteMarkerId":"marker-1"`) {
+				t.Errorf("Expected removed delete marker ID in response, got %s", w.Body.String())
+			}
+		})
+	}
+}
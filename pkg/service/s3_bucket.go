@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// DeleteBucketInput represents input for deleting a bucket
+type DeleteBucketInput struct {
+	Bucket string `json:"bucket"`
+	Empty  bool   `json:"empty,omitempty"` // remove all contents before deleting the bucket
+
+	// BypassGovernanceRetention also removes versions under GOVERNANCE retention while
+	// emptying. It needs the s3:BypassGovernanceRetention permission and never applies
+	// to COMPLIANCE retention or legal holds.
+	BypassGovernanceRetention bool `json:"bypassGovernanceRetention,omitempty"`
+}
+
+// DeleteBucketOutput reports what was removed while deleting a bucket
+type DeleteBucketOutput struct {
+	Bucket          string `json:"bucket"`
+	DeletedObjects  int    `json:"deletedObjects"` // object versions and delete markers
+	AbortedUploads  int    `json:"abortedUploads"`
+	VersionsSkipped bool   `json:"versionsSkipped,omitempty"` // the endpoint does not support version listing
+}
+
+// DeleteBucket deletes a bucket. With Empty set, in-progress multipart uploads are aborted and
+// every object version and delete marker is removed first, since S3 only deletes empty buckets.
+func (s *AWSS3Service) DeleteBucket(ctx context.Context, input DeleteBucketInput) (*DeleteBucketOutput, error) {
+	s.logger.Debug("Deleting S3 bucket", "bucketName", input.Bucket, "empty", input.Empty)
+
+	output := &DeleteBucketOutput{Bucket: input.Bucket}
+
+	if input.Empty {
+		if err := s.emptyBucket(ctx, input.Bucket, input.BypassGovernanceRetention, output); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := s.client.DeleteBucket(ctx, &s3.DeleteBucketInput{
+		Bucket: aws.String(input.Bucket),
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete S3 bucket", "error", err, "bucketName", input.Bucket)
+		return nil, convertS3Error("delete bucket", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket":         input.Bucket,
+				"deletedObjects": output.DeletedObjects,
+				"abortedUploads": output.AbortedUploads,
+			})
+	}
+
+	s.logger.Info("Successfully deleted S3 bucket",
+		"bucketName", input.Bucket,
+		"deletedObjects", output.DeletedObjects,
+		"abortedUploads", output.AbortedUploads,
+	)
+	return output, nil
+}
+
+// Bucket versioning states. A bucket that never had versioning enabled
+// reports no status at all; it can be suspended but never unversioned again.
+const (
+	VersioningEnabled     = "Enabled"
+	VersioningSuspended   = "Suspended"
+	VersioningUnversioned = "Unversioned"
+)
+
+// BucketVersioning represents the versioning configuration of a bucket
+type BucketVersioning struct {
+	Bucket    string `json:"bucket"`
+	Status    string `json:"status"`              // Enabled, Suspended or Unversioned
+	MFADelete string `json:"mfaDelete,omitempty"` // Enabled or Disabled, empty if never configured
+}
+
+// PutBucketVersioningInput represents input for changing bucket versioning
+type PutBucketVersioningInput struct {
+	Bucket    string `json:"bucket"`
+	Status    string `json:"status"`              // Enabled or Suspended
+	MFADelete string `json:"mfaDelete,omitempty"` // Enabled or Disabled, leave empty to keep it unchanged
+	MFA       string `json:"mfa,omitempty"`       // device serial number and code, required to change MFA delete
+}
+
+// GetBucketVersioning returns the versioning state and MFA delete status of a bucket
+func (s *AWSS3Service) GetBucketVersioning(ctx context.Context, bucket string) (*BucketVersioning, error) {
+	s.logger.Debug("Getting S3 bucket versioning", "bucketName", bucket)
+
+	result, err := s.client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		s.logger.Error("Failed to get S3 bucket versioning", "error", err, "bucketName", bucket)
+		return nil, convertS3Error("get bucket versioning", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{"bucket": bucket})
+	}
+
+	status := string(result.Status)
+	if status == "" {
+		status = VersioningUnversioned
+	}
+
+	return &BucketVersioning{
+		Bucket:    bucket,
+		Status:    status,
+		MFADelete: string(result.MFADelete),
+	}, nil
+}
+
+// PutBucketVersioning enables or suspends versioning on a bucket
+func (s *AWSS3Service) PutBucketVersioning(ctx context.Context, input PutBucketVersioningInput) error {
+	s.logger.Debug("Updating S3 bucket versioning",
+		"bucketName", input.Bucket,
+		"status", input.Status,
+		"mfaDelete", input.MFADelete,
+	)
+
+	s3Input := &s3.PutBucketVersioningInput{
+		Bucket: aws.String(input.Bucket),
+		VersioningConfiguration: &types.VersioningConfiguration{
+			Status: types.BucketVersioningStatus(input.Status),
+		},
+	}
+	if input.MFADelete != "" {
+		s3Input.VersioningConfiguration.MFADelete = types.MFADelete(input.MFADelete)
+		s3Input.MFA = aws.String(input.MFA)
+	}
+
+	_, err := s.client.PutBucketVersioning(ctx, s3Input)
+	if err != nil {
+		s.logger.Error("Failed to update S3 bucket versioning", "error", err, "bucketName", input.Bucket)
+		return convertS3Error("update bucket versioning", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket":    input.Bucket,
+				"status":    input.Status,
+				"mfaDelete": input.MFADelete,
+			})
+	}
+
+	s.logger.Info("Successfully updated S3 bucket versioning", "bucketName", input.Bucket, "status", input.Status)
+	return nil
+}
+
+// emptyBucket aborts multipart uploads and deletes all object versions and delete markers
+func (s *AWSS3Service) emptyBucket(ctx context.Context, bucket string, bypassGovernance bool, output *DeleteBucketOutput) error {
+	aborted, err := s.abortMultipartUploads(ctx, bucket)
+	output.AbortedUploads = aborted
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.deleteAllVersions(ctx, bucket, bypassGovernance)
+	if isNotImplemented(err) {
+		// Some S3-compatible services cannot list versions, which also means they keep none
+		s.logger.Warn("Object versions are not supported, deleting current objects only", "bucketName", bucket)
+		output.VersionsSkipped = true
+		deleted, err = s.deleteAllObjects(ctx, bucket)
+	}
+	output.DeletedObjects = deleted
+	return err
+}
+
+// abortMultipartUploads aborts every in-progress multipart upload in the bucket
+func (s *AWSS3Service) abortMultipartUploads(ctx context.Context, bucket string) (int, error) {
+	aborted := 0
+	listInput := &s3.ListMultipartUploadsInput{Bucket: aws.String(bucket)}
+
+	for {
+		page, err := s.client.ListMultipartUploads(ctx, listInput)
+		if err != nil {
+			return aborted, convertS3Error("list multipart uploads", err).(*s3cerrors.S3CError).
+				WithDetails(map[string]any{"bucket": bucket})
+		}
+
+		for _, upload := range page.Uploads {
+			_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(bucket),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+			if err != nil {
+				return aborted, convertS3Error("abort multipart upload", err).(*s3cerrors.S3CError).
+					WithDetails(map[string]any{
+						"bucket":   bucket,
+						"key":      aws.ToString(upload.Key),
+						"uploadId": aws.ToString(upload.UploadId),
+					})
+			}
+			aborted++
+		}
+
+		if !aws.ToBool(page.IsTruncated) {
+			return aborted, nil
+		}
+		listInput.KeyMarker = page.NextKeyMarker
+		listInput.UploadIdMarker = page.NextUploadIdMarker
+	}
+}
+
+// deleteAllVersions deletes every object version and delete marker in the bucket
+func (s *AWSS3Service) deleteAllVersions(ctx context.Context, bucket string, bypassGovernance bool) (int, error) {
+	deleted := 0
+	paginator := s3.NewListObjectVersionsPaginator(s.client, &s3.ListObjectVersionsInput{
+		Bucket:  aws.String(bucket),
+		MaxKeys: aws.Int32(maxDeleteBatchSize),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return deleted, convertS3Error("list object versions", err).(*s3cerrors.S3CError).
+				WithDetails(map[string]any{"bucket": bucket})
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, len(page.Versions)+len(page.DeleteMarkers))
+		for _, version := range page.Versions {
+			objects = append(objects, types.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range page.DeleteMarkers {
+			objects = append(objects, types.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+
+		removed, failed, err := s.deleteObjectIdentifiers(ctx, bucket, objects, bypassGovernance)
+		deleted += len(removed)
+		if err != nil {
+			return deleted, err
+		}
+		if len(failed) > 0 {
+			return deleted, bucketNotEmptyError(bucket, failed)
+		}
+	}
+	return deleted, nil
+}
+
+// deleteAllObjects deletes every current object in the bucket
+func (s *AWSS3Service) deleteAllObjects(ctx context.Context, bucket string) (int, error) {
+	deleted := 0
+	err := s.walkPrefix(ctx, bucket, "", func(keys []string) error {
+		removed, failed, err := s.deleteKeys(ctx, bucket, keys)
+		deleted += len(removed)
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			return bucketNotEmptyError(bucket, failed)
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// bucketNotEmptyError reports the objects that stopped a bucket from being emptied
+func bucketNotEmptyError(bucket string, failed []ObjectError) error {
+	locked := slices.IndexFunc(failed, func(failure ObjectError) bool {
+		return failure.Code == string(s3cerrors.CodeS3ObjectLocked)
+	})
+	if locked >= 0 {
+		return s3cerrors.NewS3ObjectLockedError(bucket, failed[locked].Key).
+			WithDetails(map[string]any{
+				"bucket": bucket,
+				"key":    failed[locked].Key,
+				"failed": failed,
+			})
+	}
+
+	return s3cerrors.NewS3BucketNotEmptyError(bucket).
+		WithDetails(map[string]any{
+			"bucket": bucket,
+			"failed": failed,
+		}).
+		WithSuggestion("Some objects could not be deleted, check object lock settings and your permissions")
+}
+
+// isNotImplemented reports whether an S3-compatible endpoint rejected an unsupported API
+func isNotImplemented(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "NotImplemented")
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// CORSRule represents one cross-origin access rule of a bucket
+type CORSRule struct {
+	ID             string   `json:"id,omitempty"`
+	AllowedOrigins []string `json:"allowedOrigins"`
+	AllowedMethods []string `json:"allowedMethods"` // GET, PUT, POST, DELETE or HEAD
+	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
+	ExposeHeaders  []string `json:"exposeHeaders,omitempty"`
+	MaxAgeSeconds  int32    `json:"maxAgeSeconds,omitempty"` // how long browsers may cache the preflight response
+}
+
+// BucketCors represents the CORS configuration of a bucket
+type BucketCors struct {
+	Bucket string     `json:"bucket"`
+	Rules  []CORSRule `json:"rules"`
+}
+
+// S3BucketCorsManager interface for bucket CORS operations
+type S3BucketCorsManager interface {
+	GetBucketCors(ctx context.Context, bucket string) (*BucketCors, error)
+	PutBucketCors(ctx context.Context, bucket string, rules []CORSRule) error
+	DeleteBucketCors(ctx context.Context, bucket string) error
+}
+
+// GetBucketCors returns the CORS rules of a bucket, an empty list when none are set
+func (s *AWSS3Service) GetBucketCors(ctx context.Context, bucket string) (*BucketCors, error) {
+	s.logger.Debug("Getting S3 bucket CORS", "bucketName", bucket)
+
+	result, err := s.client.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "NoSuchCORSConfiguration") {
+			return &BucketCors{Bucket: bucket, Rules: []CORSRule{}}, nil
+		}
+		s.logger.Error("Failed to get S3 bucket CORS", "error", err, "bucketName", bucket)
+		return nil, convertS3Error("get bucket cors", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{"bucket": bucket})
+	}
+
+	rules := make([]CORSRule, len(result.CORSRules))
+	for i, rule := range result.CORSRules {
+		rules[i] = CORSRule{
+			ID:             aws.ToString(rule.ID),
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  aws.ToInt32(rule.MaxAgeSeconds),
+		}
+	}
+
+	return &BucketCors{Bucket: bucket, Rules: rules}, nil
+}
+
+// PutBucketCors replaces the CORS rules of a bucket
+func (s *AWSS3Service) PutBucketCors(ctx context.Context, bucket string, rules []CORSRule) error {
+	s.logger.Debug("Updating S3 bucket CORS", "bucketName", bucket, "ruleCount", len(rules))
+
+	corsRules := make([]types.CORSRule, len(rules))
+	for i, rule := range rules {
+		corsRules[i] = types.CORSRule{
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+		}
+		if rule.ID != "" {
+			corsRules[i].ID = aws.String(rule.ID)
+		}
+		if rule.MaxAgeSeconds > 0 {
+			corsRules[i].MaxAgeSeconds = aws.Int32(rule.MaxAgeSeconds)
+		}
+	}
+
+	_, err := s.client.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+		Bucket:            aws.String(bucket),
+		CORSConfiguration: &types.CORSConfiguration{CORSRules: corsRules},
+	})
+	if err != nil {
+		s.logger.Error("Failed to update S3 bucket CORS", "error", err, "bucketName", bucket)
+		return convertS3Error("put bucket cors", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{"bucket": bucket, "ruleCount": len(rules)})
+	}
+
+	s.logger.Info("Successfully updated S3 bucket CORS", "bucketName", bucket, "ruleCount", len(rules))
+	return nil
+}
+
+// DeleteBucketCors removes every CORS rule from a bucket
+func (s *AWSS3Service) DeleteBucketCors(ctx context.Context, bucket string) error {
+	s.logger.Debug("Deleting S3 bucket CORS", "bucketName", bucket)
+
+	_, err := s.client.DeleteBucketCors(ctx, &s3.DeleteBucketCorsInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete S3 bucket CORS", "error", err, "bucketName", bucket)
+		return convertS3Error("delete bucket cors", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{"bucket": bucket})
+	}
+
+	s.logger.Info("Successfully deleted S3 bucket CORS", "bucketName", bucket)
+	return nil
+}
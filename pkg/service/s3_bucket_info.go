@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Bucket info section states
+const (
+	SectionOK            = "ok"
+	SectionNotConfigured = "not_configured" // the bucket has no such configuration
+	SectionNotPermitted  = "not_permitted"  // the credentials may not read it
+	SectionNotSupported  = "not_supported"  // the endpoint does not implement the API
+	SectionError         = "error"
+)
+
+// notConfiguredErrors are the error codes S3 answers when a bucket has no configuration of a kind
+var notConfiguredErrors = []string{
+	"ServerSideEncryptionConfigurationNotFoundError",
+	"NoSuchPublicAccessBlockConfiguration",
+	"OwnershipControlsNotFoundError",
+	"ObjectLockConfigurationNotFoundError",
+	"NoSuchTagSet",
+}
+
+// BucketInfoSection holds one bucket property, or why it could not be read
+type BucketInfoSection struct {
+	Status  string `json:"status"`
+	Value   any    `json:"value,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// BucketInfo gathers the bucket properties shown when a bucket is selected
+type BucketInfo struct {
+	Bucket            string            `json:"bucket"`
+	Region            BucketInfoSection `json:"region"`
+	Versioning        BucketInfoSection `json:"versioning"`
+	Encryption        BucketInfoSection `json:"encryption"`
+	PublicAccessBlock BucketInfoSection `json:"publicAccessBlock"`
+	OwnershipControls BucketInfoSection `json:"ownershipControls"`
+	ObjectLock        BucketInfoSection `json:"objectLock"`
+	Tagging           BucketInfoSection `json:"tagging"`
+	Logging           BucketInfoSection `json:"logging"`
+	RequestPayment    BucketInfoSection `json:"requestPayment"`
+}
+
+// BucketEncryption represents the default encryption of a bucket
+type BucketEncryption struct {
+	Algorithm        string `json:"algorithm"`
+	KMSKeyID         string `json:"kmsKeyId,omitempty"`
+	BucketKeyEnabled bool   `json:"bucketKeyEnabled,omitempty"`
+}
+
+// BucketPublicAccessBlock represents the public access block settings of a bucket
+type BucketPublicAccessBlock struct {
+	BlockPublicAcls       bool `json:"blockPublicAcls"`
+	IgnorePublicAcls      bool `json:"ignorePublicAcls"`
+	BlockPublicPolicy     bool `json:"blockPublicPolicy"`
+	RestrictPublicBuckets bool `json:"restrictPublicBuckets"`
+}
+
+// BucketObjectLock represents the object lock configuration of a bucket
+type BucketObjectLock struct {
+	Enabled bool   `json:"enabled"`
+	Mode    string `json:"mode,omitempty"` // default retention mode
+	Days    int32  `json:"days,omitempty"`
+	Years   int32  `json:"years,omitempty"`
+}
+
+// BucketLogging represents the server access logging target of a bucket
+type BucketLogging struct {
+	Enabled      bool   `json:"enabled"`
+	TargetBucket string `json:"targetBucket,omitempty"`
+	TargetPrefix string `json:"targetPrefix,omitempty"`
+}
+
+// S3BucketInspector interface for reading bucket properties
+type S3BucketInspector interface {
+	GetBucketInfo(ctx context.Context, bucket string) (*BucketInfo, error)
+}
+
+// GetBucketInfo reads every bucket property concurrently. A property that cannot be
+// read is reported in its own section, so one denied call does not hide the rest.
+func (s *AWSS3Service) GetBucketInfo(ctx context.Context, bucket string) (*BucketInfo, error) {
+	s.logger.Debug("Getting S3 bucket info", "bucketName", bucket)
+
+	info := &BucketInfo{Bucket: bucket}
+	sections := []struct {
+		target *BucketInfoSection
+		fetch  func(ctx context.Context, bucket string) (any, error)
+	}{
+		{&info.Region, s.bucketRegion},
+		{&info.Versioning, func(ctx context.Context, bucket string) (any, error) { return s.GetBucketVersioning(ctx, bucket) }},
+		{&info.Encryption, s.bucketEncryption},
+		{&info.PublicAccessBlock, s.bucketPublicAccessBlock},
+		{&info.OwnershipControls, s.bucketOwnershipControls},
+		{&info.ObjectLock, s.bucketObjectLock},
+		{&info.Tagging, s.bucketTagging},
+		{&info.Logging, s.bucketLogging},
+		{&info.RequestPayment, s.bucketRequestPayment},
+	}
+
+	errs := make([]error, len(sections))
+	var wg sync.WaitGroup
+	for i, section := range sections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := section.fetch(ctx, bucket)
+			errs[i] = err
+			*section.target = newBucketInfoSection(value, err)
+		}()
+	}
+	wg.Wait()
+
+	// A missing bucket fails every call, so report it once instead of per section
+	for _, err := range errs {
+		if err != nil && strings.Contains(err.Error(), "NoSuchBucket") {
+			return nil, s3cerrors.NewS3BucketNotFoundError(bucket).WithWrapped(err)
+		}
+	}
+
+	return info, nil
+}
+
+// newBucketInfoSection classifies the result of a single property read
+func newBucketInfoSection(value any, err error) BucketInfoSection {
+	if err == nil {
+		if value == nil {
+			return BucketInfoSection{Status: SectionNotConfigured}
+		}
+		return BucketInfoSection{Status: SectionOK, Value: value}
+	}
+
+	errMsg := err.Error()
+	for _, code := range notConfiguredErrors {
+		if strings.Contains(errMsg, code) {
+			return BucketInfoSection{Status: SectionNotConfigured}
+		}
+	}
+
+	switch {
+	case strings.Contains(errMsg, "AccessDenied") || strings.Contains(errMsg, "Forbidden"):
+		return BucketInfoSection{Status: SectionNotPermitted}
+	case isNotImplemented(err) || strings.Contains(errMsg, "MethodNotAllowed"):
+		return BucketInfoSection{Status: SectionNotSupported}
+	default:
+		return BucketInfoSection{Status: SectionError, Message: errMsg}
+	}
+}
+
+// bucketRegion resolves the bucket region, mapping the legacy location constraints
+func (s *AWSS3Service) bucketRegion(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+
+	switch region := string(result.LocationConstraint); region {
+	case "":
+		return "us-east-1", nil
+	case "EU":
+		return "eu-west-1", nil
+	default:
+		return region, nil
+	}
+}
+
+func (s *AWSS3Service) bucketEncryption(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+	if result.ServerSideEncryptionConfiguration == nil || len(result.ServerSideEncryptionConfiguration.Rules) == 0 {
+		return nil, nil
+	}
+
+	rule := result.ServerSideEncryptionConfiguration.Rules[0]
+	encryption := &BucketEncryption{BucketKeyEnabled: aws.ToBool(rule.BucketKeyEnabled)}
+	if rule.ApplyServerSideEncryptionByDefault != nil {
+		encryption.Algorithm = string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+		encryption.KMSKeyID = aws.ToString(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID)
+	}
+	return encryption, nil
+}
+
+func (s *AWSS3Service) bucketPublicAccessBlock(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+
+	config := result.PublicAccessBlockConfiguration
+	if config == nil {
+		return nil, nil
+	}
+	return &BucketPublicAccessBlock{
+		BlockPublicAcls:       aws.ToBool(config.BlockPublicAcls),
+		IgnorePublicAcls:      aws.ToBool(config.IgnorePublicAcls),
+		BlockPublicPolicy:     aws.ToBool(config.BlockPublicPolicy),
+		RestrictPublicBuckets: aws.ToBool(config.RestrictPublicBuckets),
+	}, nil
+}
+
+// bucketOwnershipControls returns the object ownership setting, e.g. BucketOwnerEnforced
+func (s *AWSS3Service) bucketOwnershipControls(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+	if result.OwnershipControls == nil || len(result.OwnershipControls.Rules) == 0 {
+		return nil, nil
+	}
+	return string(result.OwnershipControls.Rules[0].ObjectOwnership), nil
+}
+
+func (s *AWSS3Service) bucketObjectLock(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+
+	config := result.ObjectLockConfiguration
+	if config == nil {
+		return &BucketObjectLock{}, nil
+	}
+	lock := &BucketObjectLock{Enabled: config.ObjectLockEnabled == "Enabled"}
+	if config.Rule != nil && config.Rule.DefaultRetention != nil {
+		lock.Mode = string(config.Rule.DefaultRetention.Mode)
+		lock.Days = aws.ToInt32(config.Rule.DefaultRetention.Days)
+		lock.Years = aws.ToInt32(config.Rule.DefaultRetention.Years)
+	}
+	return lock, nil
+}
+
+func (s *AWSS3Service) bucketTagging(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(result.TagSet))
+	for _, tag := range result.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+func (s *AWSS3Service) bucketLogging(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.LoggingEnabled == nil {
+		return &BucketLogging{}, nil
+	}
+	return &BucketLogging{
+		Enabled:      true,
+		TargetBucket: aws.ToString(result.LoggingEnabled.TargetBucket),
+		TargetPrefix: aws.ToString(result.LoggingEnabled.TargetPrefix),
+	}, nil
+}
+
+// bucketRequestPayment returns who pays for requests, BucketOwner or Requester
+func (s *AWSS3Service) bucketRequestPayment(ctx context.Context, bucket string) (any, error) {
+	result, err := s.client.GetBucketRequestPayment(ctx, &s3.GetBucketRequestPaymentInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return nil, err
+	}
+	return string(result.Payer), nil
+}
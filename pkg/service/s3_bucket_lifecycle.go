@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Lifecycle actions reported by a rule preview
+const (
+	LifecycleActionExpire     = "expire"
+	LifecycleActionTransition = "transition"
+)
+
+// LifecycleRule is one bucket lifecycle rule. The filter fields are combined with
+// AND; a rule without any of them applies to the whole bucket.
+type LifecycleRule struct {
+	ID                                 string                        `json:"id,omitempty"`
+	Enabled                            bool                          `json:"enabled"`
+	Prefix                             string                        `json:"prefix,omitempty"`
+	Tags                               map[string]string             `json:"tags,omitempty"`
+	ObjectSizeGreaterThan              int64                         `json:"objectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan                 int64                         `json:"objectSizeLessThan,omitempty"`
+	Expiration                         *LifecycleExpiration          `json:"expiration,omitempty"`
+	Transitions                        []LifecycleTransition         `json:"transitions,omitempty"`
+	NoncurrentVersionExpiration        *NoncurrentVersionExpiration  `json:"noncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransitions       []NoncurrentVersionTransition `json:"noncurrentVersionTransitions,omitempty"`
+	AbortIncompleteMultipartUploadDays int32                         `json:"abortIncompleteMultipartUploadDays,omitempty"`
+}
+
+// LifecycleExpiration expires current object versions after a number of days or on a date
+type LifecycleExpiration struct {
+	Days                      int32      `json:"days,omitempty"`
+	Date                      *time.Time `json:"date,omitempty"`
+	ExpiredObjectDeleteMarker bool       `json:"expiredObjectDeleteMarker,omitempty"` // remove delete markers left without versions
+}
+
+// LifecycleTransition moves current object versions to another storage class
+type LifecycleTransition struct {
+	Days         int32      `json:"days,omitempty"`
+	Date         *time.Time `json:"date,omitempty"`
+	StorageClass string     `json:"storageClass"`
+}
+
+// NoncurrentVersionExpiration removes versions some days after they become noncurrent
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays          int32 `json:"noncurrentDays"`
+	NewerNoncurrentVersions int32 `json:"newerNoncurrentVersions,omitempty"` // keep this many newest noncurrent versions
+}
+
+// NoncurrentVersionTransition moves noncurrent versions to another storage class
+type NoncurrentVersionTransition struct {
+	NoncurrentDays          int32  `json:"noncurrentDays"`
+	NewerNoncurrentVersions int32  `json:"newerNoncurrentVersions,omitempty"`
+	StorageClass            string `json:"storageClass"`
+}
+
+// BucketLifecycle represents the lifecycle configuration of a bucket
+type BucketLifecycle struct {
+	Bucket string          `json:"bucket"`
+	Rules  []LifecycleRule `json:"rules"`
+}
+
+// LifecyclePreviewInput represents input for previewing a rule against one listing page
+type LifecyclePreviewInput struct {
+	Bucket            string        `json:"bucket"`
+	Prefix            string        `json:"prefix,omitempty"` // the folder currently listed
+	ContinuationToken string        `json:"continuationToken,omitempty"`
+	Rule              LifecycleRule `json:"rule"`
+}
+
+// LifecycleMatch is an object a rule applies to, with the dates its actions are due
+type LifecycleMatch struct {
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	LastModified string            `json:"lastModified"`
+	Actions      []LifecycleAction `json:"actions,omitempty"`
+}
+
+// LifecycleAction is a scheduled lifecycle action on the current version of an object
+type LifecycleAction struct {
+	Action       string    `json:"action"` // expire or transition
+	StorageClass string    `json:"storageClass,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+// LifecyclePreviewOutput lists the objects of a listing page that a rule matches
+type LifecyclePreviewOutput struct {
+	Matched               []LifecycleMatch `json:"matched"`
+	Checked               int              `json:"checked"`
+	Failed                []ObjectError    `json:"failed,omitempty"` // objects whose tags could not be read
+	IsTruncated           bool             `json:"isTruncated"`
+	NextContinuationToken string           `json:"nextContinuationToken,omitempty"`
+}
+
+// S3BucketLifecycleManager interface for bucket lifecycle operations
+type S3BucketLifecycleManager interface {
+	GetBucketLifecycle(ctx context.Context, bucket string) (*BucketLifecycle, error)
+	PutBucketLifecycle(ctx context.Context, bucket string, rules []LifecycleRule) error
+	DeleteBucketLifecycle(ctx context.Context, bucket string) error
+	PreviewLifecycleRule(ctx context.Context, input LifecyclePreviewInput) (*LifecyclePreviewOutput, error)
+}
+
+// GetBucketLifecycle returns the lifecycle rules of a bucket, an empty list when none are set
+func (s *AWSS3Service) GetBucketLifecycle(ctx context.Context, bucket string) (*BucketLifecycle, error) {
+	s.logger.Debug("Getting S3 bucket lifecycle", "bucketName", bucket)
+
+	result, err := s.client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "NoSuchLifecycleConfiguration") {
+			return &BucketLifecycle{Bucket: bucket, Rules: []LifecycleRule{}}, nil
+		}
+		s.logger.Error("Failed to get S3 bucket lifecycle", "error", err, "bucketName", bucket)
+		return nil, convertS3Error("get bucket lifecycle", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{"bucket": bucket})
+	}
+
+	rules := make([]LifecycleRule, len(result.Rules))
+	for i, rule := range result.Rules {
+		rules[i] = fromSDKLifecycleRule(rule)
+	}
+
+	return &BucketLifecycle{Bucket: bucket, Rules: rules}, nil
+}
+
+// PutBucketLifecycle replaces the lifecycle rules of a bucket
+func (s *AWSS3Service) PutBucketLifecycle(ctx context.Context, bucket string, rules []LifecycleRule) error {
+	s.logger.Debug("Updating S3 bucket lifecycle", "bucketName", bucket, "ruleCount", len(rules))
+
+	sdkRules := make([]types.LifecycleRule, len(rules))
+	for i, rule := range rules {
+		sdkRules[i] = toSDKLifecycleRule(rule)
+	}
+
+	_, err := s.client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(bucket),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{Rules: sdkRules},
+	})
+	if err != nil {
+		s.logger.Error("Failed to update S3 bucket lifecycle", "error", err, "bucketName", bucket)
+		return convertS3Error("put bucket lifecycle", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{"bucket": bucket, "ruleCount": len(rules)})
+	}
+
+	s.logger.Info("Successfully updated S3 bucket lifecycle", "bucketName", bucket, "ruleCount", len(rules))
+	return nil
+}
+
+// DeleteBucketLifecycle removes every lifecycle rule from a bucket
+func (s *AWSS3Service) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	s.logger.Debug("Deleting S3 bucket lifecycle", "bucketName", bucket)
+
+	_, err := s.client.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete S3 bucket lifecycle", "error", err, "bucketName", bucket)
+		return convertS3Error("delete bucket lifecycle", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{"bucket": bucket})
+	}
+
+	s.logger.Info("Successfully deleted S3 bucket lifecycle", "bucketName", bucket)
+	return nil
+}
+
+// PreviewLifecycleRule checks a rule against one page of the folder listing, the same
+// page the object browser shows. Tags are only fetched for objects that pass the
+// prefix and size conditions, since each one costs a request.
+func (s *AWSS3Service) PreviewLifecycleRule(ctx context.Context, input LifecyclePreviewInput) (*LifecyclePreviewOutput, error) {
+	listing, err := s.ListObjects(ctx, ListObjectsInput{
+		Bucket:            input.Bucket,
+		Prefix:            input.Prefix,
+		MaxKeys:           maxDeleteBatchSize,
+		ContinuationToken: input.ContinuationToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rule := input.Rule
+	var candidates []S3Object
+	for _, object := range listing.Objects {
+		if object.IsFolder {
+			continue
+		}
+		if lifecycleObjectMatches(rule, object.Key, object.Size) {
+			candidates = append(candidates, object)
+		}
+	}
+
+	tags := make([]map[string]string, len(candidates))
+	errs := make([]error, len(candidates))
+	if len(rule.Tags) > 0 {
+		keys := make([]string, len(candidates))
+		for i, object := range candidates {
+			keys[i] = object.Key
+		}
+		errs = forEachKey(ctx, keys, func(i int, key string) error {
+			objectTags, err := s.GetObjectTagging(ctx, ObjectTaggingInput{Bucket: input.Bucket, Key: key})
+			if err != nil {
+				return err
+			}
+			tags[i] = objectTags.Tags
+			return nil
+		})
+	}
+
+	output := &LifecyclePreviewOutput{
+		Matched:               []LifecycleMatch{},
+		IsTruncated:           listing.IsTruncated,
+		NextContinuationToken: listing.NextContinuationToken,
+	}
+	for _, object := range listing.Objects {
+		if !object.IsFolder {
+			output.Checked++
+		}
+	}
+
+	for i, object := range candidates {
+		if errs[i] != nil {
+			output.Failed = append(output.Failed, newObjectError(object.Key, errs[i]))
+			continue
+		}
+		if !lifecycleTagsMatch(rule.Tags, tags[i]) {
+			continue
+		}
+
+		lastModified, _ := time.Parse(time.RFC3339, object.LastModified)
+		output.Matched = append(output.Matched, LifecycleMatch{
+			Key:          object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+			Actions:      scheduledLifecycleActions(rule, lastModified),
+		})
+	}
+
+	return output, nil
+}
+
+// lifecycleObjectMatches reports whether an object passes the prefix and size conditions of a rule
+func lifecycleObjectMatches(rule LifecycleRule, key string, size int64) bool {
+	if !strings.HasPrefix(key, rule.Prefix) {
+		return false
+	}
+	if rule.ObjectSizeGreaterThan > 0 && size <= rule.ObjectSizeGreaterThan {
+		return false
+	}
+	if rule.ObjectSizeLessThan > 0 && size >= rule.ObjectSizeLessThan {
+		return false
+	}
+	return true
+}
+
+// lifecycleTagsMatch reports whether an object carries every tag a rule filters on
+func lifecycleTagsMatch(filter, tags map[string]string) bool {
+	for name, value := range filter {
+		if current, ok := tags[name]; !ok || current != value {
+			return false
+		}
+	}
+	return true
+}
+
+// scheduledLifecycleActions returns when the current-version actions of a rule are due
+// for an object last modified at the given time
+func scheduledLifecycleActions(rule LifecycleRule, lastModified time.Time) []LifecycleAction {
+	var actions []LifecycleAction
+	for _, transition := range rule.Transitions {
+		actions = append(actions, LifecycleAction{
+			Action:       LifecycleActionTransition,
+			StorageClass: transition.StorageClass,
+			At:           lifecycleDueDate(lastModified, transition.Days, transition.Date),
+		})
+	}
+	if rule.Expiration != nil && (rule.Expiration.Days > 0 || rule.Expiration.Date != nil) {
+		actions = append(actions, LifecycleAction{
+			Action: LifecycleActionExpire,
+			At:     lifecycleDueDate(lastModified, rule.Expiration.Days, rule.Expiration.Date),
+		})
+	}
+	return actions
+}
+
+// lifecycleDueDate applies the S3 rule: add the days to the object creation time and
+// round up to the next midnight UTC. A fixed date is used as is.
+func lifecycleDueDate(lastModified time.Time, days int32, date *time.Time) time.Time {
+	if date != nil {
+		return date.UTC()
+	}
+	due := lastModified.UTC().AddDate(0, 0, int(days))
+	midnight := due.Truncate(24 * time.Hour)
+	if midnight.Equal(due) {
+		return due
+	}
+	return midnight.Add(24 * time.Hour)
+}
+
+// toSDKLifecycleRule converts a rule into the SDK shape, using the And operator
+// only when the filter has more than one condition, as S3 requires
+func toSDKLifecycleRule(rule LifecycleRule) types.LifecycleRule {
+	status := types.ExpirationStatusDisabled
+	if rule.Enabled {
+		status = types.ExpirationStatusEnabled
+	}
+	sdkRule := types.LifecycleRule{Status: status}
+	if rule.ID != "" {
+		sdkRule.ID = aws.String(rule.ID)
+	}
+
+	conditions := len(rule.Tags)
+	for _, set := range []bool{rule.Prefix != "", rule.ObjectSizeGreaterThan > 0, rule.ObjectSizeLessThan > 0} {
+		if set {
+			conditions++
+		}
+	}
+
+	filter := &types.LifecycleRuleFilter{}
+	switch {
+	case conditions > 1:
+		filter.And = &types.LifecycleRuleAndOperator{Tags: tagSet(rule.Tags)}
+		if rule.Prefix != "" {
+			filter.And.Prefix = aws.String(rule.Prefix)
+		}
+		if rule.ObjectSizeGreaterThan > 0 {
+			filter.And.ObjectSizeGreaterThan = aws.Int64(rule.ObjectSizeGreaterThan)
+		}
+		if rule.ObjectSizeLessThan > 0 {
+			filter.And.ObjectSizeLessThan = aws.Int64(rule.ObjectSizeLessThan)
+		}
+	case len(rule.Tags) == 1:
+		filter.Tag = &tagSet(rule.Tags)[0]
+	case rule.ObjectSizeGreaterThan > 0:
+		filter.ObjectSizeGreaterThan = aws.Int64(rule.ObjectSizeGreaterThan)
+	case rule.ObjectSizeLessThan > 0:
+		filter.ObjectSizeLessThan = aws.Int64(rule.ObjectSizeLessThan)
+	default:
+		// An empty prefix applies the rule to the whole bucket
+		filter.Prefix = aws.String(rule.Prefix)
+	}
+	sdkRule.Filter = filter
+
+	if rule.Expiration != nil {
+		sdkRule.Expiration = &types.LifecycleExpiration{Date: rule.Expiration.Date}
+		if rule.Expiration.Days > 0 {
+			sdkRule.Expiration.Days = aws.Int32(rule.Expiration.Days)
+		}
+		if rule.Expiration.ExpiredObjectDeleteMarker {
+			sdkRule.Expiration.ExpiredObjectDeleteMarker = aws.Bool(true)
+		}
+	}
+	for _, transition := range rule.Transitions {
+		sdkTransition := types.Transition{
+			Date:         transition.Date,
+			StorageClass: types.TransitionStorageClass(transition.StorageClass),
+		}
+		if transition.Date == nil {
+			sdkTransition.Days = aws.Int32(transition.Days)
+		}
+		sdkRule.Transitions = append(sdkRule.Transitions, sdkTransition)
+	}
+	if rule.NoncurrentVersionExpiration != nil {
+		sdkRule.NoncurrentVersionExpiration = &types.NoncurrentVersionExpiration{
+			NoncurrentDays: aws.Int32(rule.NoncurrentVersionExpiration.NoncurrentDays),
+		}
+		if newer := rule.NoncurrentVersionExpiration.NewerNoncurrentVersions; newer > 0 {
+			sdkRule.NoncurrentVersionExpiration.NewerNoncurrentVersions = aws.Int32(newer)
+		}
+	}
+	for _, transition := range rule.NoncurrentVersionTransitions {
+		sdkTransition := types.NoncurrentVersionTransition{
+			NoncurrentDays: aws.Int32(transition.NoncurrentDays),
+			StorageClass:   types.TransitionStorageClass(transition.StorageClass),
+		}
+		if transition.NewerNoncurrentVersions > 0 {
+			sdkTransition.NewerNoncurrentVersions = aws.Int32(transition.NewerNoncurrentVersions)
+		}
+		sdkRule.NoncurrentVersionTransitions = append(sdkRule.NoncurrentVersionTransitions, sdkTransition)
+	}
+	if rule.AbortIncompleteMultipartUploadDays > 0 {
+		sdkRule.AbortIncompleteMultipartUpload = &types.AbortIncompleteMultipartUpload{
+			DaysAfterInitiation: aws.Int32(rule.AbortIncompleteMultipartUploadDays),
+		}
+	}
+
+	return sdkRule
+}
+
+// fromSDKLifecycleRule flattens an SDK rule, including the deprecated rule-level prefix
+func fromSDKLifecycleRule(sdkRule types.LifecycleRule) LifecycleRule {
+	rule := LifecycleRule{
+		ID:      aws.ToString(sdkRule.ID),
+		Enabled: sdkRule.Status == types.ExpirationStatusEnabled,
+		Prefix:  aws.ToString(sdkRule.Prefix),
+	}
+
+	if filter := sdkRule.Filter; filter != nil {
+		tags := make(map[string]string)
+		if filter.Prefix != nil {
+			rule.Prefix = aws.ToString(filter.Prefix)
+		}
+		if filter.Tag != nil {
+			tags[aws.ToString(filter.Tag.Key)] = aws.ToString(filter.Tag.Value)
+		}
+		rule.ObjectSizeGreaterThan = aws.ToInt64(filter.ObjectSizeGreaterThan)
+		rule.ObjectSizeLessThan = aws.ToInt64(filter.ObjectSizeLessThan)
+		if and := filter.And; and != nil {
+			rule.Prefix = aws.ToString(and.Prefix)
+			rule.ObjectSizeGreaterThan = aws.ToInt64(and.ObjectSizeGreaterThan)
+			rule.ObjectSizeLessThan = aws.ToInt64(and.ObjectSizeLessThan)
+			for _, tag := range and.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+		}
+		if len(tags) > 0 {
+			rule.Tags = tags
+		}
+	}
+
+	if expiration := sdkRule.Expiration; expiration != nil {
+		rule.Expiration = &LifecycleExpiration{
+			Days:                      aws.ToInt32(expiration.Days),
+			Date:                      expiration.Date,
+			ExpiredObjectDeleteMarker: aws.ToBool(expiration.ExpiredObjectDeleteMarker),
+		}
+	}
+	for _, transition := range sdkRule.Transitions {
+		rule.Transitions = append(rule.Transitions, LifecycleTransition{
+			Days:         aws.ToInt32(transition.Days),
+			Date:         transition.Date,
+			StorageClass: string(transition.StorageClass),
+		})
+	}
+	if expiration := sdkRule.NoncurrentVersionExpiration; expiration != nil {
+		rule.NoncurrentVersionExpiration = &NoncurrentVersionExpiration{
+			NoncurrentDays:          aws.ToInt32(expiration.NoncurrentDays),
+			NewerNoncurrentVersions: aws.ToInt32(expiration.NewerNoncurrentVersions),
+		}
+	}
+	for _, transition := range sdkRule.NoncurrentVersionTransitions {
+		rule.NoncurrentVersionTransitions = append(rule.NoncurrentVersionTransitions, NoncurrentVersionTransition{
+			NoncurrentDays:          aws.ToInt32(transition.NoncurrentDays),
+			NewerNoncurrentVersions: aws.ToInt32(transition.NewerNoncurrentVersions),
+			StorageClass:            string(transition.StorageClass),
+		})
+	}
+	if abort := sdkRule.AbortIncompleteMultipartUpload; abort != nil {
+		rule.AbortIncompleteMultipartUploadDays = aws.ToInt32(abort.DaysAfterInitiation)
+	}
+
+	return rule
+}
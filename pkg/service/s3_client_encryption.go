@@ -0,0 +1,426 @@
+package service
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Key sources for client-side encryption
+const (
+	ClientKeySourcePassphrase = "passphrase" // key derived from a passphrase sent with each request
+	ClientKeySourceKeyFile    = "key-file"   // key read from the file named in S3Config.ClientKeyFile
+)
+
+// Client-side encryption format. Bodies are split into chunks that are sealed
+// independently with AES-256-GCM under a random per-object data key, which is
+// itself sealed with the key from the chosen key source.
+const (
+	clientEncryptionAlgorithm = "AES-256-GCM"
+	clientChunkSize           = 64 << 10 // plaintext bytes per chunk
+	clientKeySize             = 32
+	gcmTagSize                = 16
+
+	// PBKDF2-HMAC-SHA256 settings for passphrase keys, iterations follow the OWASP recommendation
+	clientKDF           = "PBKDF2-SHA256"
+	clientKDFIterations = 600_000
+	clientKDFSaltSize   = 16
+)
+
+// Object metadata describing a client-side encrypted body. S3 returns user
+// metadata keys in lower case, so the keys are lower case to begin with.
+const (
+	metaClientAlgorithm     = "s3c-cse-algorithm"
+	metaClientChunkSize     = "s3c-cse-chunk-size"
+	metaClientKeySource     = "s3c-cse-key-source"
+	metaClientWrappedKey    = "s3c-cse-wrapped-key"
+	metaClientKeyID         = "s3c-cse-key-id"
+	metaClientKDF           = "s3c-cse-kdf"
+	metaClientKDFSalt       = "s3c-cse-kdf-salt"
+	metaClientKDFIterations = "s3c-cse-kdf-iterations"
+)
+
+// ClientEncryption asks for an object body to be encrypted before it leaves s3c,
+// so the storage provider only ever sees ciphertext
+type ClientEncryption struct {
+	KeySource  string `json:"keySource"`            // passphrase or key-file
+	Passphrase string `json:"passphrase,omitempty"` // passphrase key source only
+}
+
+// isClientEncrypted reports whether object metadata describes a client-side encrypted body
+func isClientEncrypted(metadata map[string]string) bool {
+	_, ok := metadata[metaClientAlgorithm]
+	return ok
+}
+
+// withClientEncryptionMetadata returns metadata with the client-side encryption keys of
+// current carried over, so that replacing user metadata never makes an object unreadable
+func withClientEncryptionMetadata(metadata, current map[string]string) map[string]string {
+	merged := maps.Clone(metadata)
+	for key, value := range current {
+		if strings.HasPrefix(key, "s3c-cse-") {
+			if merged == nil {
+				merged = make(map[string]string)
+			}
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// loadClientKeyFile reads a 256-bit key stored either as 32 raw bytes or base64 text
+func loadClientKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, s3cerrors.NewConfigError(s3cerrors.CodeConfigInvalid, "Failed to read client encryption key file").
+			WithWrapped(err).
+			WithDetails(map[string]any{"clientKeyFile": path})
+	}
+	if len(data) == clientKeySize {
+		return data, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != clientKeySize {
+		return nil, s3cerrors.NewConfigError(s3cerrors.CodeConfigInvalid, "Client encryption key file must hold a 256-bit key").
+			WithDetails(map[string]any{"clientKeyFile": path}).
+			WithSuggestion("Create one with: openssl rand -base64 32 > s3c.key")
+	}
+	return key, nil
+}
+
+// clientKeyID fingerprints a key file key, so a mismatch can be reported without trying to decrypt
+func clientKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// newClientDataKey creates a data key for one object and seals it with the key from
+// the requested key source, returning the metadata needed to open it again
+func (s *AWSS3Service) newClientDataKey(enc *ClientEncryption) ([]byte, map[string]string, error) {
+	metadata := map[string]string{
+		metaClientAlgorithm: clientEncryptionAlgorithm,
+		metaClientChunkSize: strconv.Itoa(clientChunkSize),
+		metaClientKeySource: enc.KeySource,
+	}
+
+	var wrappingKey []byte
+	switch enc.KeySource {
+	case ClientKeySourcePassphrase:
+		if enc.Passphrase == "" {
+			return nil, nil, s3cerrors.NewMissingFieldError("clientEncryption.passphrase")
+		}
+		salt := make([]byte, clientKDFSaltSize)
+		rand.Read(salt)
+		key, err := pbkdf2.Key(sha256.New, enc.Passphrase, salt, clientKDFIterations, clientKeySize)
+		if err != nil {
+			return nil, nil, s3cerrors.NewInternalError(s3cerrors.CodeInternalError, "Failed to derive encryption key").WithWrapped(err)
+		}
+		wrappingKey = key
+		metadata[metaClientKDF] = clientKDF
+		metadata[metaClientKDFSalt] = base64.StdEncoding.EncodeToString(salt)
+		metadata[metaClientKDFIterations] = strconv.Itoa(clientKDFIterations)
+	case ClientKeySourceKeyFile:
+		if s.clientKey == nil {
+			return nil, nil, s3cerrors.NewEncryptionKeyMissingError(ClientKeySourceKeyFile).
+				WithSuggestion("Set a client encryption key file in the connection settings")
+		}
+		wrappingKey = s.clientKey
+		metadata[metaClientKeyID] = clientKeyID(s.clientKey)
+	default:
+		return nil, nil, s3cerrors.NewInvalidInputError("clientEncryption.keySource", enc.KeySource).
+			WithSuggestion("Use passphrase or key-file")
+	}
+
+	dataKey := make([]byte, clientKeySize)
+	rand.Read(dataKey)
+
+	aead, err := newGCM(wrappingKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	// The algorithm is bound to the sealed key so the metadata cannot be swapped
+	wrapped := aead.Seal(nonce, nonce, dataKey, []byte(clientEncryptionAlgorithm))
+	metadata[metaClientWrappedKey] = base64.StdEncoding.EncodeToString(wrapped)
+
+	return dataKey, metadata, nil
+}
+
+// openClientDataKey recovers the data key of an object from its metadata
+func (s *AWSS3Service) openClientDataKey(metadata map[string]string, passphrase string) ([]byte, error) {
+	if algorithm := metadata[metaClientAlgorithm]; algorithm != clientEncryptionAlgorithm {
+		return nil, s3cerrors.NewDecryptionFailedError(errors.New("unsupported algorithm " + algorithm))
+	}
+	if chunkSize := metadata[metaClientChunkSize]; chunkSize != strconv.Itoa(clientChunkSize) {
+		return nil, s3cerrors.NewDecryptionFailedError(errors.New("unsupported chunk size " + chunkSize))
+	}
+
+	var wrappingKey []byte
+	switch source := metadata[metaClientKeySource]; source {
+	case ClientKeySourcePassphrase:
+		if passphrase == "" {
+			return nil, s3cerrors.NewEncryptionKeyMissingError(ClientKeySourcePassphrase).
+				WithSuggestion("Enter the passphrase the object was uploaded with")
+		}
+		salt, err := base64.StdEncoding.DecodeString(metadata[metaClientKDFSalt])
+		if err != nil {
+			return nil, s3cerrors.NewDecryptionFailedError(err)
+		}
+		// Metadata is writable by anyone who can write the object, so only the
+		// iteration count s3c itself uses is accepted rather than an arbitrary cost
+		if metadata[metaClientKDF] != clientKDF || metadata[metaClientKDFIterations] != strconv.Itoa(clientKDFIterations) {
+			return nil, s3cerrors.NewDecryptionFailedError(errors.New("unsupported key derivation"))
+		}
+		key, err := pbkdf2.Key(sha256.New, passphrase, salt, clientKDFIterations, clientKeySize)
+		if err != nil {
+			return nil, s3cerrors.NewDecryptionFailedError(err)
+		}
+		wrappingKey = key
+	case ClientKeySourceKeyFile:
+		if s.clientKey == nil {
+			return nil, s3cerrors.NewEncryptionKeyMissingError(ClientKeySourceKeyFile).
+				WithSuggestion("Set the client encryption key file the object was uploaded with in the connection settings")
+		}
+		if id := metadata[metaClientKeyID]; id != clientKeyID(s.clientKey) {
+			return nil, s3cerrors.NewEncryptionKeyMissingError(ClientKeySourceKeyFile).
+				WithSuggestion("The object was encrypted with a different key file (key ID " + id + ")")
+		}
+		wrappingKey = s.clientKey
+	default:
+		return nil, s3cerrors.NewDecryptionFailedError(errors.New("unknown key source " + source))
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(metadata[metaClientWrappedKey])
+	if err != nil {
+		return nil, s3cerrors.NewDecryptionFailedError(err)
+	}
+	aead, err := newGCM(wrappingKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, s3cerrors.NewDecryptionFailedError(errors.New("wrapped key is truncated"))
+	}
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(clientEncryptionAlgorithm))
+	if err != nil {
+		// A wrong passphrase fails here, before any of the body is read
+		return nil, s3cerrors.NewDecryptionFailedError(err).
+			WithSuggestion("Check the passphrase or key file, it does not match the one used for the upload")
+	}
+	return dataKey, nil
+}
+
+// newGCM creates an AES-256-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, s3cerrors.NewInternalError(s3cerrors.CodeInternalError, "Failed to create cipher").WithWrapped(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, s3cerrors.NewInternalError(s3cerrors.CodeInternalError, "Failed to create cipher").WithWrapped(err)
+	}
+	return aead, nil
+}
+
+// chunkNonce derives the nonce of a chunk from its index and whether it is the last one.
+// Data keys are never reused across objects, so a counter nonce is safe, and marking the
+// last chunk lets a truncated body be detected.
+func chunkNonce(index uint64, final bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce, index)
+	if final {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// encryptedSize returns the stored size of a body of plainSize bytes
+func encryptedSize(plainSize int64) int64 {
+	chunks := max(1, (plainSize+clientChunkSize-1)/clientChunkSize)
+	return plainSize + chunks*gcmTagSize
+}
+
+// decryptedSize returns the plaintext size of a stored body of cipherSize bytes
+func decryptedSize(cipherSize int64) int64 {
+	chunks := max(1, (cipherSize+clientChunkSize+gcmTagSize-1)/(clientChunkSize+gcmTagSize))
+	return max(0, cipherSize-chunks*gcmTagSize)
+}
+
+// encryptingReader seals a plaintext stream chunk by chunk. One chunk is read
+// ahead so the last chunk can be marked before it is sealed.
+type encryptingReader struct {
+	src   io.Reader
+	aead  cipher.AEAD
+	index uint64
+	next  []byte // plaintext of the chunk after the one being returned
+	out   []byte // sealed bytes not yet returned
+	done  bool
+}
+
+// newEncryptingReader returns a reader of the sealed form of src
+func newEncryptingReader(src io.Reader, dataKey []byte) (io.Reader, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	next, err := readChunk(src, clientChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptingReader{src: src, aead: aead, next: next}, nil
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		chunk := r.next
+		final := len(chunk) < clientChunkSize
+		if !final {
+			next, err := readChunk(r.src, clientChunkSize)
+			if err != nil {
+				return 0, err
+			}
+			final = len(next) == 0
+			r.next = next
+		}
+
+		r.out = r.aead.Seal(r.out[:0], chunkNonce(r.index, final), chunk, nil)
+		r.index++
+		r.done = final
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// decryptingReader opens a sealed stream chunk by chunk and fails on any chunk
+// that was modified, reordered or cut off
+type decryptingReader struct {
+	src   *bufio.Reader
+	body  io.Closer
+	aead  cipher.AEAD
+	index uint64
+	out   []byte // plaintext not yet returned
+	done  bool
+	err   error
+}
+
+// newDecryptingReader returns a reader of the plaintext of a sealed body, closing body when closed
+func newDecryptingReader(body io.ReadCloser, dataKey []byte) (io.ReadCloser, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReader{
+		src:  bufio.NewReaderSize(body, clientChunkSize+gcmTagSize),
+		body: body,
+		aead: aead,
+	}, nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.openNext()
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// openNext reads and opens the next sealed chunk
+func (r *decryptingReader) openNext() error {
+	sealed, err := readChunk(r.src, clientChunkSize+gcmTagSize)
+	if err != nil {
+		return err
+	}
+
+	final := len(sealed) < clientChunkSize+gcmTagSize
+	if !final {
+		if _, err := r.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plain, err := r.aead.Open(r.out[:0], chunkNonce(r.index, final), sealed, nil)
+	if err != nil {
+		return s3cerrors.NewDecryptionFailedError(err).
+			WithDetails(map[string]any{"chunk": r.index})
+	}
+	r.out = plain
+	r.index++
+	r.done = final
+	return nil
+}
+
+func (r *decryptingReader) Close() error {
+	return r.body.Close()
+}
+
+// encryptUploadBody replaces the body of an upload with its sealed form and
+// records how to open it in the object metadata
+func (s *AWSS3Service) encryptUploadBody(input UploadObjectInput) (UploadObjectInput, error) {
+	dataKey, metadata, err := s.newClientDataKey(input.ClientEncryption)
+	if err != nil {
+		return input, err
+	}
+	body, err := newEncryptingReader(input.Body, dataKey)
+	if err != nil {
+		return input, err
+	}
+
+	input.Metadata = withClientEncryptionMetadata(input.Metadata, metadata)
+	input.Body = body
+	if input.Size > 0 {
+		input.Size = encryptedSize(input.Size)
+	} else {
+		input.Size = -1
+	}
+	return input, nil
+}
+
+// decryptDownloadBody replaces the body of a client-side encrypted download with its plaintext
+func (s *AWSS3Service) decryptDownloadBody(input DownloadObjectInput, output *DownloadObjectOutput) error {
+	dataKey, err := s.openClientDataKey(output.Metadata, input.ClientPassphrase)
+	if err != nil {
+		return err
+	}
+	body, err := newDecryptingReader(output.Body, dataKey)
+	if err != nil {
+		return err
+	}
+
+	output.Body = body
+	output.ContentLength = decryptedSize(output.ContentLength)
+	return nil
+}
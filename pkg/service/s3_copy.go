@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Server-side copy limits
+const (
+	// CopyObject accepts sources up to 5 GiB, larger objects need UploadPartCopy
+	maxSingleCopySize int64 = 5 << 30
+
+	// DefaultCopyPartSize is the range copied by each UploadPartCopy request
+	DefaultCopyPartSize int64 = 512 << 20 // 512 MiB
+)
+
+// CopyObjectInput represents input for a server-side object copy
+type CopyObjectInput struct {
+	SourceBucket      string `json:"sourceBucket"`
+	SourceKey         string `json:"sourceKey"`
+	SourceVersionID   string `json:"sourceVersionId,omitempty"` // copy a specific version, latest when empty
+	DestinationBucket string `json:"destinationBucket"`
+	DestinationKey    string `json:"destinationKey"`
+
+	// Encryption is applied to the destination, which otherwise gets the bucket default
+	Encryption *ServerSideEncryption `json:"encryption,omitempty"`
+	// SourceSSECustomerKey is the base64 key the source was written with when it uses SSE-C
+	SourceSSECustomerKey string `json:"-"`
+
+	// sourceETag makes every part copy conditional on the source still having this ETag
+	sourceETag string
+}
+
+// CopyObjectOutput represents output from a server-side object copy
+type CopyObjectOutput struct {
+	SourceKey      string `json:"sourceKey"`
+	DestinationKey string `json:"destinationKey"`
+	ETag           string `json:"etag"`
+	Size           int64  `json:"size"`
+	Multipart      bool   `json:"multipart,omitempty"`
+}
+
+// S3ObjectCopier interface for server-side copy and move operations
+type S3ObjectCopier interface {
+	CopyObject(ctx context.Context, input CopyObjectInput) (*CopyObjectOutput, error)
+	MoveObject(ctx context.Context, input CopyObjectInput) (*CopyObjectOutput, error)
+}
+
+// CopyObject copies an object within or across buckets without downloading it
+func (s *AWSS3Service) CopyObject(ctx context.Context, input CopyObjectInput) (*CopyObjectOutput, error) {
+	s.logger.Debug("Copying S3 object",
+		"sourceBucket", input.SourceBucket,
+		"sourceKey", input.SourceKey,
+		"destinationBucket", input.DestinationBucket,
+		"destinationKey", input.DestinationKey,
+	)
+
+	sse, sourceKey, err := input.encryptionFields()
+	if err != nil {
+		return nil, err.(*s3cerrors.S3CError).WithDetails(copyDetails(input))
+	}
+
+	headInput := &s3.HeadObjectInput{
+		Bucket:               aws.String(input.SourceBucket),
+		Key:                  aws.String(input.SourceKey),
+		SSECustomerAlgorithm: sourceKey.algorithm,
+		SSECustomerKey:       sourceKey.key,
+		SSECustomerKeyMD5:    sourceKey.keyMD5,
+	}
+	if input.SourceVersionID != "" {
+		headInput.VersionId = aws.String(input.SourceVersionID)
+	}
+	source, err := s.client.HeadObject(ctx, headInput)
+	if err != nil {
+		return nil, convertS3Error("copy object", err).(*s3cerrors.S3CError).
+			WithDetails(copyDetails(input))
+	}
+
+	// Copy only the object that was inspected, so a concurrent overwrite fails the
+	// copy (and a move keeps its source) instead of mixing or losing content
+	input.sourceETag = aws.ToString(source.ETag)
+
+	size := aws.ToInt64(source.ContentLength)
+	if size > maxSingleCopySize {
+		return s.copyMultipart(ctx, input, source, sse)
+	}
+
+	result, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:                         aws.String(input.DestinationBucket),
+		Key:                            aws.String(input.DestinationKey),
+		CopySource:                     aws.String(input.versionedCopySource()),
+		CopySourceIfMatch:              source.ETag,
+		StorageClass:                   source.StorageClass,
+		ServerSideEncryption:           sse.mode,
+		SSEKMSKeyId:                    sse.kmsKeyID,
+		SSEKMSEncryptionContext:        sse.kmsContext,
+		BucketKeyEnabled:               sse.bucketKeyEnabled,
+		SSECustomerAlgorithm:           sse.customer.algorithm,
+		SSECustomerKey:                 sse.customer.key,
+		SSECustomerKeyMD5:              sse.customer.keyMD5,
+		CopySourceSSECustomerAlgorithm: sourceKey.algorithm,
+		CopySourceSSECustomerKey:       sourceKey.key,
+		CopySourceSSECustomerKeyMD5:    sourceKey.keyMD5,
+	})
+	if err != nil {
+		return nil, convertS3Error("copy object", err).(*s3cerrors.S3CError).
+			WithDetails(copyDetails(input))
+	}
+
+	output := &CopyObjectOutput{
+		SourceKey:      input.SourceKey,
+		DestinationKey: input.DestinationKey,
+		Size:           size,
+	}
+	if result.CopyObjectResult != nil {
+		output.ETag = aws.ToString(result.CopyObjectResult.ETag)
+	}
+
+	s.logger.Info("Successfully copied S3 object",
+		"sourceBucket", input.SourceBucket,
+		"sourceKey", input.SourceKey,
+		"destinationBucket", input.DestinationBucket,
+		"destinationKey", input.DestinationKey,
+	)
+	return output, nil
+}
+
+// MoveObject copies an object and deletes the source once the copy is confirmed
+func (s *AWSS3Service) MoveObject(ctx context.Context, input CopyObjectInput) (*CopyObjectOutput, error) {
+	if input.SourceBucket == input.DestinationBucket && input.SourceKey == input.DestinationKey {
+		return nil, s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Source and destination are the same object").
+			WithDetails(copyDetails(input))
+	}
+
+	output, err := s.CopyObject(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Confirm the destination exists with the expected size before removing the source.
+	// The option was already checked by CopyObject.
+	sse, _, _ := input.encryptionFields()
+	dest, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(input.DestinationBucket),
+		Key:                  aws.String(input.DestinationKey),
+		SSECustomerAlgorithm: sse.customer.algorithm,
+		SSECustomerKey:       sse.customer.key,
+		SSECustomerKeyMD5:    sse.customer.keyMD5,
+	})
+	if err != nil {
+		return nil, convertS3Error("verify copied object", err).(*s3cerrors.S3CError).
+			WithDetails(copyDetails(input))
+	}
+	if aws.ToInt64(dest.ContentLength) != output.Size {
+		return nil, s3cerrors.NewS3Error(s3cerrors.CodeS3Operation, "Copied object does not match the source size").
+			WithDetails(map[string]any{
+				"sourceBucket":      input.SourceBucket,
+				"sourceKey":         input.SourceKey,
+				"destinationBucket": input.DestinationBucket,
+				"destinationKey":    input.DestinationKey,
+				"sourceSize":        output.Size,
+				"destinationSize":   aws.ToInt64(dest.ContentLength),
+			}).
+			WithSuggestion("The source object was kept, retry the move")
+	}
+
+	if input.SourceVersionID != "" {
+		// Remove exactly the version that was moved; a plain delete would hide the
+		// current object behind a delete marker and leave the moved version behind
+		_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:    aws.String(input.SourceBucket),
+			Key:       aws.String(input.SourceKey),
+			VersionId: aws.String(input.SourceVersionID),
+		})
+		if err != nil {
+			return nil, convertS3Error("delete object version", err).(*s3cerrors.S3CError).
+				WithDetails(copyDetails(input))
+		}
+	} else if err := s.DeleteObject(ctx, input.SourceBucket, input.SourceKey); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Successfully moved S3 object",
+		"sourceBucket", input.SourceBucket,
+		"sourceKey", input.SourceKey,
+		"destinationBucket", input.DestinationBucket,
+		"destinationKey", input.DestinationKey,
+	)
+	return output, nil
+}
+
+// copyMultipart copies objects larger than 5 GiB using concurrent UploadPartCopy requests
+func (s *AWSS3Service) copyMultipart(ctx context.Context, input CopyObjectInput, source *s3.HeadObjectOutput, sse sseFields) (*CopyObjectOutput, error) {
+	// UploadPartCopy does not carry headers over, so replay them from the source
+	createInput := &s3.CreateMultipartUploadInput{
+		Bucket:                  aws.String(input.DestinationBucket),
+		Key:                     aws.String(input.DestinationKey),
+		ContentType:             source.ContentType,
+		ContentEncoding:         source.ContentEncoding,
+		ContentDisposition:      source.ContentDisposition,
+		ContentLanguage:         source.ContentLanguage,
+		CacheControl:            source.CacheControl,
+		Metadata:                source.Metadata,
+		StorageClass:            source.StorageClass,
+		ServerSideEncryption:    sse.mode,
+		SSEKMSKeyId:             sse.kmsKeyID,
+		SSEKMSEncryptionContext: sse.kmsContext,
+		BucketKeyEnabled:        sse.bucketKeyEnabled,
+		SSECustomerAlgorithm:    sse.customer.algorithm,
+		SSECustomerKey:          sse.customer.key,
+		SSECustomerKeyMD5:       sse.customer.keyMD5,
+	}
+	tagInput := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(input.SourceBucket),
+		Key:    aws.String(input.SourceKey),
+	}
+	if input.SourceVersionID != "" {
+		tagInput.VersionId = aws.String(input.SourceVersionID)
+	}
+	if tagging, err := s.client.GetObjectTagging(ctx, tagInput); err == nil && len(tagging.TagSet) > 0 {
+		createInput.Tagging = aws.String(encodeTagSet(tagging.TagSet))
+	} else if err != nil {
+		s.logger.Warn("Could not read source tags for multipart copy", "error", err, "sourceKey", input.SourceKey)
+	}
+
+	return s.copyParts(ctx, input, aws.ToInt64(source.ContentLength), createInput)
+}
+
+// copyParts runs a multipart upload whose parts are copied server-side from the source object
+func (s *AWSS3Service) copyParts(ctx context.Context, input CopyObjectInput, size int64, createInput *s3.CreateMultipartUploadInput) (*CopyObjectOutput, error) {
+	partSize := partSizeFor(size, DefaultCopyPartSize)
+	concurrency := s.multipartOptions().concurrency
+
+	s.logger.Debug("Starting multipart copy",
+		"sourceBucket", input.SourceBucket,
+		"sourceKey", input.SourceKey,
+		"size", size,
+		"partSize", partSize,
+	)
+
+	created, err := s.client.CreateMultipartUpload(ctx, createInput)
+	if err != nil {
+		return nil, convertS3Error("create multipart copy", err).(*s3cerrors.S3CError).
+			WithDetails(copyDetails(input))
+	}
+	uploadID := aws.ToString(created.UploadId)
+
+	parts, err := s.copyPartRanges(ctx, input, uploadID, size, partSize, concurrency)
+	if err != nil {
+		s.abortMultipartUpload(ctx, input.DestinationBucket, input.DestinationKey, uploadID)
+		return nil, convertS3Error("copy part", err).(*s3cerrors.S3CError).
+			WithDetails(copyDetails(input))
+	}
+
+	result, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(input.DestinationBucket),
+		Key:             aws.String(input.DestinationKey),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		s.abortMultipartUpload(ctx, input.DestinationBucket, input.DestinationKey, uploadID)
+		return nil, convertS3Error("complete multipart copy", err).(*s3cerrors.S3CError).
+			WithDetails(copyDetails(input))
+	}
+
+	s.logger.Info("Completed multipart copy",
+		"sourceKey", input.SourceKey,
+		"destinationKey", input.DestinationKey,
+		"size", size,
+		"parts", len(parts),
+	)
+
+	return &CopyObjectOutput{
+		SourceKey:      input.SourceKey,
+		DestinationKey: input.DestinationKey,
+		ETag:           aws.ToString(result.ETag),
+		Size:           size,
+		Multipart:      true,
+	}, nil
+}
+
+// copyPartRanges issues UploadPartCopy requests for each byte range with bounded concurrency
+func (s *AWSS3Service) copyPartRanges(ctx context.Context, input CopyObjectInput, uploadID string, size, partSize int64, concurrency int) ([]types.CompletedPart, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		parts    []types.CompletedPart
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	source := input.versionedCopySource()
+	sse, sourceKey, err := input.encryptionFields()
+	if err != nil {
+		return nil, err
+	}
+	var copySourceIfMatch *string
+	if input.sourceETag != "" {
+		copySourceIfMatch = aws.String(input.sourceETag)
+	}
+
+	for partNumber, start := int32(1), int64(0); start < size; partNumber, start = partNumber+1, start+partSize {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		end := min(start+partSize, size) - 1
+
+		wg.Add(1)
+		go func(partNumber int32, byteRange string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+				Bucket:          aws.String(input.DestinationBucket),
+				Key:             aws.String(input.DestinationKey),
+				UploadId:        aws.String(uploadID),
+				PartNumber:      aws.Int32(partNumber),
+				CopySource:      aws.String(source),
+				CopySourceRange: aws.String(byteRange),
+
+				SSECustomerAlgorithm:           sse.customer.algorithm,
+				SSECustomerKey:                 sse.customer.key,
+				SSECustomerKeyMD5:              sse.customer.keyMD5,
+				CopySourceSSECustomerAlgorithm: sourceKey.algorithm,
+				CopySourceSSECustomerKey:       sourceKey.key,
+				CopySourceSSECustomerKeyMD5:    sourceKey.keyMD5,
+				CopySourceIfMatch:              copySourceIfMatch,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			parts = append(parts, types.CompletedPart{
+				ETag:       result.CopyPartResult.ETag,
+				PartNumber: aws.Int32(partNumber),
+			})
+		}(partNumber, fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// The caller's context may have ended before every part was issued
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(parts, func(a, b types.CompletedPart) int {
+		return int(aws.ToInt32(a.PartNumber) - aws.ToInt32(b.PartNumber))
+	})
+	return parts, nil
+}
+
+// copySource builds the URL-encoded CopySource value, keeping key path separators intact
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
+
+// versionedCopySource returns the CopySource value for the input, pinned to the source version if one is set
+func (input CopyObjectInput) versionedCopySource() string {
+	source := copySource(input.SourceBucket, input.SourceKey)
+	if input.SourceVersionID != "" {
+		source += "?versionId=" + url.QueryEscape(input.SourceVersionID)
+	}
+	return source
+}
+
+// encryptionFields resolves the destination encryption and the SSE-C key of the source
+func (input CopyObjectInput) encryptionFields() (sseFields, sseCustomerFields, error) {
+	sse, err := input.Encryption.fields()
+	if err != nil {
+		return sseFields{}, sseCustomerFields{}, err
+	}
+	sourceKey, err := customerKeyFields(input.SourceSSECustomerKey)
+	if err != nil {
+		return sseFields{}, sseCustomerFields{}, err
+	}
+	return sse, sourceKey, nil
+}
+
+// encodeTagSet converts a tag set into the URL query format used by the Tagging header
+func encodeTagSet(tags []types.Tag) string {
+	values := url.Values{}
+	for _, tag := range tags {
+		values.Add(aws.ToString(tag.Key), aws.ToString(tag.Value))
+	}
+	return values.Encode()
+}
+
+// copyDetails returns the error details shared by copy operations
+func copyDetails(input CopyObjectInput) map[string]any {
+	return map[string]any{
+		"sourceBucket":      input.SourceBucket,
+		"sourceKey":         input.SourceKey,
+		"sourceVersionId":   input.SourceVersionID,
+		"destinationBucket": input.DestinationBucket,
+		"destinationKey":    input.DestinationKey,
+	}
+}
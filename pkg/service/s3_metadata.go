@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// HeadObjectInput represents input for inspecting an object
+type HeadObjectInput struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"`
+
+	// SSECustomerKey is the base64 key an SSE-C object was written with
+	SSECustomerKey string `json:"-"`
+}
+
+// ObjectChecksums holds the checksums S3 stored for an object
+type ObjectChecksums struct {
+	Type      string `json:"type,omitempty"` // FULL_OBJECT or COMPOSITE
+	CRC32     string `json:"crc32,omitempty"`
+	CRC32C    string `json:"crc32c,omitempty"`
+	CRC64NVME string `json:"crc64nvme,omitempty"`
+	SHA1      string `json:"sha1,omitempty"`
+	SHA256    string `json:"sha256,omitempty"`
+}
+
+// ObjectLockState describes the object lock settings applied to an object version
+type ObjectLockState struct {
+	Mode            string `json:"mode,omitempty"` // GOVERNANCE or COMPLIANCE
+	RetainUntilDate string `json:"retainUntilDate,omitempty"`
+	LegalHold       string `json:"legalHold,omitempty"` // ON or OFF
+}
+
+// ObjectDetails represents everything HeadObject reports about an object
+type ObjectDetails struct {
+	Bucket       string `json:"bucket"`
+	Key          string `json:"key"`
+	VersionID    string `json:"versionId,omitempty"`
+	Size         int64  `json:"size"`
+	LastModified string `json:"lastModified,omitempty"`
+	ETag         string `json:"etag,omitempty"`
+	PartsCount   int32  `json:"partsCount,omitempty"`
+
+	// System headers
+	ContentType             string `json:"contentType,omitempty"`
+	ContentEncoding         string `json:"contentEncoding,omitempty"`
+	ContentDisposition      string `json:"contentDisposition,omitempty"`
+	ContentLanguage         string `json:"contentLanguage,omitempty"`
+	CacheControl            string `json:"cacheControl,omitempty"`
+	Expires                 string `json:"expires,omitempty"`
+	WebsiteRedirectLocation string `json:"websiteRedirectLocation,omitempty"`
+
+	// Storage and encryption
+	StorageClass         string `json:"storageClass,omitempty"`
+	ServerSideEncryption string `json:"serverSideEncryption,omitempty"`
+	SSEKMSKeyID          string `json:"sseKmsKeyId,omitempty"`
+	BucketKeyEnabled     bool   `json:"bucketKeyEnabled,omitempty"`
+	SSECustomerAlgorithm string `json:"sseCustomerAlgorithm,omitempty"`
+	ClientEncryption     string `json:"clientEncryption,omitempty"` // client-side key source, Size is the encrypted size
+	ReplicationStatus    string `json:"replicationStatus,omitempty"`
+	Expiration           string `json:"expiration,omitempty"` // lifecycle expiry rule, if any
+
+	Restore    *RestoreStatus    `json:"restore,omitempty"` // archived objects with a restore request only
+	Checksums  *ObjectChecksums  `json:"checksums,omitempty"`
+	ObjectLock *ObjectLockState  `json:"objectLock,omitempty"`
+	Metadata   map[string]string `json:"metadata"` // user x-amz-meta-* values
+}
+
+// MetadataUpdate describes header and metadata changes applied in place.
+// Nil header fields keep their current value and an empty string removes the header.
+type MetadataUpdate struct {
+	ContentType             *string `json:"contentType,omitempty"`
+	ContentEncoding         *string `json:"contentEncoding,omitempty"`
+	ContentDisposition      *string `json:"contentDisposition,omitempty"`
+	ContentLanguage         *string `json:"contentLanguage,omitempty"`
+	CacheControl            *string `json:"cacheControl,omitempty"`
+	WebsiteRedirectLocation *string `json:"websiteRedirectLocation,omitempty"`
+
+	// Metadata replaces all user metadata when set, nil keeps it
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Storage class, encryption and tags are kept unless set explicitly
+	StorageClass         string            `json:"storageClass,omitempty"`
+	ServerSideEncryption string            `json:"serverSideEncryption,omitempty"` // AES256 or aws:kms
+	SSEKMSKeyID          string            `json:"sseKmsKeyId,omitempty"`
+	Tags                 map[string]string `json:"tags,omitempty"`
+}
+
+// UpdateMetadataInput represents input for rewriting the metadata of one object or a prefix
+type UpdateMetadataInput struct {
+	Bucket string         `json:"bucket"`
+	Key    string         `json:"key,omitempty"`    // single object
+	Prefix string         `json:"prefix,omitempty"` // every object under the prefix, when Key is empty
+	Update MetadataUpdate `json:"update"`
+}
+
+// UpdateMetadataOutput summarises an in-place metadata update key by key
+type UpdateMetadataOutput struct {
+	Updated []string      `json:"updated"`
+	Failed  []ObjectError `json:"failed,omitempty"`
+}
+
+// S3ObjectInspector interface for reading object metadata without its body
+type S3ObjectInspector interface {
+	HeadObject(ctx context.Context, input HeadObjectInput) (*ObjectDetails, error)
+}
+
+// S3MetadataEditor interface for rewriting object headers and metadata in place
+type S3MetadataEditor interface {
+	UpdateMetadata(ctx context.Context, input UpdateMetadataInput) (*UpdateMetadataOutput, error)
+}
+
+// HeadObject returns the system headers, encryption, checksums, lock state and
+// user metadata of an object without downloading it
+func (s *AWSS3Service) HeadObject(ctx context.Context, input HeadObjectInput) (*ObjectDetails, error) {
+	s.logger.Debug("Inspecting S3 object", "bucket", input.Bucket, "key", input.Key, "versionId", input.VersionID)
+
+	headInput := &s3.HeadObjectInput{
+		Bucket:       aws.String(input.Bucket),
+		Key:          aws.String(input.Key),
+		ChecksumMode: types.ChecksumModeEnabled, // checksums are only returned on request
+	}
+	if input.VersionID != "" {
+		headInput.VersionId = aws.String(input.VersionID)
+	}
+	customer, err := customerKeyFields(input.SSECustomerKey)
+	if err != nil {
+		return nil, err.(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket": input.Bucket,
+				"key":    input.Key,
+			})
+	}
+	headInput.SSECustomerAlgorithm = customer.algorithm
+	headInput.SSECustomerKey = customer.key
+	headInput.SSECustomerKeyMD5 = customer.keyMD5
+
+	result, err := s.client.HeadObject(ctx, headInput)
+	if err != nil {
+		return nil, convertS3Error("head object", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket":    input.Bucket,
+				"key":       input.Key,
+				"versionId": input.VersionID,
+			})
+	}
+
+	return objectDetailsFromHead(input.Bucket, input.Key, result), nil
+}
+
+// objectDetailsFromHead converts a HeadObject response into ObjectDetails
+func objectDetailsFromHead(bucket, key string, result *s3.HeadObjectOutput) *ObjectDetails {
+	details := &ObjectDetails{
+		Bucket:                  bucket,
+		Key:                     key,
+		VersionID:               aws.ToString(result.VersionId),
+		Size:                    aws.ToInt64(result.ContentLength),
+		ETag:                    aws.ToString(result.ETag),
+		PartsCount:              aws.ToInt32(result.PartsCount),
+		ContentType:             aws.ToString(result.ContentType),
+		ContentEncoding:         aws.ToString(result.ContentEncoding),
+		ContentDisposition:      aws.ToString(result.ContentDisposition),
+		ContentLanguage:         aws.ToString(result.ContentLanguage),
+		CacheControl:            aws.ToString(result.CacheControl),
+		Expires:                 aws.ToString(result.ExpiresString),
+		WebsiteRedirectLocation: aws.ToString(result.WebsiteRedirectLocation),
+		StorageClass:            string(result.StorageClass),
+		ServerSideEncryption:    string(result.ServerSideEncryption),
+		SSEKMSKeyID:             aws.ToString(result.SSEKMSKeyId),
+		BucketKeyEnabled:        aws.ToBool(result.BucketKeyEnabled),
+		SSECustomerAlgorithm:    aws.ToString(result.SSECustomerAlgorithm),
+		ReplicationStatus:       string(result.ReplicationStatus),
+		Expiration:              aws.ToString(result.Expiration),
+		Restore:                 parseRestoreHeader(aws.ToString(result.Restore)),
+		Metadata:                result.Metadata,
+	}
+
+	if details.Metadata == nil {
+		details.Metadata = map[string]string{}
+	}
+	if isClientEncrypted(details.Metadata) {
+		details.ClientEncryption = details.Metadata[metaClientKeySource]
+	}
+	if result.LastModified != nil {
+		details.LastModified = result.LastModified.Format(time.RFC3339)
+	}
+	details.StorageClass = listedStorageClass(details.StorageClass)
+
+	checksums := ObjectChecksums{
+		Type:      string(result.ChecksumType),
+		CRC32:     aws.ToString(result.ChecksumCRC32),
+		CRC32C:    aws.ToString(result.ChecksumCRC32C),
+		CRC64NVME: aws.ToString(result.ChecksumCRC64NVME),
+		SHA1:      aws.ToString(result.ChecksumSHA1),
+		SHA256:    aws.ToString(result.ChecksumSHA256),
+	}
+	if checksums != (ObjectChecksums{}) {
+		details.Checksums = &checksums
+	}
+
+	lock := ObjectLockState{
+		Mode:      string(result.ObjectLockMode),
+		LegalHold: string(result.ObjectLockLegalHoldStatus),
+	}
+	if result.ObjectLockRetainUntilDate != nil {
+		lock.RetainUntilDate = result.ObjectLockRetainUntilDate.Format(time.RFC3339)
+	}
+	if lock != (ObjectLockState{}) {
+		details.ObjectLock = &lock
+	}
+
+	return details
+}
+
+// UpdateMetadata rewrites system headers and user metadata by copying objects onto
+// themselves with MetadataDirective=REPLACE. A single key fails with an error, while
+// failures under a prefix are reported per key.
+func (s *AWSS3Service) UpdateMetadata(ctx context.Context, input UpdateMetadataInput) (*UpdateMetadataOutput, error) {
+	output := &UpdateMetadataOutput{Updated: []string{}}
+
+	if input.Key != "" {
+		if err := s.updateObjectMetadata(ctx, input.Bucket, input.Key, input.Update); err != nil {
+			return nil, err
+		}
+		output.Updated = append(output.Updated, input.Key)
+		return output, nil
+	}
+
+	prefix := normalizePrefix(input.Prefix)
+	if prefix == "" {
+		return nil, s3cerrors.NewValidationError(s3cerrors.CodeMissingField, "Either key or prefix is required")
+	}
+
+	err := s.walkPrefix(ctx, input.Bucket, prefix, func(keys []string) error {
+		// Folder markers carry no content, so their headers are left alone
+		keys = slices.DeleteFunc(keys, func(key string) bool { return strings.HasSuffix(key, "/") })
+
+		errs := forEachKey(ctx, keys, func(_ int, key string) error {
+			return s.updateObjectMetadata(ctx, input.Bucket, key, input.Update)
+		})
+
+		for i, key := range keys {
+			if errs[i] != nil {
+				output.Failed = append(output.Failed, newObjectError(key, errs[i]))
+				continue
+			}
+			output.Updated = append(output.Updated, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Finished metadata update",
+		"bucket", input.Bucket,
+		"prefix", prefix,
+		"updated", len(output.Updated),
+		"failed", len(output.Failed),
+	)
+	return output, nil
+}
+
+// updateObjectMetadata copies a single object onto itself with replaced headers
+func (s *AWSS3Service) updateObjectMetadata(ctx context.Context, bucket, key string, update MetadataUpdate) error {
+	details := map[string]any{
+		"bucket": bucket,
+		"key":    key,
+	}
+
+	current, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return convertS3Error("update metadata", err).(*s3cerrors.S3CError).WithDetails(details)
+	}
+
+	return s.rewriteObject(ctx, bucket, key, current, update)
+}
+
+// rewriteObject copies an object onto itself with the headers of current merged with update.
+// Objects too large for a single copy are rewritten part by part. Every copy request is
+// conditional on the ETag of current, so a concurrent overwrite fails the rewrite instead
+// of being lost. Like any copy, the rewrite resets the object ACL to the bucket default.
+func (s *AWSS3Service) rewriteObject(ctx context.Context, bucket, key string, current *s3.HeadObjectOutput, update MetadataUpdate) error {
+	details := map[string]any{
+		"bucket": bucket,
+		"key":    key,
+	}
+
+	headers := mergeMetadataUpdate(current, update)
+	size := aws.ToInt64(current.ContentLength)
+
+	if size > maxSingleCopySize {
+		createInput := &s3.CreateMultipartUploadInput{
+			Bucket:                  aws.String(bucket),
+			Key:                     aws.String(key),
+			ContentType:             headers.ContentType,
+			ContentEncoding:         headers.ContentEncoding,
+			ContentDisposition:      headers.ContentDisposition,
+			ContentLanguage:         headers.ContentLanguage,
+			CacheControl:            headers.CacheControl,
+			Expires:                 headers.Expires,
+			WebsiteRedirectLocation: headers.WebsiteRedirectLocation,
+			Metadata:                headers.Metadata,
+			StorageClass:            headers.StorageClass,
+			ServerSideEncryption:    headers.ServerSideEncryption,
+			SSEKMSKeyId:             headers.SSEKMSKeyId,
+			BucketKeyEnabled:        headers.BucketKeyEnabled,
+			Tagging:                 headers.Tagging,
+		}
+		if update.Tags == nil {
+			// Multipart uploads start untagged, so carry the current tags over
+			tagging, err := s.client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(key),
+			})
+			if err != nil {
+				return convertS3Error("update metadata", err).(*s3cerrors.S3CError).WithDetails(details)
+			}
+			if len(tagging.TagSet) > 0 {
+				createInput.Tagging = aws.String(encodeTagSet(tagging.TagSet))
+			}
+		}
+
+		_, err := s.copyParts(ctx, CopyObjectInput{
+			SourceBucket:      bucket,
+			SourceKey:         key,
+			DestinationBucket: bucket,
+			DestinationKey:    key,
+			sourceETag:        aws.ToString(current.ETag),
+		}, size, createInput)
+		return err
+	}
+
+	headers.Bucket = aws.String(bucket)
+	headers.Key = aws.String(key)
+	headers.CopySource = aws.String(copySource(bucket, key))
+	headers.CopySourceIfMatch = current.ETag // fail rather than overwrite a concurrent change
+
+	if _, err := s.client.CopyObject(ctx, headers); err != nil {
+		return convertS3Error("update metadata", err).(*s3cerrors.S3CError).WithDetails(details)
+	}
+
+	s.logger.Debug("Updated object metadata", "bucket", bucket, "key", key)
+	return nil
+}
+
+// mergeMetadataUpdate builds the replacement headers for a CopyObject request, starting from
+// the current object so that anything the update leaves unset keeps its value
+func mergeMetadataUpdate(current *s3.HeadObjectOutput, update MetadataUpdate) *s3.CopyObjectInput {
+	headers := &s3.CopyObjectInput{
+		MetadataDirective:       types.MetadataDirectiveReplace,
+		ContentType:             current.ContentType,
+		ContentEncoding:         current.ContentEncoding,
+		ContentDisposition:      current.ContentDisposition,
+		ContentLanguage:         current.ContentLanguage,
+		CacheControl:            current.CacheControl,
+		Expires:                 current.Expires,
+		WebsiteRedirectLocation: current.WebsiteRedirectLocation,
+		Metadata:                current.Metadata,
+		StorageClass:            current.StorageClass,
+		ServerSideEncryption:    current.ServerSideEncryption,
+		SSEKMSKeyId:             current.SSEKMSKeyId,
+		BucketKeyEnabled:        current.BucketKeyEnabled,
+	}
+
+	replaceHeader := func(target **string, value *string) {
+		if value == nil {
+			return
+		}
+		if *value == "" {
+			*target = nil
+			return
+		}
+		*target = aws.String(*value)
+	}
+	replaceHeader(&headers.ContentType, update.ContentType)
+	replaceHeader(&headers.ContentEncoding, update.ContentEncoding)
+	replaceHeader(&headers.ContentDisposition, update.ContentDisposition)
+	replaceHeader(&headers.ContentLanguage, update.ContentLanguage)
+	replaceHeader(&headers.CacheControl, update.CacheControl)
+	replaceHeader(&headers.WebsiteRedirectLocation, update.WebsiteRedirectLocation)
+
+	if update.Metadata != nil {
+		headers.Metadata = withClientEncryptionMetadata(update.Metadata, current.Metadata)
+	}
+	if update.StorageClass != "" {
+		headers.StorageClass = types.StorageClass(update.StorageClass)
+	}
+	if update.ServerSideEncryption != "" || update.SSEKMSKeyID != "" {
+		// A KMS key on its own implies SSE-KMS
+		headers.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		if update.ServerSideEncryption != "" {
+			headers.ServerSideEncryption = types.ServerSideEncryption(update.ServerSideEncryption)
+		}
+		headers.SSEKMSKeyId = nil
+		headers.BucketKeyEnabled = nil
+		if update.SSEKMSKeyID != "" {
+			headers.SSEKMSKeyId = aws.String(update.SSEKMSKeyID)
+		}
+	}
+	// S3 rejects a KMS key ID alongside AES256 encryption
+	if !strings.HasPrefix(string(headers.ServerSideEncryption), "aws:kms") {
+		headers.SSEKMSKeyId = nil
+		headers.BucketKeyEnabled = nil
+	}
+	if update.Tags != nil {
+		headers.TaggingDirective = types.TaggingDirectiveReplace
+		headers.Tagging = aws.String(encodeTags(update.Tags))
+	}
+
+	return headers
+}
+
+// encodeTags converts a tag map into the URL query format used by the Tagging header
+func encodeTags(tags map[string]string) string {
+	return encodeTagSet(tagSet(tags))
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Multipart upload defaults and S3 limits
+const (
+	DefaultMultipartThreshold   int64 = 32 << 20 // 32 MiB
+	DefaultMultipartPartSize    int64 = 16 << 20 // 16 MiB
+	DefaultMultipartConcurrency       = 4
+
+	// S3 rejects parts smaller than 5 MiB (except the last one) and more than 10,000 parts
+	minMultipartPartSize int64 = 5 << 20
+	maxMultipartParts          = 10000
+)
+
+// multipartOptions holds the resolved multipart upload settings
+type multipartOptions struct {
+	threshold   int64
+	partSize    int64
+	concurrency int
+}
+
+// multipartOptions resolves multipart settings from the service configuration
+func (s *AWSS3Service) multipartOptions() multipartOptions {
+	return resolveMultipartOptions(s.config)
+}
+
+// resolveMultipartOptions applies defaults and S3 limits to the configured multipart settings
+func resolveMultipartOptions(cfg S3Config) multipartOptions {
+	opts := multipartOptions{
+		threshold:   cfg.MultipartThreshold,
+		partSize:    cfg.MultipartPartSize,
+		concurrency: cfg.MultipartConcurrency,
+	}
+
+	if opts.partSize <= 0 {
+		opts.partSize = DefaultMultipartPartSize
+	}
+	opts.partSize = max(opts.partSize, minMultipartPartSize)
+
+	if opts.threshold <= 0 {
+		opts.threshold = DefaultMultipartThreshold
+	}
+	// A body smaller than one part never needs a multipart upload
+	opts.threshold = max(opts.threshold, opts.partSize)
+
+	if opts.concurrency <= 0 {
+		opts.concurrency = DefaultMultipartConcurrency
+	}
+
+	return opts
+}
+
+// partSizeFor grows the part size when a known body size would exceed the S3 part limit
+func partSizeFor(size, partSize int64) int64 {
+	if size <= 0 {
+		return partSize
+	}
+	// Round up so that size / partSize never exceeds maxMultipartParts
+	return max(partSize, (size+maxMultipartParts-1)/maxMultipartParts)
+}
+
+// readChunk reads up to n bytes from r, returning fewer only when r is exhausted
+func readChunk(r io.Reader, n int64) ([]byte, error) {
+	buf := make([]byte, n)
+	read, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return buf[:read], nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// readHead reads up to n bytes from r like readChunk, but sizes its buffer from
+// sizeHint (the declared body length, negative when unknown) instead of allocating
+// n bytes up front. Unknown-length bodies grow the buffer as data arrives.
+func readHead(r io.Reader, n, sizeHint int64) ([]byte, error) {
+	// The extra MinRead bytes let ReadFrom observe EOF without growing the buffer
+	buf := bytes.NewBuffer(make([]byte, 0, min(n, max(sizeHint, 0))+bytes.MinRead))
+	if _, err := buf.ReadFrom(io.LimitReader(r, n)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// uploadMultipart streams body to S3 using a multipart upload with parts sent concurrently
+func (s *AWSS3Service) uploadMultipart(ctx context.Context, input UploadObjectInput, body io.Reader, opts multipartOptions, sse sseFields) (*UploadObjectOutput, error) {
+	partSize := partSizeFor(input.Size, opts.partSize)
+
+	s.logger.Debug("Starting multipart upload",
+		"bucket", input.Bucket,
+		"key", input.Key,
+		"size", input.Size,
+		"partSize", partSize,
+		"concurrency", opts.concurrency,
+	)
+
+	createInput := &s3.CreateMultipartUploadInput{
+		Bucket:                  aws.String(input.Bucket),
+		Key:                     aws.String(input.Key),
+		ServerSideEncryption:    sse.mode,
+		SSEKMSKeyId:             sse.kmsKeyID,
+		SSEKMSEncryptionContext: sse.kmsContext,
+		BucketKeyEnabled:        sse.bucketKeyEnabled,
+		SSECustomerAlgorithm:    sse.customer.algorithm,
+		SSECustomerKey:          sse.customer.key,
+		SSECustomerKeyMD5:       sse.customer.keyMD5,
+	}
+	if input.ContentType != "" {
+		createInput.ContentType = aws.String(input.ContentType)
+	}
+	if len(input.Metadata) > 0 {
+		createInput.Metadata = input.Metadata
+	}
+	if len(input.Tags) > 0 {
+		createInput.Tagging = aws.String(encodeTags(input.Tags))
+	}
+	if input.StorageClass != "" {
+		createInput.StorageClass = types.StorageClass(input.StorageClass)
+	}
+
+	created, err := s.client.CreateMultipartUpload(ctx, createInput)
+	if err != nil {
+		return nil, convertS3Error("create multipart upload", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket": input.Bucket,
+				"key":    input.Key,
+			})
+	}
+	uploadID := aws.ToString(created.UploadId)
+
+	parts, size, err := s.uploadParts(ctx, input, uploadID, body, partSize, opts.concurrency, sse.customer)
+	if err != nil {
+		s.abortMultipartUpload(ctx, input.Bucket, input.Key, uploadID)
+
+		var s3cErr *s3cerrors.S3CError
+		if errors.As(err, &s3cErr) {
+			return nil, err
+		}
+		return nil, convertS3Error("upload part", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket":   input.Bucket,
+				"key":      input.Key,
+				"uploadId": uploadID,
+			})
+	}
+
+	result, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(input.Bucket),
+		Key:             aws.String(input.Key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		s.abortMultipartUpload(ctx, input.Bucket, input.Key, uploadID)
+		return nil, convertS3Error("complete multipart upload", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket":   input.Bucket,
+				"key":      input.Key,
+				"uploadId": uploadID,
+				"parts":    len(parts),
+			})
+	}
+
+	s.logger.Info("Completed multipart upload",
+		"bucket", input.Bucket,
+		"key", input.Key,
+		"size", size,
+		"parts", len(parts),
+	)
+
+	return &UploadObjectOutput{
+		Key:       input.Key,
+		ETag:      aws.ToString(result.ETag),
+		Size:      size,
+		Multipart: true,
+	}, nil
+}
+
+// uploadParts reads body in partSize chunks and uploads them with bounded concurrency.
+// At most concurrency parts are held in memory at any time. SSE-C uploads repeat
+// the customer key on every part.
+func (s *AWSS3Service) uploadParts(ctx context.Context, input UploadObjectInput, uploadID string, body io.Reader, partSize int64, concurrency int, customer sseCustomerFields) ([]types.CompletedPart, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		parts    []types.CompletedPart
+		firstErr error
+		total    int64
+	)
+	sem := make(chan struct{}, concurrency)
+
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+readLoop:
+	for partNumber := int32(1); ; partNumber++ {
+		// Acquire a slot before reading so buffered parts stay bounded
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break readLoop
+		}
+
+		chunk, err := readChunk(body, partSize)
+		if err != nil {
+			<-sem
+			setErr(s3cerrors.NewFileOperationError("read", "upload body", err).
+				WithDetails(map[string]any{
+					"bucket": input.Bucket,
+					"key":    input.Key,
+				}))
+			break
+		}
+		if len(chunk) == 0 && partNumber > 1 {
+			<-sem
+			break
+		}
+		// Only a non-empty chunk past the last allowed part is an error, so a body
+		// that fills exactly maxMultipartParts parts still completes
+		if partNumber > maxMultipartParts {
+			<-sem
+			setErr(s3cerrors.NewValidationError(s3cerrors.CodeOutOfRange, "Upload exceeds the maximum number of multipart parts").
+				WithDetails(map[string]any{
+					"bucket":   input.Bucket,
+					"key":      input.Key,
+					"maxParts": maxMultipartParts,
+					"partSize": partSize,
+				}).
+				WithSuggestion("Increase the multipart part size or provide the upload size in advance"))
+			break
+		}
+		total += int64(len(chunk))
+
+		wg.Add(1)
+		go func(partNumber int32, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+				Bucket:               aws.String(input.Bucket),
+				Key:                  aws.String(input.Key),
+				UploadId:             aws.String(uploadID),
+				PartNumber:           aws.Int32(partNumber),
+				Body:                 bytes.NewReader(data),
+				ContentLength:        aws.Int64(int64(len(data))),
+				SSECustomerAlgorithm: customer.algorithm,
+				SSECustomerKey:       customer.key,
+				SSECustomerKeyMD5:    customer.keyMD5,
+			})
+			if err != nil {
+				setErr(err)
+				return
+			}
+
+			mu.Lock()
+			parts = append(parts, types.CompletedPart{
+				ETag:       result.ETag,
+				PartNumber: aws.Int32(partNumber),
+			})
+			mu.Unlock()
+		}(partNumber, chunk)
+
+		if int64(len(chunk)) < partSize {
+			break
+		}
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, 0, firstErr
+	}
+	// The caller's context may have ended before the whole body was read
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// CompleteMultipartUpload requires parts in ascending order
+	slices.SortFunc(parts, func(a, b types.CompletedPart) int {
+		return int(aws.ToInt32(a.PartNumber) - aws.ToInt32(b.PartNumber))
+	})
+
+	return parts, total, nil
+}
+
+// abortMultipartUpload discards an unfinished multipart upload so its parts are not billed
+func (s *AWSS3Service) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) {
+	// The caller's context may already be cancelled, but the abort must still be sent
+	_, err := s.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		s.logger.Warn("Failed to abort multipart upload",
+			"error", err,
+			"bucket", bucket,
+			"key", key,
+			"uploadId", uploadID,
+		)
+	}
+}
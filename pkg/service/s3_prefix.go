@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Prefix operation limits
+const (
+	// maxDeleteBatchSize is the most keys a single DeleteObjects request accepts
+	maxDeleteBatchSize = 1000
+
+	// DefaultPrefixCopyConcurrency is the number of objects copied at once under a prefix
+	DefaultPrefixCopyConcurrency = 8
+)
+
+// CopyPrefixInput represents input for copying or moving every object under a prefix
+type CopyPrefixInput struct {
+	SourceBucket      string `json:"sourceBucket"`
+	SourcePrefix      string `json:"sourcePrefix"`
+	DestinationBucket string `json:"destinationBucket"`
+	DestinationPrefix string `json:"destinationPrefix"`
+
+	// Encryption and SourceSSECustomerKey apply to every copied object, see CopyObjectInput
+	Encryption           *ServerSideEncryption `json:"encryption,omitempty"`
+	SourceSSECustomerKey string                `json:"-"`
+}
+
+// CopyPrefixOutput summarises a prefix copy or move key by key
+type CopyPrefixOutput struct {
+	SourcePrefix      string             `json:"sourcePrefix"`
+	DestinationPrefix string             `json:"destinationPrefix"`
+	Objects           []CopyObjectOutput `json:"objects"`
+	Failed            []ObjectError      `json:"failed,omitempty"`
+}
+
+// DeletePrefixInput represents input for deleting every object under a prefix
+type DeletePrefixInput struct {
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+	DryRun bool   `json:"dryRun,omitempty"` // only list the keys that would be deleted
+}
+
+// DeletePrefixOutput summarises a prefix delete key by key
+type DeletePrefixOutput struct {
+	Prefix  string        `json:"prefix"`
+	Deleted []string      `json:"deleted"` // keys that would be deleted on a dry run
+	Failed  []ObjectError `json:"failed,omitempty"`
+	DryRun  bool          `json:"dryRun,omitempty"`
+}
+
+// S3PrefixCopier interface for recursive folder copy and move operations
+type S3PrefixCopier interface {
+	CopyPrefix(ctx context.Context, input CopyPrefixInput) (*CopyPrefixOutput, error)
+	MovePrefix(ctx context.Context, input CopyPrefixInput) (*CopyPrefixOutput, error)
+}
+
+// S3PrefixDeleter interface for recursive folder deletion
+type S3PrefixDeleter interface {
+	DeletePrefix(ctx context.Context, input DeletePrefixInput) (*DeletePrefixOutput, error)
+}
+
+// CopyPrefix copies every object under a prefix to a new prefix, possibly in another bucket
+func (s *AWSS3Service) CopyPrefix(ctx context.Context, input CopyPrefixInput) (*CopyPrefixOutput, error) {
+	return s.transferPrefix(ctx, input, false)
+}
+
+// MovePrefix moves every object under a prefix to a new prefix, which is how folders are renamed.
+// Sources are deleted only after their copy succeeded, so keys that fail stay where they were.
+func (s *AWSS3Service) MovePrefix(ctx context.Context, input CopyPrefixInput) (*CopyPrefixOutput, error) {
+	return s.transferPrefix(ctx, input, true)
+}
+
+// transferPrefix walks the source prefix page by page, copying each page concurrently
+// and, for moves, deleting the copied sources of the page in a single batch
+func (s *AWSS3Service) transferPrefix(ctx context.Context, input CopyPrefixInput, move bool) (*CopyPrefixOutput, error) {
+	input.SourcePrefix = normalizePrefix(input.SourcePrefix)
+	input.DestinationPrefix = normalizePrefix(input.DestinationPrefix)
+
+	if err := validatePrefixTransfer(input); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Transferring S3 prefix",
+		"sourceBucket", input.SourceBucket,
+		"sourcePrefix", input.SourcePrefix,
+		"destinationBucket", input.DestinationBucket,
+		"destinationPrefix", input.DestinationPrefix,
+		"move", move,
+	)
+
+	output := &CopyPrefixOutput{
+		SourcePrefix:      input.SourcePrefix,
+		DestinationPrefix: input.DestinationPrefix,
+		Objects:           []CopyObjectOutput{},
+	}
+
+	err := s.walkPrefix(ctx, input.SourceBucket, input.SourcePrefix, func(keys []string) error {
+		copied, failed := s.copyKeys(ctx, input, keys)
+		output.Failed = append(output.Failed, failed...)
+
+		if !move {
+			output.Objects = append(output.Objects, copied...)
+			return nil
+		}
+
+		sources := make([]string, len(copied))
+		for i, obj := range copied {
+			sources[i] = obj.SourceKey
+		}
+		deleted, deleteFailed, err := s.deleteKeys(ctx, input.SourceBucket, sources)
+		if err != nil {
+			return err
+		}
+		output.Failed = append(output.Failed, deleteFailed...)
+
+		// Only objects whose source is gone count as moved
+		removed := make(map[string]bool, len(deleted))
+		for _, key := range deleted {
+			removed[key] = true
+		}
+		for _, obj := range copied {
+			if removed[obj.SourceKey] {
+				output.Objects = append(output.Objects, obj)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Finished S3 prefix transfer",
+		"sourceBucket", input.SourceBucket,
+		"sourcePrefix", input.SourcePrefix,
+		"destinationBucket", input.DestinationBucket,
+		"destinationPrefix", input.DestinationPrefix,
+		"move", move,
+		"succeeded", len(output.Objects),
+		"failed", len(output.Failed),
+	)
+
+	return output, nil
+}
+
+// DeletePrefix deletes every object under a prefix, including nested folders and folder markers
+func (s *AWSS3Service) DeletePrefix(ctx context.Context, input DeletePrefixInput) (*DeletePrefixOutput, error) {
+	prefix := normalizePrefix(input.Prefix)
+	if prefix == "" {
+		return nil, s3cerrors.NewMissingFieldError("prefix").
+			WithSuggestion("Delete the bucket instead to remove every object in it")
+	}
+
+	s.logger.Debug("Deleting S3 prefix",
+		"bucket", input.Bucket,
+		"prefix", prefix,
+		"dryRun", input.DryRun,
+	)
+
+	output := &DeletePrefixOutput{
+		Prefix:  prefix,
+		Deleted: []string{},
+		DryRun:  input.DryRun,
+	}
+
+	err := s.walkPrefix(ctx, input.Bucket, prefix, func(keys []string) error {
+		if input.DryRun {
+			output.Deleted = append(output.Deleted, keys...)
+			return nil
+		}
+
+		deleted, failed, err := s.deleteKeys(ctx, input.Bucket, keys)
+		output.Deleted = append(output.Deleted, deleted...)
+		output.Failed = append(output.Failed, failed...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Finished S3 prefix delete",
+		"bucket", input.Bucket,
+		"prefix", prefix,
+		"dryRun", input.DryRun,
+		"deleted", len(output.Deleted),
+		"failed", len(output.Failed),
+	)
+
+	return output, nil
+}
+
+// copyKeys copies a page of source keys with bounded concurrency, preserving their order
+func (s *AWSS3Service) copyKeys(ctx context.Context, input CopyPrefixInput, keys []string) ([]CopyObjectOutput, []ObjectError) {
+	results := make([]*CopyObjectOutput, len(keys))
+	errs := forEachKey(ctx, keys, func(i int, key string) error {
+		var err error
+		results[i], err = s.CopyObject(ctx, CopyObjectInput{
+			SourceBucket:      input.SourceBucket,
+			SourceKey:         key,
+			DestinationBucket: input.DestinationBucket,
+			DestinationKey:    input.DestinationPrefix + strings.TrimPrefix(key, input.SourcePrefix),
+
+			Encryption:           input.Encryption,
+			SourceSSECustomerKey: input.SourceSSECustomerKey,
+		})
+		return err
+	})
+
+	var copied []CopyObjectOutput
+	var failed []ObjectError
+	for i, key := range keys {
+		if errs[i] != nil {
+			failed = append(failed, newObjectError(key, errs[i]))
+			continue
+		}
+		copied = append(copied, *results[i])
+	}
+	return copied, failed
+}
+
+// forEachKey runs fn for a page of keys with bounded concurrency and returns the errors by index.
+// fn also receives the index of key so callers can store results alongside the errors.
+// Once ctx is done no further keys are started; they report the context error instead.
+func forEachKey(ctx context.Context, keys []string, fn func(i int, key string) error) []error {
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, DefaultPrefixCopyConcurrency)
+	for i, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			for j := i; j < len(keys); j++ {
+				errs[j] = err
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(i, key)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// walkPrefix lists every key under prefix without a delimiter and calls fn once per page
+func (s *AWSS3Service) walkPrefix(ctx context.Context, bucket, prefix string, fn func(keys []string) error) error {
+	listInput := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		MaxKeys: aws.Int32(maxDeleteBatchSize),
+	}
+	if prefix != "" {
+		listInput.Prefix = aws.String(prefix)
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, listInput)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return convertS3Error("list objects", err).(*s3cerrors.S3CError).
+				WithDetails(map[string]any{
+					"bucket": bucket,
+					"prefix": prefix,
+				})
+		}
+
+		keys := make([]string, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		if err := fn(keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteKeys removes keys in DeleteObjects batches of up to 1000, returning the per-key outcome.
+// An error is returned only when a whole batch request fails.
+func (s *AWSS3Service) deleteKeys(ctx context.Context, bucket string, keys []string) ([]string, []ObjectError, error) {
+	objects := make([]types.ObjectIdentifier, len(keys))
+	for i, key := range keys {
+		objects[i] = types.ObjectIdentifier{Key: aws.String(key)}
+	}
+	return s.deleteObjectIdentifiers(ctx, bucket, objects, false)
+}
+
+// deleteObjectIdentifiers removes objects, or specific versions of them, in batches of up to 1000.
+// Versions under GOVERNANCE retention are only removed when bypassGovernance is set.
+func (s *AWSS3Service) deleteObjectIdentifiers(ctx context.Context, bucket string, objects []types.ObjectIdentifier, bypassGovernance bool) ([]string, []ObjectError, error) {
+	var deleted []string
+	var failed []ObjectError
+
+	for batch := range slices.Chunk(objects, maxDeleteBatchSize) {
+		s3Input := &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: batch,
+				Quiet:   aws.Bool(false), // Report deleted keys as well as errors
+			},
+		}
+		if bypassGovernance {
+			s3Input.BypassGovernanceRetention = aws.Bool(true)
+		}
+
+		result, err := s.client.DeleteObjects(ctx, s3Input)
+		if err != nil {
+			return deleted, failed, convertS3Error("delete objects", err).(*s3cerrors.S3CError).
+				WithDetails(map[string]any{
+					"bucket": bucket,
+					"count":  len(batch),
+				})
+		}
+
+		for _, obj := range result.Deleted {
+			deleted = append(deleted, aws.ToString(obj.Key))
+		}
+		for _, objErr := range result.Errors {
+			failure := ObjectError{
+				Key:     aws.ToString(objErr.Key),
+				Code:    aws.ToString(objErr.Code),
+				Message: aws.ToString(objErr.Message),
+			}
+			if isObjectLockDenial(failure.Message) {
+				// Locked versions come back as AccessDenied, report them apart from permission problems
+				failure.Code = string(s3cerrors.CodeS3ObjectLocked)
+			}
+			failed = append(failed, failure)
+		}
+	}
+
+	return deleted, failed, nil
+}
+
+// validatePrefixTransfer rejects transfers whose destination lies inside the source,
+// which would otherwise copy the copies while the source is still being listed
+func validatePrefixTransfer(input CopyPrefixInput) error {
+	if input.SourceBucket != input.DestinationBucket {
+		return nil
+	}
+	if strings.HasPrefix(input.DestinationPrefix, input.SourcePrefix) {
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Destination folder must not be inside the source folder").
+			WithDetails(map[string]any{
+				"bucket":            input.SourceBucket,
+				"sourcePrefix":      input.SourcePrefix,
+				"destinationPrefix": input.DestinationPrefix,
+			}).
+			WithSuggestion("Choose a destination outside the source folder")
+	}
+	return nil
+}
+
+// normalizePrefix makes a folder prefix end with a slash so "reports" does not match "reports-old/"
+func normalizePrefix(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+	return prefix + "/"
+}
+
+// newObjectError builds a per-key error entry, keeping the s3c error code when available
+func newObjectError(key string, err error) ObjectError {
+	var s3cErr *s3cerrors.S3CError
+	if errors.As(err, &s3cErr) {
+		return ObjectError{Key: key, Code: string(s3cErr.Code), Message: s3cErr.Message}
+	}
+	return ObjectError{Key: key, Code: string(s3cerrors.CodeInternalError), Message: err.Error()}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Presigned URL lifetimes. SigV4 signatures are valid for at most seven days.
+const (
+	DefaultPresignExpiry = 1 * time.Hour
+	MaxPresignExpiry     = 7 * 24 * time.Hour
+)
+
+// PresignGetObjectInput represents input for presigning an object download
+type PresignGetObjectInput struct {
+	Bucket             string        `json:"bucket"`
+	Key                string        `json:"key"`
+	VersionID          string        `json:"versionId,omitempty"`
+	Expires            time.Duration `json:"-"`                            // DefaultPresignExpiry when zero
+	ContentDisposition string        `json:"contentDisposition,omitempty"` // overrides the stored header, e.g. attachment; filename="a.txt"
+}
+
+// PresignedURL is a signed request that can be used without credentials until it expires
+type PresignedURL struct {
+	URL          string              `json:"url"`
+	Method       string              `json:"method"`
+	ExpiresAt    time.Time           `json:"expiresAt"`
+	SignedHeader map[string][]string `json:"signedHeader,omitempty"` // headers the caller must send with the request
+}
+
+// PresignPutObjectInput represents input for presigning a single object upload
+type PresignPutObjectInput struct {
+	Bucket      string        `json:"bucket"`
+	Key         string        `json:"key"`
+	Expires     time.Duration `json:"-"`                     // DefaultPresignExpiry when zero
+	ContentType string        `json:"contentType,omitempty"` // when set, the upload must send the same Content-Type
+}
+
+// PresignPostObjectInput represents input for a browser POST upload policy
+type PresignPostObjectInput struct {
+	Bucket           string        `json:"bucket"`
+	KeyPrefix        string        `json:"keyPrefix"` // uploaded keys must start with this prefix
+	Expires          time.Duration `json:"-"`         // DefaultPresignExpiry when zero
+	ContentType      string        `json:"contentType,omitempty"`
+	MinContentLength int64         `json:"minContentLength,omitempty"`
+	MaxContentLength int64         `json:"maxContentLength,omitempty"` // no size limit when zero
+}
+
+// PresignedPost is a signed POST policy with the form fields an upload must send.
+// The file itself goes last, in a form field named "file".
+type PresignedPost struct {
+	URL       string            `json:"url"`
+	Fields    map[string]string `json:"fields"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// S3Presigner interface for generating presigned URLs
+type S3Presigner interface {
+	PresignGetObject(ctx context.Context, input PresignGetObjectInput) (*PresignedURL, error)
+	PresignPutObject(ctx context.Context, input PresignPutObjectInput) (*PresignedURL, error)
+	PresignPostObject(ctx context.Context, input PresignPostObjectInput) (*PresignedPost, error)
+}
+
+// PresignGetObject creates a URL that downloads an object without credentials.
+// The presign client shares the service client options, so custom endpoints and
+// path-style addressing produce URLs that point at the same server.
+func (s *AWSS3Service) PresignGetObject(ctx context.Context, input PresignGetObjectInput) (*PresignedURL, error) {
+	expires := input.Expires
+	if expires == 0 {
+		expires = DefaultPresignExpiry
+	}
+
+	s.logger.Debug("Presigning S3 object download",
+		"bucket", input.Bucket,
+		"key", input.Key,
+		"versionId", input.VersionID,
+		"expires", expires,
+	)
+
+	s3Input := &s3.GetObjectInput{
+		Bucket: aws.String(input.Bucket),
+		Key:    aws.String(input.Key),
+	}
+	if input.VersionID != "" {
+		s3Input.VersionId = aws.String(input.VersionID)
+	}
+	if input.ContentDisposition != "" {
+		s3Input.ResponseContentDisposition = aws.String(input.ContentDisposition)
+	}
+
+	// Signing time is taken before the request so ExpiresAt never overstates the lifetime
+	signedAt := time.Now()
+	presigned, err := s3.NewPresignClient(s.client).PresignGetObject(ctx, s3Input, s3.WithPresignExpires(expires))
+	if err != nil {
+		s.logger.Error("Failed to presign S3 object download", "error", err, "bucket", input.Bucket, "key", input.Key)
+		return nil, convertS3Error("presign get object", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket": input.Bucket,
+				"key":    input.Key,
+			})
+	}
+
+	return &PresignedURL{
+		URL:          presigned.URL,
+		Method:       presigned.Method,
+		ExpiresAt:    signedAt.Add(expires).UTC(),
+		SignedHeader: requiredHeaders(presigned.SignedHeader),
+	}, nil
+}
+
+// PresignPutObject creates a URL that uploads one object with an HTTP PUT
+func (s *AWSS3Service) PresignPutObject(ctx context.Context, input PresignPutObjectInput) (*PresignedURL, error) {
+	expires := input.Expires
+	if expires == 0 {
+		expires = DefaultPresignExpiry
+	}
+
+	s.logger.Debug("Presigning S3 object upload", "bucket", input.Bucket, "key", input.Key, "expires", expires)
+
+	s3Input := &s3.PutObjectInput{
+		Bucket: aws.String(input.Bucket),
+		Key:    aws.String(input.Key),
+	}
+	if input.ContentType != "" {
+		s3Input.ContentType = aws.String(input.ContentType)
+	}
+
+	signedAt := time.Now()
+	presigned, err := s3.NewPresignClient(s.client).PresignPutObject(ctx, s3Input, s3.WithPresignExpires(expires))
+	if err != nil {
+		s.logger.Error("Failed to presign S3 object upload", "error", err, "bucket", input.Bucket, "key", input.Key)
+		return nil, convertS3Error("presign put object", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket": input.Bucket,
+				"key":    input.Key,
+			})
+	}
+
+	return &PresignedURL{
+		URL:          presigned.URL,
+		Method:       presigned.Method,
+		ExpiresAt:    signedAt.Add(expires).UTC(),
+		SignedHeader: requiredHeaders(presigned.SignedHeader),
+	}, nil
+}
+
+// PresignPostObject creates a POST policy that lets a browser form upload any
+// number of files under a key prefix, within the given size and type limits.
+// The key field uses S3's ${filename} placeholder, so each file keeps its name.
+func (s *AWSS3Service) PresignPostObject(ctx context.Context, input PresignPostObjectInput) (*PresignedPost, error) {
+	expires := input.Expires
+	if expires == 0 {
+		expires = DefaultPresignExpiry
+	}
+
+	s.logger.Debug("Presigning S3 POST policy",
+		"bucket", input.Bucket,
+		"keyPrefix", input.KeyPrefix,
+		"expires", expires,
+	)
+
+	conditions := []any{
+		[]any{"starts-with", "$key", input.KeyPrefix},
+	}
+	if input.MaxContentLength > 0 {
+		conditions = append(conditions, []any{"content-length-range", input.MinContentLength, input.MaxContentLength})
+	}
+	if input.ContentType != "" {
+		conditions = append(conditions, map[string]string{"Content-Type": input.ContentType})
+	}
+
+	signedAt := time.Now()
+	presigned, err := s3.NewPresignClient(s.client).PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(input.Bucket),
+		Key:    aws.String(input.KeyPrefix + "${filename}"),
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = expires
+		o.Conditions = conditions
+	})
+	if err != nil {
+		s.logger.Error("Failed to presign S3 POST policy", "error", err, "bucket", input.Bucket, "keyPrefix", input.KeyPrefix)
+		return nil, convertS3Error("presign post object", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket":    input.Bucket,
+				"keyPrefix": input.KeyPrefix,
+			})
+	}
+
+	fields := presigned.Values
+	if input.ContentType != "" {
+		fields["Content-Type"] = input.ContentType
+	}
+
+	return &PresignedPost{
+		URL:       presigned.URL,
+		Fields:    fields,
+		ExpiresAt: signedAt.Add(expires).UTC(),
+	}, nil
+}
+
+// requiredHeaders drops the Host header, which every HTTP client sends on its own
+func requiredHeaders(signed http.Header) map[string][]string {
+	headers := make(map[string][]string)
+	for name, values := range signed {
+		if !strings.EqualFold(name, "Host") {
+			headers[name] = values
+		}
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
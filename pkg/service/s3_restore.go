@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// Retrieval tiers for restoring archived objects, fastest and most expensive first
+const (
+	RestoreTierExpedited = string(types.TierExpedited) // minutes, GLACIER only
+	RestoreTierStandard  = string(types.TierStandard)  // hours
+	RestoreTierBulk      = string(types.TierBulk)      // up to two days
+)
+
+// Restore states reported for archived objects
+const (
+	RestoreStateRestoring = "restoring" // the restore request is still running
+	RestoreStateRestored  = "restored"  // a temporary copy is readable until ExpiryDate
+)
+
+// RestoreStatus describes the temporary copy of an archived object
+type RestoreStatus struct {
+	State      string `json:"state"`                // restoring or restored
+	ExpiryDate string `json:"expiryDate,omitempty"` // RFC3339, restored copies only
+}
+
+// RestoreObjectInput represents input for restoring archived objects
+type RestoreObjectInput struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key,omitempty"`       // single object
+	VersionID string `json:"versionId,omitempty"` // single object only
+	Prefix    string `json:"prefix,omitempty"`    // every object under the prefix, when Key is empty
+	Days      int32  `json:"days"`                // how long the restored copy stays readable
+	Tier      string `json:"tier,omitempty"`      // Standard when empty
+}
+
+// RestoreObjectOutput summarises restore requests key by key
+type RestoreObjectOutput struct {
+	Requested  []string      `json:"requested"`            // restore started, or expiry extended
+	InProgress []string      `json:"inProgress,omitempty"` // a restore was already running
+	Skipped    []string      `json:"skipped,omitempty"`    // not archived, readable as is
+	Failed     []ObjectError `json:"failed,omitempty"`
+}
+
+// S3ObjectRestorer interface for restoring GLACIER and DEEP_ARCHIVE objects
+type S3ObjectRestorer interface {
+	RestoreObject(ctx context.Context, input RestoreObjectInput) (*RestoreObjectOutput, error)
+}
+
+// restoreOutcome is the result of one restore request that did not fail
+type restoreOutcome int
+
+const (
+	restoreRequested restoreOutcome = iota
+	restoreInProgress
+	restoreSkipped
+)
+
+// restoreHeaderField matches the key="value" pairs of an x-amz-restore header
+var restoreHeaderField = regexp.MustCompile(`([a-z-]+)="([^"]*)"`)
+
+// parseRestoreHeader converts an x-amz-restore header, e.g.
+// ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT",
+// into a RestoreStatus. It returns nil when no restore was ever requested.
+func parseRestoreHeader(header string) *RestoreStatus {
+	if header == "" {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	for _, match := range restoreHeaderField.FindAllStringSubmatch(header, -1) {
+		fields[match[1]] = match[2]
+	}
+
+	if fields["ongoing-request"] == "true" {
+		return &RestoreStatus{State: RestoreStateRestoring}
+	}
+	status := &RestoreStatus{State: RestoreStateRestored}
+	if expiry, err := http.ParseTime(fields["expiry-date"]); err == nil {
+		status.ExpiryDate = expiry.UTC().Format(time.RFC3339)
+	}
+	return status
+}
+
+// listedRestoreStatus converts the restore status returned by listings
+func listedRestoreStatus(status *types.RestoreStatus) *RestoreStatus {
+	if status == nil {
+		return nil
+	}
+	if aws.ToBool(status.IsRestoreInProgress) {
+		return &RestoreStatus{State: RestoreStateRestoring}
+	}
+	if status.RestoreExpiryDate == nil {
+		return nil
+	}
+	return &RestoreStatus{State: RestoreStateRestored, ExpiryDate: status.RestoreExpiryDate.UTC().Format(time.RFC3339)}
+}
+
+// RestoreObject asks S3 for temporary readable copies of archived objects. A single
+// key fails with an error, while results under a prefix are reported per key.
+func (s *AWSS3Service) RestoreObject(ctx context.Context, input RestoreObjectInput) (*RestoreObjectOutput, error) {
+	if input.Days < 1 {
+		return nil, s3cerrors.NewInvalidInputError("days", input.Days).
+			WithSuggestion("Keep the restored copy for at least one day")
+	}
+	if input.Tier == "" {
+		input.Tier = RestoreTierStandard
+	}
+
+	output := &RestoreObjectOutput{Requested: []string{}}
+	record := func(key string, outcome restoreOutcome) {
+		switch outcome {
+		case restoreInProgress:
+			output.InProgress = append(output.InProgress, key)
+		case restoreSkipped:
+			output.Skipped = append(output.Skipped, key)
+		default:
+			output.Requested = append(output.Requested, key)
+		}
+	}
+
+	if input.Key != "" {
+		outcome, err := s.restoreObject(ctx, input, input.Key, input.VersionID)
+		if err != nil {
+			return nil, err
+		}
+		record(input.Key, outcome)
+		return output, nil
+	}
+
+	prefix := normalizePrefix(input.Prefix)
+	if prefix == "" {
+		return nil, s3cerrors.NewValidationError(s3cerrors.CodeMissingField, "Either key or prefix is required")
+	}
+
+	err := s.walkPrefix(ctx, input.Bucket, prefix, func(keys []string) error {
+		// Folder markers are never archived
+		keys = slices.DeleteFunc(keys, func(key string) bool { return strings.HasSuffix(key, "/") })
+
+		outcomes := make([]restoreOutcome, len(keys))
+		errs := forEachKey(ctx, keys, func(i int, key string) error {
+			var err error
+			outcomes[i], err = s.restoreObject(ctx, input, key, "")
+			return err
+		})
+
+		for i, key := range keys {
+			if errs[i] != nil {
+				output.Failed = append(output.Failed, newObjectError(key, errs[i]))
+				continue
+			}
+			record(key, outcomes[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Finished restore requests",
+		"bucket", input.Bucket,
+		"prefix", prefix,
+		"tier", input.Tier,
+		"days", input.Days,
+		"requested", len(output.Requested),
+		"inProgress", len(output.InProgress),
+		"skipped", len(output.Skipped),
+		"failed", len(output.Failed),
+	)
+	return output, nil
+}
+
+// restoreObject sends one restore request. Objects that are already being restored or
+// that are not archived at all are reported as outcomes rather than errors.
+func (s *AWSS3Service) restoreObject(ctx context.Context, input RestoreObjectInput, key, versionID string) (restoreOutcome, error) {
+	s3Input := &s3.RestoreObjectInput{
+		Bucket: aws.String(input.Bucket),
+		Key:    aws.String(key),
+		RestoreRequest: &types.RestoreRequest{
+			Days:                 aws.Int32(input.Days),
+			GlacierJobParameters: &types.GlacierJobParameters{Tier: types.Tier(input.Tier)},
+		},
+	}
+	if versionID != "" {
+		s3Input.VersionId = aws.String(versionID)
+	}
+
+	_, err := s.client.RestoreObject(ctx, s3Input)
+	switch {
+	case err == nil:
+		s.logger.Debug("Requested object restore", "bucket", input.Bucket, "key", key, "tier", input.Tier, "days", input.Days)
+		return restoreRequested, nil
+	case strings.Contains(err.Error(), "RestoreAlreadyInProgress"):
+		return restoreInProgress, nil
+	case strings.Contains(err.Error(), "InvalidObjectState"):
+		// S3 refuses restores of objects outside the archive storage classes
+		return restoreSkipped, nil
+	default:
+		return 0, convertS3Error("restore object", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket":    input.Bucket,
+				"key":       key,
+				"versionId": versionID,
+				"tier":      input.Tier,
+			})
+	}
+}
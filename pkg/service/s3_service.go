@@ -22,13 +22,22 @@ type S3Config struct {
 	Profile     string `json:"profile"`
 	EndpointURL string `json:"endpointUrl"`
 	Region      string `json:"region"`
+
+	// Multipart upload tuning, zero values fall back to the package defaults
+	MultipartThreshold   int64 `json:"multipartThreshold,omitempty"`
+	MultipartPartSize    int64 `json:"multipartPartSize,omitempty"`
+	MultipartConcurrency int   `json:"multipartConcurrency,omitempty"`
+
+	// ClientKeyFile is the path of the 256-bit key used by key-file client-side encryption
+	ClientKeyFile string `json:"clientKeyFile,omitempty"`
 }
 
 // AWSS3Service implements S3Operations using AWS SDK
 type AWSS3Service struct {
-	client *s3.Client
-	config S3Config
-	logger *slog.Logger
+	client    *s3.Client
+	config    S3Config
+	logger    *slog.Logger
+	clientKey []byte // loaded from config.ClientKeyFile, nil when unset
 }
 
 // S3Object represents an S3 object with metadata
@@ -37,6 +46,10 @@ type S3Object struct {
 	Size         int64  `json:"size"`
 	LastModified string `json:"lastModified"`
 	IsFolder     bool   `json:"isFolder"`
+	StorageClass string `json:"storageClass,omitempty"` // empty for folders
+
+	// Restore is only reported when the listing asked for RestoreStatus
+	Restore *RestoreStatus `json:"restore,omitempty"`
 }
 
 // ListObjectsInput represents input for listing objects
@@ -46,6 +59,11 @@ type ListObjectsInput struct {
 	Delimiter         string `json:"delimiter,omitempty"`
 	MaxKeys           int32  `json:"maxKeys,omitempty"`
 	ContinuationToken string `json:"continuationToken,omitempty"`
+	Recursive         bool   `json:"recursive,omitempty"` // List every nested key without a delimiter
+
+	// RestoreStatus asks for the restore state of archived objects, which AWS
+	// only returns on request and some S3 compatible services not at all
+	RestoreStatus bool `json:"restoreStatus,omitempty"`
 }
 
 // ListObjectsOutput represents output from listing objects
@@ -71,6 +89,17 @@ type S3BucketCreator interface {
 	CreateBucket(ctx context.Context, bucketName string) error
 }
 
+// S3BucketDeleter interface for bucket deletion operations
+type S3BucketDeleter interface {
+	DeleteBucket(ctx context.Context, input DeleteBucketInput) (*DeleteBucketOutput, error)
+}
+
+// S3BucketVersioning interface for bucket versioning configuration
+type S3BucketVersioning interface {
+	GetBucketVersioning(ctx context.Context, bucket string) (*BucketVersioning, error)
+	PutBucketVersioning(ctx context.Context, input PutBucketVersioningInput) error
+}
+
 // S3ObjectReader interface for read-only object operations
 type S3ObjectReader interface {
 	ListObjects(ctx context.Context, input ListObjectsInput) (*ListObjectsOutput, error)
@@ -79,35 +108,73 @@ type S3ObjectReader interface {
 // S3ObjectDeleter interface for object deletion operations
 type S3ObjectDeleter interface {
 	DeleteObject(ctx context.Context, bucket, key string) error
-	DeleteObjects(ctx context.Context, bucket string, keys []string) error
+	DeleteObjects(ctx context.Context, bucket string, keys []string) (*DeleteObjectsOutput, error)
+}
+
+// ObjectError describes why an operation failed for a single key
+type ObjectError struct {
+	Key     string `json:"key"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// DeleteObjectsOutput reports which keys a batch delete removed and which S3 refused
+type DeleteObjectsOutput struct {
+	Deleted []string      `json:"deleted"`
+	Failed  []ObjectError `json:"failed,omitempty"`
 }
 
 // UploadObjectInput represents input for uploading objects
 type UploadObjectInput struct {
 	Bucket      string            `json:"bucket"`
 	Key         string            `json:"key"`
-	Body        []byte            `json:"-"` // Don't serialize body in JSON
+	Body        io.Reader         `json:"-"` // Don't serialize body in JSON
+	Size        int64             `json:"-"` // Body length in bytes, negative when unknown
 	ContentType string            `json:"contentType,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+
+	// StorageClass overrides the bucket default (STANDARD) when set
+	StorageClass string `json:"storageClass,omitempty"`
+	// Encryption overrides the bucket default encryption when set
+	Encryption *ServerSideEncryption `json:"encryption,omitempty"`
+	// ClientEncryption encrypts the body before it is sent, independently of Encryption
+	ClientEncryption *ClientEncryption `json:"clientEncryption,omitempty"`
 }
 
 // UploadObjectOutput represents output from uploading objects
 type UploadObjectOutput struct {
-	Key  string `json:"key"`
-	ETag string `json:"etag"`
+	Key       string `json:"key"`
+	ETag      string `json:"etag"`
+	Size      int64  `json:"size"`
+	Multipart bool   `json:"multipart,omitempty"`
 }
 
 // DownloadObjectInput represents input for downloading objects
 type DownloadObjectInput struct {
-	Bucket string `json:"bucket"`
-	Key    string `json:"key"`
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"` // a specific version, latest when empty
+	Range     string `json:"range,omitempty"`     // HTTP Range header value, e.g. "bytes=0-1023"
+
+	// Conditional request fields, S3 answers PreconditionFailed when they do not hold
+	IfMatch           string    `json:"ifMatch,omitempty"`
+	IfUnmodifiedSince time.Time `json:"ifUnmodifiedSince,omitzero"`
+
+	// SSECustomerKey is the base64 key an SSE-C object was written with
+	SSECustomerKey string `json:"-"`
+	// ClientPassphrase opens objects client-side encrypted with a passphrase
+	ClientPassphrase string `json:"-"`
 }
 
-// DownloadObjectOutput represents output from downloading objects
+// DownloadObjectOutput represents output from downloading objects.
+// The caller must close Body once it has been consumed.
 type DownloadObjectOutput struct {
-	Body          []byte            `json:"-"` // Don't serialize body in JSON
+	Body          io.ReadCloser     `json:"-"` // Don't serialize body in JSON
 	ContentType   string            `json:"contentType"`
+	ETag          string            `json:"etag,omitempty"`
 	ContentLength int64             `json:"contentLength"`
+	ContentRange  string            `json:"contentRange,omitempty"` // Set when a range was served
 	LastModified  string            `json:"lastModified"`
 	Metadata      map[string]string `json:"metadata,omitempty"`
 }
@@ -132,11 +199,28 @@ type S3Operations interface {
 	S3ConnectionTester
 	S3BucketLister
 	S3BucketCreator
+	S3BucketDeleter
+	S3BucketVersioning
+	S3BucketInspector
+	S3BucketPolicyManager
+	S3BucketCorsManager
+	S3BucketLifecycleManager
 	S3ObjectReader
 	S3ObjectDeleter
 	S3ObjectUploader
 	S3ObjectDownloader
 	S3FolderCreator
+	S3ObjectCopier
+	S3PrefixCopier
+	S3PrefixDeleter
+	S3ObjectInspector
+	S3MetadataEditor
+	S3VersionManager
+	S3Presigner
+	S3ObjectTagger
+	S3StorageClassChanger
+	S3ObjectRestorer
+	S3ObjectLockManager
 }
 
 // NewS3Service creates a new S3Service with the given configuration
@@ -197,15 +281,26 @@ func NewS3ServiceWithLogger(ctx context.Context, cfg S3Config, logger *slog.Logg
 	// Create S3 client
 	client := s3.NewFromConfig(awsConfig, s3Options...)
 
+	// Fail at configuration time rather than on the first encrypted upload
+	var clientKey []byte
+	if cfg.ClientKeyFile != "" {
+		clientKey, err = loadClientKeyFile(cfg.ClientKeyFile)
+		if err != nil {
+			serviceLogger.Error("Failed to load client encryption key", "error", err)
+			return nil, err
+		}
+	}
+
 	serviceLogger.Info("S3 service created successfully",
 		"profile", cfg.Profile,
 		"region", cfg.Region,
 	)
 
 	return &AWSS3Service{
-		client: client,
-		config: cfg,
-		logger: serviceLogger,
+		client:    client,
+		config:    cfg,
+		logger:    serviceLogger,
+		clientKey: clientKey,
 	}, nil
 }
 
@@ -289,6 +384,7 @@ func (s *AWSS3Service) ListObjects(ctx context.Context, input ListObjectsInput)
 		"prefix", input.Prefix,
 		"delimiter", input.Delimiter,
 		"maxKeys", input.MaxKeys,
+		"recursive", input.Recursive,
 		"hasContinuationToken", input.ContinuationToken != "",
 	)
 
@@ -299,15 +395,20 @@ func (s *AWSS3Service) ListObjects(ctx context.Context, input ListObjectsInput)
 	}
 
 	delimiter := input.Delimiter
-	if delimiter == "" && input.Prefix != "" {
+	if input.Recursive {
+		delimiter = "" // Flat listing of every key under the prefix
+	} else if delimiter == "" && input.Prefix != "" {
 		delimiter = "/" // Default delimiter for folder-like browsing
 	}
 
 	// Prepare S3 input
 	s3Input := &s3.ListObjectsV2Input{
-		Bucket:    aws.String(input.Bucket),
-		MaxKeys:   aws.Int32(maxKeys),
-		Delimiter: aws.String(delimiter),
+		Bucket:  aws.String(input.Bucket),
+		MaxKeys: aws.Int32(maxKeys),
+	}
+
+	if delimiter != "" {
+		s3Input.Delimiter = aws.String(delimiter)
 	}
 
 	if input.Prefix != "" {
@@ -318,6 +419,10 @@ func (s *AWSS3Service) ListObjects(ctx context.Context, input ListObjectsInput)
 		s3Input.ContinuationToken = aws.String(input.ContinuationToken)
 	}
 
+	if input.RestoreStatus {
+		s3Input.OptionalObjectAttributes = []types.OptionalObjectAttributes{types.OptionalObjectAttributesRestoreStatus}
+	}
+
 	// Call S3
 	result, err := s.client.ListObjectsV2(ctx, s3Input)
 	if err != nil {
@@ -399,6 +504,10 @@ func (s *AWSS3Service) ListObjects(ctx context.Context, input ListObjectsInput)
 			Size:     size,
 			IsFolder: isFolder,
 		}
+		if !isFolder {
+			s3Obj.StorageClass = listedStorageClass(string(obj.StorageClass))
+			s3Obj.Restore = listedRestoreStatus(obj.RestoreStatus)
+		}
 
 		if obj.LastModified != nil {
 			s3Obj.LastModified = obj.LastModified.Format(time.RFC3339)
@@ -433,48 +542,96 @@ func (s *AWSS3Service) DeleteObject(ctx context.Context, bucket, key string) err
 	return nil
 }
 
-// DeleteObjects deletes multiple objects from S3 in a batch operation
-func (s *AWSS3Service) DeleteObjects(ctx context.Context, bucket string, keys []string) error {
+// DeleteObjects deletes multiple objects from S3 in batches of up to 1000 keys.
+// Keys S3 refuses to delete (for example AccessDenied) are reported in the output
+// rather than as an error; an error means a whole batch request failed.
+func (s *AWSS3Service) DeleteObjects(ctx context.Context, bucket string, keys []string) (*DeleteObjectsOutput, error) {
+	output := &DeleteObjectsOutput{Deleted: []string{}}
 	if len(keys) == 0 {
-		return nil
+		return output, nil
 	}
 
-	// Use Go 1.24 slices.Collect to convert keys to ObjectIdentifier slice
-	objects := slices.Collect(func(yield func(types.ObjectIdentifier) bool) {
-		for _, key := range keys {
-			if !yield(types.ObjectIdentifier{Key: aws.String(key)}) {
-				return
-			}
+	deleted, failed, err := s.deleteKeys(ctx, bucket, keys)
+	output.Deleted = append(output.Deleted, deleted...)
+	output.Failed = failed
+	if err != nil {
+		return nil, err.(*s3cerrors.S3CError).WithDetails(map[string]any{
+			"bucket":  bucket,
+			"count":   len(keys),
+			"deleted": len(output.Deleted),
+		})
+	}
+
+	if len(failed) > 0 {
+		s.logger.Warn("Some objects could not be deleted",
+			"bucket", bucket,
+			"deleted", len(output.Deleted),
+			"failed", len(failed),
+		)
+	}
+
+	return output, nil
+}
+
+// UploadObject uploads an object to S3, switching to a multipart upload
+// when the body reaches the configured multipart threshold
+func (s *AWSS3Service) UploadObject(ctx context.Context, input UploadObjectInput) (*UploadObjectOutput, error) {
+	opts := s.multipartOptions()
+
+	// Reject a bad encryption option before any of the body is read
+	sse, err := input.Encryption.fields()
+	if err != nil {
+		return nil, err.(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket": input.Bucket,
+				"key":    input.Key,
+			})
+	}
+
+	if input.ClientEncryption != nil {
+		if input, err = s.encryptUploadBody(input); err != nil {
+			return nil, err
 		}
-	})
+	}
 
-	// Perform batch delete
-	_, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
-		Bucket: aws.String(bucket),
-		Delete: &types.Delete{
-			Objects: objects,
-			Quiet:   aws.Bool(false), // Return results for error handling
-		},
-	})
+	// Bodies known to be large go straight to multipart without buffering
+	if input.Size >= opts.threshold {
+		return s.uploadMultipart(ctx, input, input.Body, opts, sse)
+	}
+
+	// Read ahead up to the threshold so that small or unknown-length bodies
+	// can be sent with a single PutObject using a seekable body
+	head, err := readHead(input.Body, opts.threshold, input.Size)
 	if err != nil {
-		return convertS3Error("delete objects", err).(*s3cerrors.S3CError).
+		return nil, s3cerrors.NewFileOperationError("read", "upload body", err).
 			WithDetails(map[string]any{
-				"bucket": bucket,
-				"keys":   keys,
-				"count":  len(keys),
+				"bucket": input.Bucket,
+				"key":    input.Key,
 			})
 	}
 
-	return nil
+	if int64(len(head)) < opts.threshold {
+		return s.putObject(ctx, input, head, sse)
+	}
+
+	return s.uploadMultipart(ctx, input, io.MultiReader(bytes.NewReader(head), input.Body), opts, sse)
 }
 
-// UploadObject uploads an object to S3
-func (s *AWSS3Service) UploadObject(ctx context.Context, input UploadObjectInput) (*UploadObjectOutput, error) {
+// putObject uploads a fully buffered body with a single PutObject request
+func (s *AWSS3Service) putObject(ctx context.Context, input UploadObjectInput, body []byte, sse sseFields) (*UploadObjectOutput, error) {
 	// Prepare S3 input
 	s3Input := &s3.PutObjectInput{
-		Bucket: aws.String(input.Bucket),
-		Key:    aws.String(input.Key),
-		Body:   bytes.NewReader(input.Body),
+		Bucket:                  aws.String(input.Bucket),
+		Key:                     aws.String(input.Key),
+		Body:                    bytes.NewReader(body),
+		ContentLength:           aws.Int64(int64(len(body))),
+		ServerSideEncryption:    sse.mode,
+		SSEKMSKeyId:             sse.kmsKeyID,
+		SSEKMSEncryptionContext: sse.kmsContext,
+		BucketKeyEnabled:        sse.bucketKeyEnabled,
+		SSECustomerAlgorithm:    sse.customer.algorithm,
+		SSECustomerKey:          sse.customer.key,
+		SSECustomerKeyMD5:       sse.customer.keyMD5,
 	}
 
 	if input.ContentType != "" {
@@ -485,6 +642,14 @@ func (s *AWSS3Service) UploadObject(ctx context.Context, input UploadObjectInput
 		s3Input.Metadata = input.Metadata
 	}
 
+	if len(input.Tags) > 0 {
+		s3Input.Tagging = aws.String(encodeTags(input.Tags))
+	}
+
+	if input.StorageClass != "" {
+		s3Input.StorageClass = types.StorageClass(input.StorageClass)
+	}
+
 	// Upload to S3
 	result, err := s.client.PutObject(ctx, s3Input)
 	if err != nil {
@@ -492,12 +657,13 @@ func (s *AWSS3Service) UploadObject(ctx context.Context, input UploadObjectInput
 			WithDetails(map[string]any{
 				"bucket": input.Bucket,
 				"key":    input.Key,
-				"size":   len(input.Body),
+				"size":   len(body),
 			})
 	}
 
 	output := &UploadObjectOutput{
-		Key: input.Key,
+		Key:  input.Key,
+		Size: int64(len(body)),
 	}
 	if result.ETag != nil {
 		output.ETag = *result.ETag
@@ -506,35 +672,66 @@ func (s *AWSS3Service) UploadObject(ctx context.Context, input UploadObjectInput
 	return output, nil
 }
 
-// DownloadObject downloads an object from S3
+// DownloadObject opens an object from S3 for streaming.
+// The returned body streams directly from S3 and must be closed by the caller.
 func (s *AWSS3Service) DownloadObject(ctx context.Context, input DownloadObjectInput) (*DownloadObjectOutput, error) {
-	// Get object from S3
-	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+	s3Input := &s3.GetObjectInput{
 		Bucket: aws.String(input.Bucket),
 		Key:    aws.String(input.Key),
-	})
+	}
+	if input.VersionID != "" {
+		s3Input.VersionId = aws.String(input.VersionID)
+	}
+	if input.Range != "" {
+		s3Input.Range = aws.String(input.Range)
+	}
+	if input.IfMatch != "" {
+		s3Input.IfMatch = aws.String(input.IfMatch)
+	}
+	if !input.IfUnmodifiedSince.IsZero() {
+		s3Input.IfUnmodifiedSince = aws.Time(input.IfUnmodifiedSince)
+	}
+	customer, err := customerKeyFields(input.SSECustomerKey)
 	if err != nil {
-		return nil, convertS3Error("download object", err).(*s3cerrors.S3CError).
+		return nil, err.(*s3cerrors.S3CError).
 			WithDetails(map[string]any{
 				"bucket": input.Bucket,
 				"key":    input.Key,
 			})
 	}
-	defer result.Body.Close()
+	s3Input.SSECustomerAlgorithm = customer.algorithm
+	s3Input.SSECustomerKey = customer.key
+	s3Input.SSECustomerKeyMD5 = customer.keyMD5
 
-	// Read the body
-	body, err := io.ReadAll(result.Body)
+	// Get object from S3
+	result, err := s.client.GetObject(ctx, s3Input)
 	if err != nil {
-		return nil, s3cerrors.NewFileOperationError("read", "S3 object body", err).
+		return nil, convertS3Error("download object", err).(*s3cerrors.S3CError).
 			WithDetails(map[string]any{
-				"bucket": input.Bucket,
-				"key":    input.Key,
+				"bucket":    input.Bucket,
+				"key":       input.Key,
+				"versionId": input.VersionID,
+				"range":     input.Range,
 			})
 	}
 
+	if isClientEncrypted(result.Metadata) && input.Range != "" {
+		// Ranges of the stored ciphertext do not map onto plaintext bytes, so the
+		// whole object is read instead, pinned to the version that was just found
+		result.Body.Close()
+		input.Range = ""
+		input.IfMatch = aws.ToString(result.ETag)
+		if result.VersionId != nil {
+			input.VersionID = *result.VersionId
+		}
+		return s.DownloadObject(ctx, input)
+	}
+
 	output := &DownloadObjectOutput{
-		Body:          body,
+		Body:          result.Body,
 		ContentLength: aws.ToInt64(result.ContentLength),
+		ContentRange:  aws.ToString(result.ContentRange),
+		ETag:          aws.ToString(result.ETag),
 		Metadata:      result.Metadata,
 	}
 
@@ -546,6 +743,19 @@ func (s *AWSS3Service) DownloadObject(ctx context.Context, input DownloadObjectI
 		output.LastModified = result.LastModified.Format(time.RFC3339)
 	}
 
+	if isClientEncrypted(result.Metadata) {
+		if err := s.decryptDownloadBody(input, output); err != nil {
+			result.Body.Close()
+			return nil, err.(*s3cerrors.S3CError).
+				WithDetails(map[string]any{
+					"bucket":    input.Bucket,
+					"key":       input.Key,
+					"versionId": input.VersionID,
+					"keySource": result.Metadata[metaClientKeySource],
+				})
+		}
+	}
+
 	return output, nil
 }
 
@@ -566,7 +776,8 @@ func (s *AWSS3Service) CreateFolder(ctx context.Context, bucket, prefix string)
 	uploadInput := UploadObjectInput{
 		Bucket:      bucket,
 		Key:         folderKey,
-		Body:        []byte{}, // Empty content for folder marker
+		Body:        strings.NewReader(""), // Empty content for folder marker
+		Size:        0,
 		ContentType: "application/x-directory",
 		Metadata: map[string]string{
 			"folder-marker": "true",
@@ -607,10 +818,40 @@ func convertS3Error(operation string, err error) error {
 		// Extract bucket name from error message if possible
 		return s3cerrors.NewS3BucketNotFoundError("").WithWrapped(err)
 
+	case strings.Contains(errMsg, "BucketNotEmpty"):
+		return s3cerrors.NewS3BucketNotEmptyError("").WithWrapped(err)
+
 	case strings.Contains(errMsg, "NoSuchKey"):
 		// Extract key name from error message if possible
 		return s3cerrors.NewS3ObjectNotFoundError("", "").WithWrapped(err)
 
+	case strings.Contains(errMsg, "InvalidRange"):
+		return s3cerrors.NewS3InvalidRangeError("").WithWrapped(err)
+
+	case strings.Contains(errMsg, "PreconditionFailed"):
+		return s3cerrors.NewS3PreconditionFailedError(operation).WithWrapped(err)
+
+	case strings.Contains(errMsg, "InvalidObjectState"):
+		// GLACIER and DEEP_ARCHIVE objects cannot be read or copied until restored
+		return s3cerrors.NewS3ObjectArchivedError("", "").WithWrapped(err)
+
+	case strings.Contains(errMsg, "MalformedPolicy"):
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidFormat, "S3 rejected the policy document").
+			WithWrapped(err).
+			WithSuggestion("Check the policy against the IAM policy grammar")
+
+	case strings.Contains(errMsg, "Server Side Encryption"):
+		// S3 refuses to read SSE-C objects without the key they were written with
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Object is encrypted with a customer-provided key").
+			WithWrapped(err).
+			WithSuggestion("Provide the SSE-C key the object was uploaded with")
+
+	case strings.Contains(errMsg, "KMS."):
+		// Checked before NotFound, which KMS.NotFoundException would otherwise match
+		return s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "KMS rejected the encryption key").
+			WithWrapped(err).
+			WithSuggestion("Check the KMS key ID and that your credentials are allowed to use it")
+
 	case strings.Contains(errMsg, "NotFound"):
 		return s3cerrors.NewS3Error(s3cerrors.CodeS3ObjectNotFound, "Resource not found").WithWrapped(err)
 
@@ -621,6 +862,10 @@ func convertS3Error(operation string, err error) error {
 		return s3cerrors.NewCredentialsInvalidError(err).
 			WithSuggestion("Check your AWS secret access key")
 
+	case isObjectLockDenial(errMsg):
+		// S3 reports locked objects as AccessDenied, so this is checked first
+		return s3cerrors.NewS3ObjectLockedError("", "").WithWrapped(err)
+
 	case strings.Contains(errMsg, "AccessDenied") || strings.Contains(errMsg, "Forbidden"):
 		return s3cerrors.NewS3AccessDeniedError(operation, "S3 resource").WithWrapped(err)
 
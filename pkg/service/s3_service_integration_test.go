@@ -3,13 +3,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime/multipart"
+	"net/http"
 	"os"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -17,6 +24,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/localstack"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
 )
 
 const (
@@ -61,6 +70,74 @@ func TestS3ServiceIntegration(t *testing.T) {
 	t.Run("CreateFolder", func(t *testing.T) {
 		testCreateFolder(t, ctx, s3Service, endpoint)
 	})
+
+	t.Run("MultipartUpload", func(t *testing.T) {
+		testMultipartUpload(t, ctx, s3Service)
+	})
+
+	t.Run("CopyAndMoveObjects", func(t *testing.T) {
+		testCopyAndMoveObjects(t, ctx, s3Service)
+	})
+
+	t.Run("MoveFolderPrefix", func(t *testing.T) {
+		testMoveFolderPrefix(t, ctx, s3Service)
+	})
+
+	t.Run("DeleteFolderPrefix", func(t *testing.T) {
+		testDeleteFolderPrefix(t, ctx, s3Service)
+	})
+
+	t.Run("DeleteBucket", func(t *testing.T) {
+		testDeleteBucket(t, ctx, s3Service)
+	})
+
+	t.Run("UpdateMetadata", func(t *testing.T) {
+		testUpdateMetadata(t, ctx, s3Service)
+	})
+
+	t.Run("ObjectVersions", func(t *testing.T) {
+		testObjectVersions(t, ctx, s3Service)
+	})
+
+	t.Run("PresignedRequests", func(t *testing.T) {
+		testPresignedRequests(t, ctx, s3Service)
+	})
+
+	t.Run("ObjectTagging", func(t *testing.T) {
+		testObjectTagging(t, ctx, s3Service)
+	})
+
+	t.Run("BucketPolicy", func(t *testing.T) {
+		testBucketPolicy(t, ctx, s3Service)
+	})
+
+	t.Run("BucketCors", func(t *testing.T) {
+		testBucketCors(t, ctx, s3Service)
+	})
+
+	t.Run("BucketLifecycle", func(t *testing.T) {
+		testBucketLifecycle(t, ctx, s3Service)
+	})
+
+	t.Run("ServerSideEncryption", func(t *testing.T) {
+		testServerSideEncryption(t, ctx, s3Service)
+	})
+
+	t.Run("ClientSideEncryption", func(t *testing.T) {
+		testClientSideEncryption(t, ctx, s3Service)
+	})
+
+	t.Run("StorageClass", func(t *testing.T) {
+		testStorageClass(t, ctx, s3Service)
+	})
+
+	t.Run("ArchiveRestore", func(t *testing.T) {
+		testArchiveRestore(t, ctx, s3Service)
+	})
+
+	t.Run("ObjectLock", func(t *testing.T) {
+		testObjectLock(t, ctx, s3Service, endpoint)
+	})
 }
 
 func startLocalStack(t *testing.T, ctx context.Context) (*localstack.LocalStackContainer, string) {
@@ -205,7 +282,8 @@ func testUploadFilesAndFolders(t *testing.T, ctx context.Context, s3Service S3Op
 			_, err := s3Service.UploadObject(ctx, UploadObjectInput{
 				Bucket:      testBucket,
 				Key:         tc.key,
-				Body:        []byte(tc.content),
+				Body:        strings.NewReader(tc.content),
+				Size:        int64(len(tc.content)),
 				ContentType: "text/plain",
 			})
 			if err != nil {
@@ -232,6 +310,7 @@ func testListObjectsWithFolders(t *testing.T, ctx context.Context, s3Service S3O
 		name      string
 		prefix    string
 		delimiter string
+		recursive bool
 		expected  map[string]bool // key -> isFolder
 	}{
 		{
@@ -277,6 +356,17 @@ func testListObjectsWithFolders(t *testing.T, ctx context.Context, s3Service S3O
 				"folder2/file5.txt":           false,
 			},
 		},
+		{
+			name:      "Recursive listing under a prefix",
+			prefix:    "folder1/",
+			recursive: true,
+			expected: map[string]bool{
+				"folder1/":                    true,
+				"folder1/file3.txt":           false,
+				"folder1/subfolder/":          true,
+				"folder1/subfolder/file4.txt": false,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -285,6 +375,7 @@ func testListObjectsWithFolders(t *testing.T, ctx context.Context, s3Service S3O
 				Bucket:    testBucket,
 				Prefix:    tt.prefix,
 				Delimiter: tt.delimiter,
+				Recursive: tt.recursive,
 				MaxKeys:   100,
 			})
 			if err != nil {
@@ -346,7 +437,8 @@ func testNestedFolderStructure(t *testing.T, ctx context.Context, s3Service S3Op
 			_, err := s3Service.UploadObject(ctx, UploadObjectInput{
 				Bucket:      testBucket,
 				Key:         item.key,
-				Body:        []byte(item.content),
+				Body:        strings.NewReader(item.content),
+				Size:        int64(len(item.content)),
 				ContentType: "text/plain",
 			})
 			if err != nil {
@@ -422,7 +514,8 @@ func testDeleteOperations(t *testing.T, ctx context.Context, s3Service S3Operati
 		_, err := s3Service.UploadObject(ctx, UploadObjectInput{
 			Bucket:      testBucket,
 			Key:         filename,
-			Body:        []byte("test content"),
+			Body:        strings.NewReader("test content"),
+			Size:        int64(len("test content")),
 			ContentType: "text/plain",
 		})
 		if err != nil {
@@ -437,9 +530,11 @@ func testDeleteOperations(t *testing.T, ctx context.Context, s3Service S3Operati
 	}
 
 	// Test batch delete
-	err = s3Service.DeleteObjects(ctx, testBucket, []string{"delete_me2.txt"})
+	deleted, err := s3Service.DeleteObjects(ctx, testBucket, []string{"delete_me2.txt"})
 	if err != nil {
 		t.Errorf("Failed to delete objects in batch: %v", err)
+	} else if len(deleted.Deleted) != 1 || len(deleted.Failed) != 0 {
+		t.Errorf("Expected one deleted key and no failures, got %v and %v", deleted.Deleted, deleted.Failed)
 	}
 
 	// Verify deletions
@@ -680,3 +775,909 @@ func testCreateFolder(t *testing.T, ctx context.Context, s3Service S3Operations,
 			folderMarker.Key, folderMarker.Size, folderMarker.IsFolder)
 	})
 }
+
+func testMultipartUpload(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	// Use the smallest allowed part size so the test stays fast
+	svc := s3Service.(*AWSS3Service)
+	multipartService := &AWSS3Service{
+		client: svc.client,
+		config: S3Config{
+			Region:               svc.config.Region,
+			EndpointURL:          svc.config.EndpointURL,
+			MultipartThreshold:   minMultipartPartSize,
+			MultipartPartSize:    minMultipartPartSize,
+			MultipartConcurrency: 2,
+		},
+		logger: svc.logger,
+	}
+
+	// 12 MiB of unknown length: two full parts plus a short final part
+	content := strings.Repeat("0123456789abcdef", (12<<20)/16)
+	output, err := multipartService.UploadObject(ctx, UploadObjectInput{
+		Bucket:      testBucket,
+		Key:         "multipart/large.bin",
+		Body:        io.MultiReader(strings.NewReader(content)), // hide Len/Seek
+		Size:        -1,
+		ContentType: "application/octet-stream",
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload multipart object: %v", err)
+	}
+	if !output.Multipart {
+		t.Error("Expected upload to use multipart")
+	}
+	if output.Size != int64(len(content)) {
+		t.Errorf("Expected uploaded size %d, got %d", len(content), output.Size)
+	}
+
+	result, err := s3Service.ListObjects(ctx, ListObjectsInput{
+		Bucket:  testBucket,
+		Prefix:  "multipart/large.bin",
+		MaxKeys: 10,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list multipart object: %v", err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Size != int64(len(content)) {
+		t.Errorf("Expected one object of size %d, got %v", len(content), result.Objects)
+	}
+
+	// Small uploads stay on a single PutObject
+	output, err = multipartService.UploadObject(ctx, UploadObjectInput{
+		Bucket: testBucket,
+		Key:    "multipart/small.txt",
+		Body:   strings.NewReader("small"),
+		Size:   -1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload small object: %v", err)
+	}
+	if output.Multipart {
+		t.Error("Expected small upload to avoid multipart")
+	}
+}
+
+func testCopyAndMoveObjects(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+		Bucket:      testBucket,
+		Key:         "copy/source file.txt",
+		Body:        strings.NewReader("copy me"),
+		Size:        7,
+		ContentType: "text/plain",
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload source object: %v", err)
+	}
+
+	copied, err := s3Service.CopyObject(ctx, CopyObjectInput{
+		SourceBucket:      testBucket,
+		SourceKey:         "copy/source file.txt",
+		DestinationBucket: testBucket,
+		DestinationKey:    "copy/copied.txt",
+	})
+	if err != nil {
+		t.Fatalf("Failed to copy object: %v", err)
+	}
+	if copied.Size != 7 {
+		t.Errorf("Expected copied size 7, got %d", copied.Size)
+	}
+
+	_, err = s3Service.MoveObject(ctx, CopyObjectInput{
+		SourceBucket:      testBucket,
+		SourceKey:         "copy/copied.txt",
+		DestinationBucket: testBucket,
+		DestinationKey:    "copy/renamed.txt",
+	})
+	if err != nil {
+		t.Fatalf("Failed to move object: %v", err)
+	}
+
+	result, err := s3Service.ListObjects(ctx, ListObjectsInput{
+		Bucket:    testBucket,
+		Prefix:    "copy/",
+		Recursive: true,
+		MaxKeys:   10,
+	})
+	if err != nil {
+		t.Fatalf("Failed to list copied objects: %v", err)
+	}
+	var keys []string
+	for _, obj := range result.Objects {
+		keys = append(keys, obj.Key)
+	}
+	expected := []string{"copy/renamed.txt", "copy/source file.txt"}
+	if strings.Join(keys, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected keys %v after copy and move, got %v", expected, keys)
+	}
+
+	// Moving an object onto itself must not delete it
+	_, err = s3Service.MoveObject(ctx, CopyObjectInput{
+		SourceBucket:      testBucket,
+		SourceKey:         "copy/renamed.txt",
+		DestinationBucket: testBucket,
+		DestinationKey:    "copy/renamed.txt",
+	})
+	if err == nil {
+		t.Error("Expected moving an object onto itself to fail")
+	}
+}
+
+func testMoveFolderPrefix(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	if err := s3Service.CreateFolder(ctx, testBucket, "reports/2024/"); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+	for _, key := range []string{"reports/2024/q1.csv", "reports/2024/q2/summary.csv"} {
+		_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+			Bucket: testBucket,
+			Key:    key,
+			Body:   strings.NewReader("data"),
+			Size:   4,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload %s: %v", key, err)
+		}
+	}
+
+	output, err := s3Service.MovePrefix(ctx, CopyPrefixInput{
+		SourceBucket:      testBucket,
+		SourcePrefix:      "reports/2024",
+		DestinationBucket: testBucket,
+		DestinationPrefix: "archive/2024",
+	})
+	if err != nil {
+		t.Fatalf("Failed to move folder: %v", err)
+	}
+	if len(output.Objects) != 3 || len(output.Failed) != 0 {
+		t.Errorf("Expected 3 moved objects and no failures, got %d moved and %v", len(output.Objects), output.Failed)
+	}
+
+	remaining, err := s3Service.ListObjects(ctx, ListObjectsInput{Bucket: testBucket, Prefix: "reports/2024/", Recursive: true})
+	if err != nil {
+		t.Fatalf("Failed to list source folder: %v", err)
+	}
+	if len(remaining.Objects) != 0 {
+		t.Errorf("Expected source folder to be empty, got %v", remaining.Objects)
+	}
+
+	archived, err := s3Service.ListObjects(ctx, ListObjectsInput{Bucket: testBucket, Prefix: "archive/2024/", Recursive: true})
+	if err != nil {
+		t.Fatalf("Failed to list destination folder: %v", err)
+	}
+	var keys []string
+	for _, obj := range archived.Objects {
+		keys = append(keys, obj.Key)
+	}
+	expected := []string{"archive/2024/", "archive/2024/q1.csv", "archive/2024/q2/summary.csv"}
+	if strings.Join(keys, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected keys %v, got %v", expected, keys)
+	}
+}
+
+func testDeleteFolderPrefix(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	for _, key := range []string{"trash/a.txt", "trash/nested/b.txt", "trash-keep/c.txt"} {
+		_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+			Bucket: testBucket,
+			Key:    key,
+			Body:   strings.NewReader("data"),
+			Size:   4,
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload %s: %v", key, err)
+		}
+	}
+
+	preview, err := s3Service.DeletePrefix(ctx, DeletePrefixInput{Bucket: testBucket, Prefix: "trash", DryRun: true})
+	if err != nil {
+		t.Fatalf("Failed to preview folder delete: %v", err)
+	}
+	if len(preview.Deleted) != 2 {
+		t.Errorf("Expected dry run to list 2 keys, got %v", preview.Deleted)
+	}
+
+	output, err := s3Service.DeletePrefix(ctx, DeletePrefixInput{Bucket: testBucket, Prefix: "trash"})
+	if err != nil {
+		t.Fatalf("Failed to delete folder: %v", err)
+	}
+	if len(output.Deleted) != 2 || len(output.Failed) != 0 {
+		t.Errorf("Expected 2 deleted keys and no failures, got %v and %v", output.Deleted, output.Failed)
+	}
+
+	// Sibling prefixes sharing the folder name must survive
+	result, err := s3Service.ListObjects(ctx, ListObjectsInput{Bucket: testBucket, Prefix: "trash", Recursive: true})
+	if err != nil {
+		t.Fatalf("Failed to list after delete: %v", err)
+	}
+	if len(result.Objects) != 1 || result.Objects[0].Key != "trash-keep/c.txt" {
+		t.Errorf("Expected only trash-keep/c.txt to remain, got %v", result.Objects)
+	}
+}
+
+func testDeleteBucket(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	const scratchBucket = "scratch-bucket"
+	if err := s3Service.CreateBucket(ctx, scratchBucket); err != nil {
+		t.Fatalf("Failed to create scratch bucket: %v", err)
+	}
+	_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+		Bucket: scratchBucket,
+		Key:    "leftover.txt",
+		Body:   strings.NewReader("data"),
+		Size:   4,
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload to scratch bucket: %v", err)
+	}
+
+	// A non-empty bucket is refused unless emptying is requested
+	_, err = s3Service.DeleteBucket(ctx, DeleteBucketInput{Bucket: scratchBucket})
+	var s3cErr *s3cerrors.S3CError
+	if !errors.As(err, &s3cErr) || s3cErr.Code != s3cerrors.CodeS3BucketNotEmpty {
+		t.Errorf("Expected bucket not empty error, got %v", err)
+	}
+
+	output, err := s3Service.DeleteBucket(ctx, DeleteBucketInput{Bucket: scratchBucket, Empty: true})
+	if err != nil {
+		t.Fatalf("Failed to empty and delete bucket: %v", err)
+	}
+	if output.DeletedObjects != 1 {
+		t.Errorf("Expected 1 deleted object, got %d", output.DeletedObjects)
+	}
+
+	buckets, err := s3Service.ListBuckets(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list buckets: %v", err)
+	}
+	if slices.Contains(buckets, scratchBucket) {
+		t.Error("Expected scratch bucket to be deleted")
+	}
+}
+
+func testUpdateMetadata(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+		Bucket:      testBucket,
+		Key:         "site/index.html",
+		Body:        strings.NewReader("<html></html>"),
+		Size:        13,
+		ContentType: "application/octet-stream",
+		Metadata:    map[string]string{"original-filename": "index.html"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload object: %v", err)
+	}
+
+	_, err = s3Service.UpdateMetadata(ctx, UpdateMetadataInput{
+		Bucket: testBucket,
+		Key:    "site/index.html",
+		Update: MetadataUpdate{
+			ContentType:  aws.String("text/html"),
+			CacheControl: aws.String("max-age=60"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to update metadata: %v", err)
+	}
+
+	details, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: "site/index.html"})
+	if err != nil {
+		t.Fatalf("Failed to inspect object: %v", err)
+	}
+	if details.ContentType != "text/html" || details.CacheControl != "max-age=60" {
+		t.Errorf("Expected updated headers, got content type %q and cache control %q", details.ContentType, details.CacheControl)
+	}
+	if details.Metadata["original-filename"] != "index.html" {
+		t.Errorf("Expected user metadata to be kept, got %v", details.Metadata)
+	}
+	if details.Size != 13 {
+		t.Errorf("Expected object content to be kept, got size %d", details.Size)
+	}
+
+	output, err := s3Service.UpdateMetadata(ctx, UpdateMetadataInput{
+		Bucket: testBucket,
+		Prefix: "site",
+		Update: MetadataUpdate{CacheControl: aws.String("no-store")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to update prefix metadata: %v", err)
+	}
+	if len(output.Updated) != 1 || len(output.Failed) != 0 {
+		t.Errorf("Expected one updated object, got %v and %v", output.Updated, output.Failed)
+	}
+}
+
+func testObjectVersions(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	const versionedBucket = "versioned-bucket"
+	if err := s3Service.CreateBucket(ctx, versionedBucket); err != nil {
+		t.Fatalf("Failed to create versioned bucket: %v", err)
+	}
+	versioning, err := s3Service.GetBucketVersioning(ctx, versionedBucket)
+	if err != nil {
+		t.Fatalf("Failed to get bucket versioning: %v", err)
+	}
+	if versioning.Status != VersioningUnversioned {
+		t.Errorf("Expected new bucket to be unversioned, got %q", versioning.Status)
+	}
+	err = s3Service.PutBucketVersioning(ctx, PutBucketVersioningInput{Bucket: versionedBucket, Status: VersioningEnabled})
+	if err != nil {
+		t.Fatalf("Failed to enable versioning: %v", err)
+	}
+	versioning, err = s3Service.GetBucketVersioning(ctx, versionedBucket)
+	if err != nil {
+		t.Fatalf("Failed to get bucket versioning: %v", err)
+	}
+	if versioning.Status != VersioningEnabled {
+		t.Errorf("Expected versioning to be enabled, got %q", versioning.Status)
+	}
+
+	for _, content := range []string{"first", "second"} {
+		_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+			Bucket: versionedBucket,
+			Key:    "notes.txt",
+			Body:   strings.NewReader(content),
+			Size:   int64(len(content)),
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload version %q: %v", content, err)
+		}
+	}
+	if err := s3Service.DeleteObject(ctx, versionedBucket, "notes.txt"); err != nil {
+		t.Fatalf("Failed to delete object: %v", err)
+	}
+
+	output, err := s3Service.ListObjectVersions(ctx, ListObjectVersionsInput{Bucket: versionedBucket})
+	if err != nil {
+		t.Fatalf("Failed to list object versions: %v", err)
+	}
+	if len(output.Versions) != 3 {
+		t.Fatalf("Expected 2 versions and a delete marker, got %+v", output.Versions)
+	}
+	if !output.Versions[0].IsDeleteMarker || !output.Versions[0].IsLatest {
+		t.Errorf("Expected latest entry to be a delete marker, got %+v", output.Versions[0])
+	}
+	oldest := output.Versions[2]
+
+	// Read the first version directly
+	download, err := s3Service.DownloadObject(ctx, DownloadObjectInput{
+		Bucket:    versionedBucket,
+		Key:       "notes.txt",
+		VersionID: oldest.VersionID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to download old version: %v", err)
+	}
+	body, _ := io.ReadAll(download.Body)
+	download.Body.Close()
+	if string(body) != "first" {
+		t.Errorf("Expected old version content %q, got %q", "first", body)
+	}
+
+	if _, err := s3Service.UndeleteObject(ctx, versionedBucket, "notes.txt"); err != nil {
+		t.Fatalf("Failed to undelete object: %v", err)
+	}
+	if _, err := s3Service.RestoreObjectVersion(ctx, versionedBucket, "notes.txt", oldest.VersionID); err != nil {
+		t.Fatalf("Failed to restore old version: %v", err)
+	}
+
+	download, err = s3Service.DownloadObject(ctx, DownloadObjectInput{Bucket: versionedBucket, Key: "notes.txt"})
+	if err != nil {
+		t.Fatalf("Failed to download restored object: %v", err)
+	}
+	body, _ = io.ReadAll(download.Body)
+	download.Body.Close()
+	if string(body) != "first" {
+		t.Errorf("Expected restored content %q, got %q", "first", body)
+	}
+
+	output, err = s3Service.ListObjectVersions(ctx, ListObjectVersionsInput{Bucket: versionedBucket})
+	if err != nil {
+		t.Fatalf("Failed to list object versions: %v", err)
+	}
+	if len(output.Versions) != 3 || output.Versions[0].IsDeleteMarker {
+		t.Errorf("Expected restore to add a version after undelete, got %+v", output.Versions)
+	}
+
+	// Moving an old version removes that version only, the current object stays visible
+	_, err = s3Service.MoveObject(ctx, CopyObjectInput{
+		SourceBucket:      versionedBucket,
+		SourceKey:         "notes.txt",
+		SourceVersionID:   oldest.VersionID,
+		DestinationBucket: versionedBucket,
+		DestinationKey:    "archive/notes.txt",
+	})
+	if err != nil {
+		t.Fatalf("Failed to move old version: %v", err)
+	}
+	output, err = s3Service.ListObjectVersions(ctx, ListObjectVersionsInput{Bucket: versionedBucket, Prefix: "notes.txt"})
+	if err != nil {
+		t.Fatalf("Failed to list object versions: %v", err)
+	}
+	if len(output.Versions) != 2 || output.Versions[0].IsDeleteMarker {
+		t.Errorf("Expected move to remove only the old version, got %+v", output.Versions)
+	}
+	for _, version := range output.Versions {
+		if version.VersionID == oldest.VersionID {
+			t.Errorf("Expected moved version %s to be deleted", oldest.VersionID)
+		}
+	}
+}
+
+func testPresignedRequests(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	// Upload through a presigned PUT URL, without credentials
+	put, err := s3Service.PresignPutObject(ctx, PresignPutObjectInput{
+		Bucket:      testBucket,
+		Key:         "share/link.txt",
+		ContentType: "text/plain",
+	})
+	if err != nil {
+		t.Fatalf("Failed to presign upload: %v", err)
+	}
+	req, _ := http.NewRequestWithContext(ctx, put.Method, put.URL, strings.NewReader("shared"))
+	for name, values := range put.SignedHeader {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to upload through presigned URL: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected presigned upload to succeed, got status %d", resp.StatusCode)
+	}
+
+	presigned, err := s3Service.PresignGetObject(ctx, PresignGetObjectInput{
+		Bucket:             testBucket,
+		Key:                "share/link.txt",
+		ContentDisposition: `attachment; filename="link.txt"`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to presign object: %v", err)
+	}
+
+	resp, err = http.Get(presigned.URL)
+	if err != nil {
+		t.Fatalf("Failed to fetch presigned URL: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "shared" {
+		t.Errorf("Expected object content, got status %d and body %q", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename="link.txt"` {
+		t.Errorf("Expected content disposition override, got %q", got)
+	}
+
+	// Upload a file through a browser-style POST policy
+	post, err := s3Service.PresignPostObject(ctx, PresignPostObjectInput{
+		Bucket:           testBucket,
+		KeyPrefix:        "partner/",
+		MaxContentLength: 1024,
+	})
+	if err != nil {
+		t.Fatalf("Failed to presign POST policy: %v", err)
+	}
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	for name, value := range post.Fields {
+		writer.WriteField(name, value)
+	}
+	part, _ := writer.CreateFormFile("file", "invoice.txt")
+	part.Write([]byte("invoice"))
+	writer.Close()
+
+	resp, err = http.Post(post.URL, writer.FormDataContentType(), &form)
+	if err != nil {
+		t.Fatalf("Failed to post form: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		t.Fatalf("Expected POST upload to succeed, got status %d", resp.StatusCode)
+	}
+	if _, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: "partner/invoice.txt"}); err != nil {
+		t.Errorf("Expected uploaded file under the prefix: %v", err)
+	}
+}
+
+func testObjectTagging(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	for _, key := range []string{"tagged/a.log", "tagged/b.log"} {
+		_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+			Bucket: testBucket,
+			Key:    key,
+			Body:   strings.NewReader("log"),
+			Size:   3,
+			Tags:   map[string]string{"team": "platform"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to upload %s: %v", key, err)
+		}
+	}
+
+	tags, err := s3Service.GetObjectTagging(ctx, ObjectTaggingInput{Bucket: testBucket, Key: "tagged/a.log"})
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if tags.Tags["team"] != "platform" {
+		t.Errorf("Expected tag from upload, got %v", tags.Tags)
+	}
+
+	output, err := s3Service.TagPrefix(ctx, TagPrefixInput{
+		Bucket: testBucket,
+		Prefix: "tagged",
+		Tags:   map[string]string{"retention": "30d"},
+		Merge:  true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to tag prefix: %v", err)
+	}
+	if len(output.Tagged) != 2 || len(output.Failed) != 0 {
+		t.Errorf("Expected 2 tagged objects, got %v and %v", output.Tagged, output.Failed)
+	}
+
+	tags, err = s3Service.GetObjectTagging(ctx, ObjectTaggingInput{Bucket: testBucket, Key: "tagged/b.log"})
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if tags.Tags["team"] != "platform" || tags.Tags["retention"] != "30d" {
+		t.Errorf("Expected merged tags, got %v", tags.Tags)
+	}
+
+	if err := s3Service.DeleteObjectTagging(ctx, ObjectTaggingInput{Bucket: testBucket, Key: "tagged/b.log"}); err != nil {
+		t.Fatalf("Failed to delete tags: %v", err)
+	}
+	tags, err = s3Service.GetObjectTagging(ctx, ObjectTaggingInput{Bucket: testBucket, Key: "tagged/b.log"})
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(tags.Tags) != 0 {
+		t.Errorf("Expected no tags after delete, got %v", tags.Tags)
+	}
+}
+
+func testBucketPolicy(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	policy, err := s3Service.GetBucketPolicy(ctx, testBucket)
+	if err != nil {
+		t.Fatalf("Failed to get policy: %v", err)
+	}
+	if policy.Exists {
+		t.Fatalf("Expected no policy on a fresh bucket, got %s", policy.Policy)
+	}
+
+	document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},` +
+		`"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::` + testBucket + `/public/*"]}]}`
+	if err := s3Service.PutBucketPolicy(ctx, testBucket, document); err != nil {
+		t.Fatalf("Failed to put policy: %v", err)
+	}
+
+	policy, err = s3Service.GetBucketPolicy(ctx, testBucket)
+	if err != nil {
+		t.Fatalf("Failed to get policy: %v", err)
+	}
+	if !policy.Exists || !strings.Contains(policy.Policy, "\n") || !strings.Contains(policy.Policy, "s3:GetObject") {
+		t.Errorf("Expected pretty-printed policy, got %s", policy.Policy)
+	}
+
+	if err := s3Service.DeleteBucketPolicy(ctx, testBucket); err != nil {
+		t.Fatalf("Failed to delete policy: %v", err)
+	}
+	// Deleting again is a no-op
+	if err := s3Service.DeleteBucketPolicy(ctx, testBucket); err != nil {
+		t.Errorf("Expected second delete to succeed, got %v", err)
+	}
+}
+
+func testBucketCors(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	cors, err := s3Service.GetBucketCors(ctx, testBucket)
+	if err != nil {
+		t.Fatalf("Failed to get CORS: %v", err)
+	}
+	if len(cors.Rules) != 0 {
+		t.Fatalf("Expected no CORS rules on a fresh bucket, got %+v", cors.Rules)
+	}
+
+	rules := []CORSRule{{
+		ID:             "assets",
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{"GET", "HEAD"},
+		AllowedHeaders: []string{"*"},
+		ExposeHeaders:  []string{"ETag"},
+		MaxAgeSeconds:  3600,
+	}}
+	if err := s3Service.PutBucketCors(ctx, testBucket, rules); err != nil {
+		t.Fatalf("Failed to put CORS: %v", err)
+	}
+
+	cors, err = s3Service.GetBucketCors(ctx, testBucket)
+	if err != nil {
+		t.Fatalf("Failed to get CORS: %v", err)
+	}
+	if len(cors.Rules) != 1 || cors.Rules[0].MaxAgeSeconds != 3600 || len(cors.Rules[0].AllowedMethods) != 2 {
+		t.Errorf("Expected stored rule, got %+v", cors.Rules)
+	}
+
+	if err := s3Service.DeleteBucketCors(ctx, testBucket); err != nil {
+		t.Fatalf("Failed to delete CORS: %v", err)
+	}
+}
+
+func testBucketLifecycle(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	rule := LifecycleRule{
+		ID:                                 "expire-scratch",
+		Enabled:                            true,
+		Prefix:                             "scratch/",
+		Expiration:                         &LifecycleExpiration{Days: 7},
+		AbortIncompleteMultipartUploadDays: 1,
+	}
+	if err := s3Service.PutBucketLifecycle(ctx, testBucket, []LifecycleRule{rule}); err != nil {
+		t.Fatalf("Failed to put lifecycle: %v", err)
+	}
+
+	lifecycle, err := s3Service.GetBucketLifecycle(ctx, testBucket)
+	if err != nil {
+		t.Fatalf("Failed to get lifecycle: %v", err)
+	}
+	if len(lifecycle.Rules) != 1 || lifecycle.Rules[0].Prefix != "scratch/" || lifecycle.Rules[0].Expiration.Days != 7 {
+		t.Errorf("Expected stored rule, got %+v", lifecycle.Rules)
+	}
+
+	for _, key := range []string{"scratch/a.tmp", "scratch/b.tmp", "keep.txt"} {
+		_, err := s3Service.UploadObject(ctx, UploadObjectInput{Bucket: testBucket, Key: key, Body: strings.NewReader("x"), Size: 1})
+		if err != nil {
+			t.Fatalf("Failed to upload %s: %v", key, err)
+		}
+	}
+
+	preview, err := s3Service.PreviewLifecycleRule(ctx, LifecyclePreviewInput{Bucket: testBucket, Prefix: "scratch/", Rule: rule})
+	if err != nil {
+		t.Fatalf("Failed to preview rule: %v", err)
+	}
+	if len(preview.Matched) != 2 || len(preview.Matched[0].Actions) != 1 {
+		t.Errorf("Expected both scratch objects to expire, got %+v", preview.Matched)
+	}
+
+	if err := s3Service.DeleteBucketLifecycle(ctx, testBucket); err != nil {
+		t.Fatalf("Failed to delete lifecycle: %v", err)
+	}
+	lifecycle, err = s3Service.GetBucketLifecycle(ctx, testBucket)
+	if err != nil {
+		t.Fatalf("Failed to get lifecycle: %v", err)
+	}
+	if len(lifecycle.Rules) != 0 {
+		t.Errorf("Expected no rules after delete, got %+v", lifecycle.Rules)
+	}
+}
+
+func testServerSideEncryption(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	for key, sse := range map[string]*ServerSideEncryption{
+		"sse/s3.txt":  {Mode: SSEModeS3},
+		"sse/kms.txt": {Mode: SSEModeKMS},
+	} {
+		_, err := s3Service.UploadObject(ctx, UploadObjectInput{Bucket: testBucket, Key: key, Body: strings.NewReader("managed"), Size: 7, Encryption: sse})
+		if err != nil {
+			t.Fatalf("Failed to upload %s: %v", key, err)
+		}
+		details, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: key})
+		if err != nil {
+			t.Fatalf("Failed to head %s: %v", key, err)
+		}
+		if details.ServerSideEncryption == "" {
+			t.Errorf("Expected %s to report its encryption", key)
+		}
+	}
+
+	customerKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+		Bucket:     testBucket,
+		Key:        "sse/customer.txt",
+		Body:       strings.NewReader("customer"),
+		Size:       8,
+		Encryption: &ServerSideEncryption{Mode: SSEModeC, CustomerKey: customerKey},
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload SSE-C object: %v", err)
+	}
+
+	if _, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: "sse/customer.txt"}); err == nil {
+		t.Error("Expected head without the customer key to fail")
+	}
+	details, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: "sse/customer.txt", SSECustomerKey: customerKey})
+	if err != nil {
+		t.Fatalf("Failed to head SSE-C object: %v", err)
+	}
+	if details.SSECustomerAlgorithm != "AES256" {
+		t.Errorf("Expected AES256 customer algorithm, got %q", details.SSECustomerAlgorithm)
+	}
+
+	download, err := s3Service.DownloadObject(ctx, DownloadObjectInput{Bucket: testBucket, Key: "sse/customer.txt", SSECustomerKey: customerKey})
+	if err != nil {
+		t.Fatalf("Failed to download SSE-C object: %v", err)
+	}
+	body, _ := io.ReadAll(download.Body)
+	download.Body.Close()
+	if string(body) != "customer" {
+		t.Errorf("Expected decrypted body, got %q", body)
+	}
+
+	// Re-encrypt the copy with S3 managed keys so it can be read without the customer key
+	_, err = s3Service.CopyObject(ctx, CopyObjectInput{
+		SourceBucket:         testBucket,
+		SourceKey:            "sse/customer.txt",
+		DestinationBucket:    testBucket,
+		DestinationKey:       "sse/copied.txt",
+		Encryption:           &ServerSideEncryption{Mode: SSEModeS3},
+		SourceSSECustomerKey: customerKey,
+	})
+	if err != nil {
+		t.Fatalf("Failed to copy SSE-C object: %v", err)
+	}
+	copied, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: "sse/copied.txt"})
+	if err != nil {
+		t.Fatalf("Failed to head copied object: %v", err)
+	}
+	if copied.SSECustomerAlgorithm != "" {
+		t.Errorf("Expected the copy to drop SSE-C, got %q", copied.SSECustomerAlgorithm)
+	}
+}
+
+func testClientSideEncryption(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	plain := strings.Repeat("payroll,", 20000) // several chunks
+	_, err := s3Service.UploadObject(ctx, UploadObjectInput{
+		Bucket:           testBucket,
+		Key:              "cse/payroll.csv",
+		Body:             strings.NewReader(plain),
+		Size:             int64(len(plain)),
+		ClientEncryption: &ClientEncryption{KeySource: ClientKeySourcePassphrase, Passphrase: "correct horse"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload client-side encrypted object: %v", err)
+	}
+
+	details, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: "cse/payroll.csv"})
+	if err != nil {
+		t.Fatalf("Failed to head client-side encrypted object: %v", err)
+	}
+	if details.ClientEncryption != ClientKeySourcePassphrase {
+		t.Errorf("Expected passphrase client-side encryption, got %q", details.ClientEncryption)
+	}
+
+	if _, err := s3Service.DownloadObject(ctx, DownloadObjectInput{Bucket: testBucket, Key: "cse/payroll.csv"}); err == nil {
+		t.Error("Expected download without the passphrase to fail")
+	}
+
+	// Ranges cannot be served from ciphertext, so the whole object comes back
+	download, err := s3Service.DownloadObject(ctx, DownloadObjectInput{Bucket: testBucket, Key: "cse/payroll.csv", Range: "bytes=0-9", ClientPassphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("Failed to download client-side encrypted object: %v", err)
+	}
+	body, err := io.ReadAll(download.Body)
+	download.Body.Close()
+	if err != nil {
+		t.Fatalf("Failed to decrypt body: %v", err)
+	}
+	if string(body) != plain {
+		t.Errorf("Expected decrypted body of %d bytes, got %d bytes", len(plain), len(body))
+	}
+}
+
+func testStorageClass(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	for key, class := range map[string]string{"tiers/warm.txt": "STANDARD_IA", "tiers/hot.txt": ""} {
+		_, err := s3Service.UploadObject(ctx, UploadObjectInput{Bucket: testBucket, Key: key, Body: strings.NewReader("tier"), Size: 4, StorageClass: class})
+		if err != nil {
+			t.Fatalf("Failed to upload %s: %v", key, err)
+		}
+	}
+
+	listStorageClasses := func() map[string]string {
+		listing, err := s3Service.ListObjects(ctx, ListObjectsInput{Bucket: testBucket, Prefix: "tiers/", Recursive: true})
+		if err != nil {
+			t.Fatalf("Failed to list tiers: %v", err)
+		}
+		classes := make(map[string]string)
+		for _, obj := range listing.Objects {
+			classes[obj.Key] = obj.StorageClass
+		}
+		return classes
+	}
+
+	classes := listStorageClasses()
+	if classes["tiers/warm.txt"] != "STANDARD_IA" || classes["tiers/hot.txt"] != "STANDARD" {
+		t.Fatalf("Expected STANDARD_IA and STANDARD, got %v", classes)
+	}
+
+	output, err := s3Service.ChangeStorageClass(ctx, ChangeStorageClassInput{Bucket: testBucket, Prefix: "tiers/", StorageClass: "STANDARD_IA"})
+	if err != nil {
+		t.Fatalf("Failed to change storage class: %v", err)
+	}
+	if len(output.Changed) != 1 || output.Changed[0] != "tiers/hot.txt" || len(output.Unchanged) != 1 {
+		t.Errorf("Expected only tiers/hot.txt to change, got %+v", output)
+	}
+
+	if classes := listStorageClasses(); classes["tiers/hot.txt"] != "STANDARD_IA" {
+		t.Errorf("Expected tiers/hot.txt to be STANDARD_IA, got %v", classes)
+	}
+}
+
+func testArchiveRestore(t *testing.T, ctx context.Context, s3Service S3Operations) {
+	_, err := s3Service.UploadObject(ctx, UploadObjectInput{Bucket: testBucket, Key: "cold/backup.tar", Body: strings.NewReader("backup"), Size: 6, StorageClass: "GLACIER"})
+	if err != nil {
+		t.Fatalf("Failed to upload archived object: %v", err)
+	}
+
+	_, err = s3Service.DownloadObject(ctx, DownloadObjectInput{Bucket: testBucket, Key: "cold/backup.tar"})
+	var s3cErr *s3cerrors.S3CError
+	if !errors.As(err, &s3cErr) || s3cErr.Code != s3cerrors.CodeS3ObjectArchived {
+		t.Fatalf("Expected %s before the restore, got %v", s3cerrors.CodeS3ObjectArchived, err)
+	}
+
+	output, err := s3Service.RestoreObject(ctx, RestoreObjectInput{Bucket: testBucket, Prefix: "cold/", Days: 1, Tier: RestoreTierStandard})
+	if err != nil {
+		t.Fatalf("Failed to request restore: %v", err)
+	}
+	if len(output.Requested)+len(output.InProgress) != 1 {
+		t.Errorf("Expected a restore request for cold/backup.tar, got %+v", output)
+	}
+
+	details, err := s3Service.HeadObject(ctx, HeadObjectInput{Bucket: testBucket, Key: "cold/backup.tar"})
+	if err != nil {
+		t.Fatalf("Failed to head archived object: %v", err)
+	}
+	if details.Restore == nil {
+		t.Errorf("Expected a restore status after the restore request, got %+v", details)
+	}
+}
+
+func testObjectLock(t *testing.T, ctx context.Context, s3Service S3Operations, endpoint string) {
+	const lockedBucket = "locked-bucket"
+	// Object lock can only be enabled through the bucket creation request
+	client := createDirectS3Client(t, ctx, endpoint)
+	_, err := client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket:                     aws.String(lockedBucket),
+		ObjectLockEnabledForBucket: aws.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create object lock bucket: %v", err)
+	}
+
+	_, err = s3Service.UploadObject(ctx, UploadObjectInput{Bucket: lockedBucket, Key: "ledger.csv", Body: strings.NewReader("ledger"), Size: 6})
+	if err != nil {
+		t.Fatalf("Failed to upload object: %v", err)
+	}
+
+	err = s3Service.PutObjectRetention(ctx, PutObjectRetentionInput{
+		Bucket:          lockedBucket,
+		Key:             "ledger.csv",
+		Mode:            RetentionModeGovernance,
+		RetainUntilDate: time.Now().Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Failed to set retention: %v", err)
+	}
+	retention, err := s3Service.GetObjectRetention(ctx, ObjectLockInput{Bucket: lockedBucket, Key: "ledger.csv"})
+	if err != nil {
+		t.Fatalf("Failed to get retention: %v", err)
+	}
+	if retention.Mode != RetentionModeGovernance || retention.RetainUntilDate == "" {
+		t.Errorf("Expected GOVERNANCE retention, got %+v", retention)
+	}
+
+	for _, status := range []string{LegalHoldOn, LegalHoldOff} {
+		err = s3Service.PutObjectLegalHold(ctx, PutObjectLegalHoldInput{Bucket: lockedBucket, Key: "ledger.csv", Status: status})
+		if err != nil {
+			t.Fatalf("Failed to set legal hold %s: %v", status, err)
+		}
+		hold, err := s3Service.GetObjectLegalHold(ctx, ObjectLockInput{Bucket: lockedBucket, Key: "ledger.csv"})
+		if err != nil {
+			t.Fatalf("Failed to get legal hold: %v", err)
+		}
+		if hold.Status != status {
+			t.Errorf("Expected legal hold %s, got %s", status, hold.Status)
+		}
+	}
+
+	_, err = s3Service.DeleteBucket(ctx, DeleteBucketInput{Bucket: lockedBucket, Empty: true})
+	var s3cErr *s3cerrors.S3CError
+	if !errors.As(err, &s3cErr) || s3cErr.Code != s3cerrors.CodeS3ObjectLocked {
+		t.Fatalf("Expected %s while the version is retained, got %v", s3cerrors.CodeS3ObjectLocked, err)
+	}
+
+	_, err = s3Service.DeleteBucket(ctx, DeleteBucketInput{Bucket: lockedBucket, Empty: true, BypassGovernanceRetention: true})
+	if err != nil {
+		t.Errorf("Expected the governance bypass to empty and delete the bucket, got %v", err)
+	}
+}
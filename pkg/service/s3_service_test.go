@@ -1,9 +1,28 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
 )
@@ -32,6 +51,46 @@ func TestConvertS3Error(t *testing.T) {
 			expectedType:  "*s3cerrors.S3CError",
 			shouldContain: "Bucket",
 		},
+		{
+			name:          "MalformedPolicy error",
+			operation:     "put bucket policy",
+			inputError:    errors.New("MalformedPolicy: Policy has invalid resource"),
+			expectedCode:  s3cerrors.CodeInvalidFormat,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "policy",
+		},
+		{
+			name:          "missing SSE-C key",
+			operation:     "download object",
+			inputError:    errors.New("InvalidRequest: The object was stored using a form of Server Side Encryption. The correct parameters must be provided to retrieve the object."),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "customer-provided key",
+		},
+		{
+			name:          "unknown KMS key",
+			operation:     "upload object",
+			inputError:    errors.New("KMS.NotFoundException: Invalid keyId alias/missing"),
+			expectedCode:  s3cerrors.CodeInvalidInput,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "KMS",
+		},
+		{
+			name:          "archived object",
+			operation:     "download object",
+			inputError:    errors.New("api error InvalidObjectState: The operation is not valid for the object's storage class"),
+			expectedCode:  s3cerrors.CodeS3ObjectArchived,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "archived",
+		},
+		{
+			name:          "object lock denial",
+			operation:     "delete object",
+			inputError:    errors.New("api error AccessDenied: Access Denied because object protected by object lock."),
+			expectedCode:  s3cerrors.CodeS3ObjectLocked,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "object lock",
+		},
 		{
 			name:          "NoSuchKey error",
 			operation:     "get_object",
@@ -40,6 +99,30 @@ func TestConvertS3Error(t *testing.T) {
 			expectedType:  "*s3cerrors.S3CError",
 			shouldContain: "Object",
 		},
+		{
+			name:          "InvalidRange error",
+			operation:     "download object",
+			inputError:    errors.New("InvalidRange: The requested range is not satisfiable"),
+			expectedCode:  s3cerrors.CodeS3InvalidRange,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "range",
+		},
+		{
+			name:          "BucketNotEmpty error",
+			operation:     "delete bucket",
+			inputError:    errors.New("BucketNotEmpty: The bucket you tried to delete is not empty"),
+			expectedCode:  s3cerrors.CodeS3BucketNotEmpty,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "not empty",
+		},
+		{
+			name:          "PreconditionFailed error",
+			operation:     "download object",
+			inputError:    errors.New("PreconditionFailed: At least one of the pre-conditions you specified did not hold"),
+			expectedCode:  s3cerrors.CodeS3PreconditionFailed,
+			expectedType:  "*s3cerrors.S3CError",
+			shouldContain: "precondition",
+		},
 		{
 			name:          "Generic NotFound error",
 			operation:     "operation",
@@ -387,3 +470,1778 @@ func TestS3ConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+// Test multipart option resolution against defaults and S3 limits
+func TestResolveMultipartOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   S3Config
+		expected multipartOptions
+	}{
+		{
+			name:   "zero values use defaults",
+			config: S3Config{},
+			expected: multipartOptions{
+				threshold:   DefaultMultipartThreshold,
+				partSize:    DefaultMultipartPartSize,
+				concurrency: DefaultMultipartConcurrency,
+			},
+		},
+		{
+			name: "part size below S3 minimum is raised",
+			config: S3Config{
+				MultipartThreshold:   10 << 20,
+				MultipartPartSize:    1 << 20,
+				MultipartConcurrency: 2,
+			},
+			expected: multipartOptions{
+				threshold:   10 << 20,
+				partSize:    minMultipartPartSize,
+				concurrency: 2,
+			},
+		},
+		{
+			name: "threshold never below part size",
+			config: S3Config{
+				MultipartThreshold: 1 << 20,
+				MultipartPartSize:  64 << 20,
+			},
+			expected: multipartOptions{
+				threshold:   64 << 20,
+				partSize:    64 << 20,
+				concurrency: DefaultMultipartConcurrency,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveMultipartOptions(tt.config)
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// Test part size growth for very large known sizes
+func TestPartSizeFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int64
+		partSize int64
+		expected int64
+	}{
+		{"unknown size keeps part size", -1, 16 << 20, 16 << 20},
+		{"small size keeps part size", 100 << 20, 16 << 20, 16 << 20},
+		{"huge size grows part size", 500 << 30, 16 << 20, (500<<30 + maxMultipartParts - 1) / maxMultipartParts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := partSizeFor(tt.size, tt.partSize)
+			if got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, got)
+			}
+			if tt.size > 0 && (tt.size+got-1)/got > maxMultipartParts {
+				t.Errorf("Part size %d produces more than %d parts", got, maxMultipartParts)
+			}
+		})
+	}
+}
+
+// Test chunked reads used for upload read-ahead and part buffering
+func TestReadChunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		n        int64
+		expected string
+	}{
+		{"shorter than chunk", "abc", 8, "abc"},
+		{"exact chunk", "abcdefgh", 8, "abcdefgh"},
+		{"longer than chunk", "abcdefghij", 8, "abcdefgh"},
+		{"empty reader", "", 8, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readChunk(strings.NewReader(tt.input), tt.n)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(got))
+			}
+		})
+	}
+}
+
+// Test upload read-ahead sizes its buffer from the declared length
+func TestReadHead(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		n        int64
+		sizeHint int64
+		expected string
+	}{
+		{"known small body", "abc", 1 << 20, 3, "abc"},
+		{"unknown length", "abcdefghij", 1 << 20, -1, "abcdefghij"},
+		{"longer than declared", "abcdefghij", 1 << 20, 3, "abcdefghij"},
+		{"longer than limit", "abcdefghij", 8, -1, "abcdefgh"},
+		{"empty body", "", 1 << 20, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readHead(strings.NewReader(tt.input), tt.n, tt.sizeHint)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, string(got))
+			}
+			if cap(got) >= int(tt.n) && tt.n > int64(len(tt.input))+bytes.MinRead {
+				t.Errorf("Buffer capacity %d was not sized from the body", cap(got))
+			}
+		})
+	}
+}
+
+// Test the shared worker pool keeps results aligned and stops starting work once cancelled
+func TestForEachKey(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+
+	t.Run("runs every key by index", func(t *testing.T) {
+		seen := make([]string, len(keys))
+		errs := forEachKey(context.Background(), keys, func(i int, key string) error {
+			seen[i] = key
+			if key == "c" {
+				return errors.New("boom")
+			}
+			return nil
+		})
+
+		if !reflect.DeepEqual(seen, keys) {
+			t.Errorf("Expected every key at its index, got %v", seen)
+		}
+		for i, err := range errs {
+			if (err != nil) != (keys[i] == "c") {
+				t.Errorf("Unexpected error for %s: %v", keys[i], err)
+			}
+		}
+	})
+
+	t.Run("cancelled context starts nothing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		errs := forEachKey(ctx, keys, func(i int, key string) error {
+			t.Errorf("Did not expect %s to run", key)
+			return nil
+		})
+
+		for i, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected %s to report cancellation, got %v", keys[i], err)
+			}
+		}
+	})
+}
+
+// Test CopySource encoding keeps path separators and escapes everything else
+func TestCopySource(t *testing.T) {
+	tests := []struct {
+		name     string
+		bucket   string
+		key      string
+		expected string
+	}{
+		{"simple key", "bucket", "file.txt", "bucket/file.txt"},
+		{"nested key", "bucket", "a/b/c.txt", "bucket/a/b/c.txt"},
+		{"spaces and symbols", "bucket", "my docs/a+b&c.txt", "bucket/my%20docs/a+b&c.txt"},
+		{"unicode key", "bucket", "フォルダ/ファイル.txt", "bucket/%E3%83%95%E3%82%A9%E3%83%AB%E3%83%80/%E3%83%95%E3%82%A1%E3%82%A4%E3%83%AB.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := copySource(tt.bucket, tt.key); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// Test prefix transfers reject destinations nested in the source
+func TestValidatePrefixTransfer(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     CopyPrefixInput
+		expectErr bool
+	}{
+		{"rename sibling folder", CopyPrefixInput{SourceBucket: "b", SourcePrefix: "reports/2024/", DestinationBucket: "b", DestinationPrefix: "archive/2024/"}, false},
+		{"move to parent", CopyPrefixInput{SourceBucket: "b", SourcePrefix: "a/b/", DestinationBucket: "b", DestinationPrefix: "a/"}, false},
+		{"similar name", CopyPrefixInput{SourceBucket: "b", SourcePrefix: "reports/", DestinationBucket: "b", DestinationPrefix: "reports-old/"}, false},
+		{"same prefix", CopyPrefixInput{SourceBucket: "b", SourcePrefix: "a/", DestinationBucket: "b", DestinationPrefix: "a/"}, true},
+		{"nested destination", CopyPrefixInput{SourceBucket: "b", SourcePrefix: "a/", DestinationBucket: "b", DestinationPrefix: "a/b/"}, true},
+		{"whole bucket within itself", CopyPrefixInput{SourceBucket: "b", DestinationBucket: "b", DestinationPrefix: "backup/"}, true},
+		{"same prefix in another bucket", CopyPrefixInput{SourceBucket: "b", SourcePrefix: "a/", DestinationBucket: "c", DestinationPrefix: "a/"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePrefixTransfer(tt.input)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Expected error %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+// Test folder prefixes always end with a slash
+func TestNormalizePrefix(t *testing.T) {
+	tests := map[string]string{
+		"":         "",
+		"reports":  "reports/",
+		"reports/": "reports/",
+		"a/b":      "a/b/",
+	}
+	for input, expected := range tests {
+		if got := normalizePrefix(input); got != expected {
+			t.Errorf("normalizePrefix(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+// Test HeadObject responses are converted into structured object details
+func TestObjectDetailsFromHead(t *testing.T) {
+	retainUntil := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("full response", func(t *testing.T) {
+		details := objectDetailsFromHead("bucket", "key.txt", &s3.HeadObjectOutput{
+			ContentLength:             aws.Int64(42),
+			ContentType:               aws.String("text/plain"),
+			ETag:                      aws.String(`"etag"`),
+			StorageClass:              types.StorageClassGlacierIr,
+			ServerSideEncryption:      types.ServerSideEncryptionAwsKms,
+			SSEKMSKeyId:               aws.String("key-id"),
+			ChecksumSHA256:            aws.String("sha"),
+			ChecksumType:              types.ChecksumTypeFullObject,
+			ObjectLockMode:            types.ObjectLockModeCompliance,
+			ObjectLockRetainUntilDate: &retainUntil,
+			VersionId:                 aws.String("v1"),
+			Metadata:                  map[string]string{"owner": "ci"},
+		})
+
+		if details.Size != 42 || details.ContentType != "text/plain" || details.VersionID != "v1" {
+			t.Errorf("Unexpected details: %+v", details)
+		}
+		if details.StorageClass != "GLACIER_IR" || details.ServerSideEncryption != "aws:kms" || details.SSEKMSKeyID != "key-id" {
+			t.Errorf("Unexpected storage or encryption: %+v", details)
+		}
+		if details.Checksums == nil || details.Checksums.SHA256 != "sha" || details.Checksums.Type != "FULL_OBJECT" {
+			t.Errorf("Unexpected checksums: %+v", details.Checksums)
+		}
+		if details.ObjectLock == nil || details.ObjectLock.RetainUntilDate != "2030-01-02T03:04:05Z" {
+			t.Errorf("Unexpected object lock: %+v", details.ObjectLock)
+		}
+		if details.Metadata["owner"] != "ci" {
+			t.Errorf("Unexpected metadata: %v", details.Metadata)
+		}
+	})
+
+	t.Run("minimal response", func(t *testing.T) {
+		details := objectDetailsFromHead("bucket", "key.txt", &s3.HeadObjectOutput{})
+
+		if details.StorageClass != "STANDARD" {
+			t.Errorf("Expected STANDARD storage class, got %q", details.StorageClass)
+		}
+		if details.Checksums != nil || details.ObjectLock != nil {
+			t.Errorf("Expected no checksums or lock state, got %+v and %+v", details.Checksums, details.ObjectLock)
+		}
+		if details.Metadata == nil {
+			t.Error("Expected empty metadata map, got nil")
+		}
+	})
+}
+
+// Test in-place metadata updates keep everything the update does not mention
+func TestMergeMetadataUpdate(t *testing.T) {
+	current := &s3.HeadObjectOutput{
+		ContentType:          aws.String("application/octet-stream"),
+		CacheControl:         aws.String("no-cache"),
+		ContentEncoding:      aws.String("gzip"),
+		Metadata:             map[string]string{"original-filename": "app.js"},
+		StorageClass:         types.StorageClassStandardIa,
+		ServerSideEncryption: types.ServerSideEncryptionAwsKms,
+		SSEKMSKeyId:          aws.String("kms-key"),
+		BucketKeyEnabled:     aws.Bool(true),
+	}
+
+	t.Run("keeps unset fields", func(t *testing.T) {
+		headers := mergeMetadataUpdate(current, MetadataUpdate{ContentType: aws.String("text/javascript")})
+
+		if headers.MetadataDirective != types.MetadataDirectiveReplace {
+			t.Errorf("Expected REPLACE directive, got %q", headers.MetadataDirective)
+		}
+		if aws.ToString(headers.ContentType) != "text/javascript" {
+			t.Errorf("Expected new content type, got %q", aws.ToString(headers.ContentType))
+		}
+		if aws.ToString(headers.CacheControl) != "no-cache" || aws.ToString(headers.ContentEncoding) != "gzip" {
+			t.Error("Expected other headers to be kept")
+		}
+		if headers.Metadata["original-filename"] != "app.js" {
+			t.Errorf("Expected user metadata to be kept, got %v", headers.Metadata)
+		}
+		if headers.StorageClass != types.StorageClassStandardIa {
+			t.Errorf("Expected storage class to be kept, got %q", headers.StorageClass)
+		}
+		if headers.ServerSideEncryption != types.ServerSideEncryptionAwsKms || aws.ToString(headers.SSEKMSKeyId) != "kms-key" {
+			t.Error("Expected KMS encryption to be kept")
+		}
+		if headers.TaggingDirective != "" || headers.Tagging != nil {
+			t.Error("Expected tags to be copied unchanged")
+		}
+	})
+
+	t.Run("removes and replaces", func(t *testing.T) {
+		headers := mergeMetadataUpdate(current, MetadataUpdate{
+			CacheControl:         aws.String(""),
+			Metadata:             map[string]string{"owner": "ci"},
+			StorageClass:         "GLACIER_IR",
+			ServerSideEncryption: "AES256",
+			Tags:                 map[string]string{"team": "data", "env": "dev"},
+		})
+
+		if headers.CacheControl != nil {
+			t.Errorf("Expected cache control to be removed, got %q", aws.ToString(headers.CacheControl))
+		}
+		if len(headers.Metadata) != 1 || headers.Metadata["owner"] != "ci" {
+			t.Errorf("Expected user metadata to be replaced, got %v", headers.Metadata)
+		}
+		if headers.StorageClass != types.StorageClassGlacierIr {
+			t.Errorf("Expected GLACIER_IR storage class, got %q", headers.StorageClass)
+		}
+		if headers.ServerSideEncryption != types.ServerSideEncryptionAes256 || headers.SSEKMSKeyId != nil || headers.BucketKeyEnabled != nil {
+			t.Error("Expected AES256 encryption without KMS settings")
+		}
+		if headers.TaggingDirective != types.TaggingDirectiveReplace || aws.ToString(headers.Tagging) != "env=dev&team=data" {
+			t.Errorf("Expected replaced tags, got %q", aws.ToString(headers.Tagging))
+		}
+	})
+
+	t.Run("kms key implies SSE-KMS", func(t *testing.T) {
+		headers := mergeMetadataUpdate(&s3.HeadObjectOutput{}, MetadataUpdate{SSEKMSKeyID: "new-key"})
+
+		if headers.ServerSideEncryption != types.ServerSideEncryptionAwsKms || aws.ToString(headers.SSEKMSKeyId) != "new-key" {
+			t.Errorf("Expected SSE-KMS with new key, got %q and %q", headers.ServerSideEncryption, aws.ToString(headers.SSEKMSKeyId))
+		}
+	})
+}
+
+// newFakeTransportService creates a service whose S3 client sends every request to transport
+func newFakeTransportService(t *testing.T, transport http.RoundTripper) *AWSS3Service {
+	t.Helper()
+	client := s3.New(s3.Options{
+		Region:           "us-east-1",
+		Credentials:      credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		BaseEndpoint:     aws.String("http://localhost:9000"),
+		UsePathStyle:     true,
+		HTTPClient:       &http.Client{Transport: transport},
+		RetryMaxAttempts: 1,
+	})
+	return &AWSS3Service{client: client, logger: slog.New(slog.DiscardHandler)}
+}
+
+// fakeS3 is an S3 endpoint for service tests. It records every request and answers
+// from its route table, where the first route matching the method and query wins.
+type fakeS3 struct {
+	mu       sync.Mutex
+	routes   []fakeRoute
+	requests []fakeRequest
+}
+
+// fakeRoute answers requests with method (any when empty) that carry the query
+// parameter query (any when empty), with respond when set and response otherwise
+type fakeRoute struct {
+	method   string
+	query    string
+	response fakeResponse
+	respond  func(req fakeRequest) fakeResponse
+}
+
+// fakeRequest is a recorded request with its body read and its path split into bucket and key
+type fakeRequest struct {
+	method string
+	bucket string
+	key    string
+	query  url.Values
+	header http.Header
+	body   string
+}
+
+// fakeResponse is the status, extra headers and body sent back for a request
+type fakeResponse struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// reply builds a response without extra headers
+func reply(status int, body string) fakeResponse {
+	return fakeResponse{status: status, body: body}
+}
+
+// replyError builds an S3 error response with code
+func replyError(status int, code string) fakeResponse {
+	return reply(status, "<Error><Code>"+code+"</Code><Message>"+code+"</Message></Error>")
+}
+
+// okRoute answers every request with an empty success carrying an ETag
+var okRoute = fakeRoute{response: fakeResponse{status: http.StatusOK, header: http.Header{"Etag": {`"etag"`}}}}
+
+func (f *fakeS3) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	recorded := fakeRequest{method: req.Method, query: req.URL.Query(), header: req.Header.Clone()}
+	recorded.bucket, recorded.key, _ = strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		recorded.body = string(body)
+	}
+	f.requests = append(f.requests, recorded)
+
+	response := replyError(http.StatusNotImplemented, "NotImplemented")
+	for _, route := range f.routes {
+		if (route.method == "" || route.method == req.Method) && (route.query == "" || recorded.query.Has(route.query)) {
+			response = route.response
+			if route.respond != nil {
+				response = route.respond(recorded)
+			}
+			break
+		}
+	}
+
+	header := http.Header{"Content-Type": {"application/xml"}}
+	maps.Copy(header, response.header)
+	return &http.Response{StatusCode: response.status, Header: header, Body: io.NopCloser(strings.NewReader(response.body)), Request: req}, nil
+}
+
+// sent returns the recorded requests with method that carry the query parameter query (any when empty)
+func (f *fakeS3) sent(method, query string) []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var matched []fakeRequest
+	for _, req := range f.requests {
+		if req.method == method && (query == "" || req.query.Has(query)) {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+func TestRewriteLargeObjectIsConditional(t *testing.T) {
+	// Arrange
+	transport := &fakeS3{routes: []fakeRoute{
+		{method: http.MethodHead, response: fakeResponse{status: http.StatusOK, header: http.Header{"Etag": {`"v1"`}, "Content-Length": {strconv.FormatInt(6<<30, 10)}}}},
+		{method: http.MethodGet, query: "tagging", response: reply(http.StatusOK, "<Tagging><TagSet></TagSet></Tagging>")},
+		{method: http.MethodPost, query: "uploads", response: reply(http.StatusOK, "<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>")},
+		{method: http.MethodPut, query: "partNumber", response: reply(http.StatusOK, `<CopyPartResult><ETag>"part"</ETag></CopyPartResult>`)},
+		{method: http.MethodPost, query: "uploadId", response: reply(http.StatusOK, `<CompleteMultipartUploadResult><ETag>"v2"</ETag></CompleteMultipartUploadResult>`)},
+		{method: http.MethodDelete, response: reply(http.StatusNoContent, "")},
+	}}
+	service := newFakeTransportService(t, transport)
+
+	// Act
+	err := service.updateObjectMetadata(context.Background(), "test-bucket", "big.bin", MetadataUpdate{ContentType: aws.String("text/plain")})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("updateObjectMetadata() error = %v", err)
+	}
+	parts := transport.sent(http.MethodPut, "partNumber")
+	if len(parts) != 12 {
+		t.Fatalf("Expected 12 part copies, got %d", len(parts))
+	}
+	for i, part := range parts {
+		if ifMatch := part.header.Get("X-Amz-Copy-Source-If-Match"); ifMatch != `"v1"` {
+			t.Errorf("Expected part copy %d to require ETag \"v1\", got %q", i, ifMatch)
+		}
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []ObjectVersion{
+		{Key: "b.txt", VersionID: "b1", LastModified: "2024-01-01T00:00:00Z"},
+		{Key: "b.txt", VersionID: "b2", LastModified: "2024-01-03T00:00:00Z", IsLatest: true, IsDeleteMarker: true},
+		{Key: "a.txt", VersionID: "a1", LastModified: "2024-01-01T00:00:00Z"},
+		// Uploaded within the same second as a1
+		{Key: "a.txt", VersionID: "a2", LastModified: "2024-01-01T00:00:00Z", IsLatest: true},
+	}
+
+	sortVersions(versions)
+
+	var got []string
+	for _, v := range versions {
+		got = append(got, v.VersionID)
+	}
+	want := []string{"a2", "a1", "b2", "b1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sortVersions() order = %v, want %v", got, want)
+	}
+}
+
+// newPresignTestService builds a service with static credentials; presigning never calls S3
+func newPresignTestService(endpoint string, pathStyle bool) *AWSS3Service {
+	options := s3.Options{
+		Region:       "us-west-2",
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		UsePathStyle: pathStyle,
+	}
+	if endpoint != "" {
+		options.BaseEndpoint = aws.String(endpoint)
+	}
+	return &AWSS3Service{client: s3.New(options), logger: slog.Default()}
+}
+
+func TestPresignGetObject(t *testing.T) {
+	tests := []struct {
+		name         string
+		service      *AWSS3Service
+		input        PresignGetObjectInput
+		expectedHost string
+		expectedPath string
+		expectedArgs map[string]string
+	}{
+		{
+			name:         "AWS virtual-hosted URL",
+			service:      newPresignTestService("", false),
+			input:        PresignGetObjectInput{Bucket: "test-bucket", Key: "docs/report.pdf"},
+			expectedHost: "test-bucket.s3.us-west-2.amazonaws.com",
+			expectedPath: "/docs/report.pdf",
+			expectedArgs: map[string]string{"X-Amz-Expires": "3600"},
+		},
+		{
+			name:    "custom endpoint with path-style URL",
+			service: newPresignTestService("http://localhost:4566", true),
+			input: PresignGetObjectInput{
+				Bucket:             "test-bucket",
+				Key:                "docs/report.pdf",
+				VersionID:          "v1",
+				Expires:            15 * time.Minute,
+				ContentDisposition: `attachment; filename="report.pdf"`,
+			},
+			expectedHost: "localhost:4566",
+			expectedPath: "/test-bucket/docs/report.pdf",
+			expectedArgs: map[string]string{
+				"X-Amz-Expires":                "900",
+				"versionId":                    "v1",
+				"response-content-disposition": `attachment; filename="report.pdf"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			presigned, err := tt.service.PresignGetObject(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("PresignGetObject() error = %v", err)
+			}
+
+			u, err := url.Parse(presigned.URL)
+			if err != nil {
+				t.Fatalf("Invalid presigned URL %q: %v", presigned.URL, err)
+			}
+			if u.Host != tt.expectedHost || u.Path != tt.expectedPath {
+				t.Errorf("Expected %s%s, got %s%s", tt.expectedHost, tt.expectedPath, u.Host, u.Path)
+			}
+			for name, want := range tt.expectedArgs {
+				if got := u.Query().Get(name); got != want {
+					t.Errorf("Expected query %s=%q, got %q", name, want, got)
+				}
+			}
+			if presigned.Method != "GET" || presigned.SignedHeader != nil {
+				t.Errorf("Expected a plain GET, got %s with headers %v", presigned.Method, presigned.SignedHeader)
+			}
+			if presigned.ExpiresAt.Before(before) {
+				t.Errorf("Expected expiry in the future, got %v", presigned.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestPresignPostObject(t *testing.T) {
+	service := newPresignTestService("http://localhost:4566", true)
+
+	post, err := service.PresignPostObject(context.Background(), PresignPostObjectInput{
+		Bucket:           "test-bucket",
+		KeyPrefix:        "partner/",
+		ContentType:      "application/pdf",
+		MaxContentLength: 1024,
+	})
+	if err != nil {
+		t.Fatalf("PresignPostObject() error = %v", err)
+	}
+
+	if post.URL != "http://localhost:4566/test-bucket" {
+		t.Errorf("Expected path-style bucket URL, got %s", post.URL)
+	}
+	if post.Fields["key"] != "partner/${filename}" || post.Fields["Content-Type"] != "application/pdf" {
+		t.Errorf("Unexpected form fields: %v", post.Fields)
+	}
+	if post.Fields["X-Amz-Signature"] == "" {
+		t.Errorf("Expected a signature field, got %v", post.Fields)
+	}
+
+	policy, err := base64.StdEncoding.DecodeString(post.Fields["policy"])
+	if err != nil {
+		t.Fatalf("Policy is not base64: %v", err)
+	}
+	for _, condition := range []string{
+		`["starts-with","$key","partner/"]`,
+		`["content-length-range",0,1024]`,
+		`{"Content-Type":"application/pdf"}`,
+	} {
+		if !strings.Contains(string(policy), condition) {
+			t.Errorf("Expected policy condition %s, got %s", condition, policy)
+		}
+	}
+}
+
+func TestTagSet(t *testing.T) {
+	set := tagSet(map[string]string{"team": "data", "env": "prod", "cost-center": "42"})
+
+	var keys []string
+	for _, tag := range set {
+		keys = append(keys, aws.ToString(tag.Key))
+	}
+	if want := []string{"cost-center", "env", "team"}; !slices.Equal(keys, want) {
+		t.Errorf("tagSet() keys = %v, want %v", keys, want)
+	}
+	if got := encodeTags(map[string]string{"team": "data", "env": "prod env"}); got != "env=prod+env&team=data" {
+		t.Errorf("encodeTags() = %q", got)
+	}
+}
+
+func TestGetBucketInfo(t *testing.T) {
+	// Each section is a bucket subresource, e.g. ?versioning
+	transport := &fakeS3{routes: []fakeRoute{
+		{query: "location", response: reply(http.StatusOK, `<LocationConstraint>EU</LocationConstraint>`)},
+		{query: "versioning", response: reply(http.StatusOK, `<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`)},
+		{query: "encryption", response: replyError(http.StatusNotFound, "ServerSideEncryptionConfigurationNotFoundError")},
+		{query: "publicAccessBlock", response: replyError(http.StatusForbidden, "AccessDenied")},
+		{query: "ownershipControls", response: reply(http.StatusOK, `<OwnershipControls><Rule><ObjectOwnership>BucketOwnerEnforced</ObjectOwnership></Rule></OwnershipControls>`)},
+		{query: "object-lock", response: reply(http.StatusOK, `<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled><Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>30</Days></DefaultRetention></Rule></ObjectLockConfiguration>`)},
+		{query: "tagging", response: replyError(http.StatusNotFound, "NoSuchTagSet")},
+		{query: "logging", response: reply(http.StatusOK, `<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>app/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`)},
+		// requestPayment is left out, so the endpoint answers NotImplemented
+	}}
+	service := newFakeTransportService(t, transport)
+
+	info, err := service.GetBucketInfo(context.Background(), "test-bucket")
+	if err != nil {
+		t.Fatalf("GetBucketInfo() error = %v", err)
+	}
+
+	statuses := map[string]BucketInfoSection{
+		SectionOK:            info.Region,
+		SectionNotConfigured: info.Encryption,
+		SectionNotPermitted:  info.PublicAccessBlock,
+		SectionNotSupported:  info.RequestPayment,
+	}
+	for want, section := range statuses {
+		if section.Status != want {
+			t.Errorf("Expected status %q, got %+v", want, section)
+		}
+	}
+	if info.Region.Value != "eu-west-1" {
+		t.Errorf("Expected legacy EU location to map to eu-west-1, got %v", info.Region.Value)
+	}
+	if v, ok := info.Versioning.Value.(*BucketVersioning); !ok || v.Status != VersioningEnabled {
+		t.Errorf("Expected versioning enabled, got %+v", info.Versioning)
+	}
+	if info.OwnershipControls.Value != "BucketOwnerEnforced" {
+		t.Errorf("Expected ownership controls, got %+v", info.OwnershipControls)
+	}
+	if lock, ok := info.ObjectLock.Value.(*BucketObjectLock); !ok || !lock.Enabled || lock.Mode != "GOVERNANCE" || lock.Days != 30 {
+		t.Errorf("Expected object lock with default retention, got %+v", info.ObjectLock)
+	}
+	if info.Tagging.Status != SectionNotConfigured {
+		t.Errorf("Expected no bucket tags, got %+v", info.Tagging)
+	}
+	if logging, ok := info.Logging.Value.(*BucketLogging); !ok || logging.TargetBucket != "logs" {
+		t.Errorf("Expected logging target, got %+v", info.Logging)
+	}
+}
+
+func TestPrettyPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		expected string
+	}{
+		{
+			name:     "compact document is indented",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow"}]}`,
+			expected: "{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": [\n    {\n      \"Effect\": \"Allow\"\n    }\n  ]\n}",
+		},
+		{
+			name:     "invalid document is returned unchanged",
+			policy:   `{"Version":`,
+			expected: `{"Version":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prettyPolicy(tt.policy); got != tt.expected {
+				t.Errorf("prettyPolicy() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLifecycleDueDate(t *testing.T) {
+	fixed := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		lastModified time.Time
+		days         int32
+		date         *time.Time
+		expected     time.Time
+	}{
+		{
+			name:         "rounded up to the next midnight",
+			lastModified: time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC),
+			days:         30,
+			expected:     time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "exact midnight is kept",
+			lastModified: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+			days:         1,
+			expected:     time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "other time zones are converted to UTC first",
+			lastModified: time.Date(2024, 3, 10, 23, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+			days:         1,
+			expected:     time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "fixed date wins",
+			lastModified: time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC),
+			date:         &fixed,
+			expected:     fixed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lifecycleDueDate(tt.lastModified, tt.days, tt.date); !got.Equal(tt.expected) {
+				t.Errorf("lifecycleDueDate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLifecycleRuleConversion(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        LifecycleRule
+		checkFilter func(t *testing.T, filter *types.LifecycleRuleFilter)
+	}{
+		{
+			name: "whole bucket uses an empty prefix",
+			rule: LifecycleRule{ID: "all", Enabled: true, AbortIncompleteMultipartUploadDays: 7},
+			checkFilter: func(t *testing.T, filter *types.LifecycleRuleFilter) {
+				if filter.Prefix == nil || *filter.Prefix != "" || filter.And != nil {
+					t.Errorf("Expected empty prefix filter, got %+v", filter)
+				}
+			},
+		},
+		{
+			name: "single tag is set directly",
+			rule: LifecycleRule{ID: "tmp", Tags: map[string]string{"temp": "true"}, Expiration: &LifecycleExpiration{Days: 1}},
+			checkFilter: func(t *testing.T, filter *types.LifecycleRuleFilter) {
+				if filter.Tag == nil || aws.ToString(filter.Tag.Key) != "temp" || filter.And != nil {
+					t.Errorf("Expected tag filter, got %+v", filter)
+				}
+			},
+		},
+		{
+			name: "several conditions use the And operator",
+			rule: LifecycleRule{
+				ID:                    "logs",
+				Enabled:               true,
+				Prefix:                "logs/",
+				Tags:                  map[string]string{"team": "platform"},
+				ObjectSizeGreaterThan: 1024,
+				Transitions:           []LifecycleTransition{{Days: 30, StorageClass: "STANDARD_IA"}},
+				Expiration:            &LifecycleExpiration{Days: 365},
+				NoncurrentVersionExpiration: &NoncurrentVersionExpiration{
+					NoncurrentDays:          30,
+					NewerNoncurrentVersions: 2,
+				},
+				NoncurrentVersionTransitions: []NoncurrentVersionTransition{{NoncurrentDays: 7, StorageClass: "GLACIER"}},
+			},
+			checkFilter: func(t *testing.T, filter *types.LifecycleRuleFilter) {
+				and := filter.And
+				if and == nil || aws.ToString(and.Prefix) != "logs/" || len(and.Tags) != 1 || aws.ToInt64(and.ObjectSizeGreaterThan) != 1024 {
+					t.Errorf("Expected And filter, got %+v", filter)
+				}
+				if filter.Prefix != nil || filter.Tag != nil {
+					t.Errorf("Expected only the And operator to be set, got %+v", filter)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			sdkRule := toSDKLifecycleRule(tt.rule)
+			roundTrip := fromSDKLifecycleRule(sdkRule)
+
+			// Assert
+			tt.checkFilter(t, sdkRule.Filter)
+			if !reflect.DeepEqual(roundTrip, tt.rule) {
+				t.Errorf("Round trip changed the rule:\n got %+v\nwant %+v", roundTrip, tt.rule)
+			}
+		})
+	}
+}
+
+func TestLifecycleRuleMatching(t *testing.T) {
+	rule := LifecycleRule{
+		Prefix:                "logs/",
+		Tags:                  map[string]string{"team": "platform"},
+		ObjectSizeGreaterThan: 100,
+		ObjectSizeLessThan:    1000,
+	}
+	tests := []struct {
+		name     string
+		key      string
+		size     int64
+		tags     map[string]string
+		expected bool
+	}{
+		{name: "all conditions met", key: "logs/a.log", size: 500, tags: map[string]string{"team": "platform", "env": "prod"}, expected: true},
+		{name: "outside prefix", key: "data/a.log", size: 500, tags: map[string]string{"team": "platform"}},
+		{name: "size bounds are exclusive", key: "logs/a.log", size: 100, tags: map[string]string{"team": "platform"}},
+		{name: "too large", key: "logs/a.log", size: 1000, tags: map[string]string{"team": "platform"}},
+		{name: "tag value differs", key: "logs/a.log", size: 500, tags: map[string]string{"team": "data"}},
+		{name: "tag missing", key: "logs/a.log", size: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lifecycleObjectMatches(rule, tt.key, tt.size) && lifecycleTagsMatch(rule.Tags, tt.tags)
+			if got != tt.expected {
+				t.Errorf("Expected match=%v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestServerSideEncryptionFields(t *testing.T) {
+	rawKey := []byte("0123456789abcdef0123456789abcdef")
+	customerKey := base64.StdEncoding.EncodeToString(rawKey)
+
+	tests := []struct {
+		name         string
+		sse          *ServerSideEncryption
+		expectedMode types.ServerSideEncryption
+		expectedErr  s3cerrors.ErrorCode
+	}{
+		{name: "bucket default"},
+		{name: "SSE-S3", sse: &ServerSideEncryption{Mode: SSEModeS3}, expectedMode: types.ServerSideEncryptionAes256},
+		{name: "SSE-KMS", sse: &ServerSideEncryption{Mode: SSEModeKMS, KMSKeyID: "alias/audit"}, expectedMode: types.ServerSideEncryptionAwsKms},
+		{name: "SSE-C", sse: &ServerSideEncryption{Mode: SSEModeC, CustomerKey: customerKey}},
+		{name: "SSE-C with short key", sse: &ServerSideEncryption{Mode: SSEModeC, CustomerKey: "c2hvcnQ="}, expectedErr: s3cerrors.CodeInvalidFormat},
+		{name: "SSE-C without key", sse: &ServerSideEncryption{Mode: SSEModeC}, expectedErr: s3cerrors.CodeMissingField},
+		{name: "unknown mode", sse: &ServerSideEncryption{Mode: "aws:kms"}, expectedErr: s3cerrors.CodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := tt.sse.fields()
+			if tt.expectedErr != "" {
+				var s3cErr *s3cerrors.S3CError
+				if !errors.As(err, &s3cErr) || s3cErr.Code != tt.expectedErr {
+					t.Fatalf("Expected %s error, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fields() error = %v", err)
+			}
+			if fields.mode != tt.expectedMode {
+				t.Errorf("Expected mode %q, got %q", tt.expectedMode, fields.mode)
+			}
+			if hasCustomerKey := fields.customer.key != nil; hasCustomerKey != (tt.sse != nil && tt.sse.Mode == SSEModeC) {
+				t.Errorf("Expected customer key fields only for SSE-C, got %+v", fields.customer)
+			}
+		})
+	}
+}
+
+func TestUploadObjectEncryptionHeaders(t *testing.T) {
+	rawKey := []byte("0123456789abcdef0123456789abcdef")
+	customerKey := base64.StdEncoding.EncodeToString(rawKey)
+	keyMD5 := md5.Sum(rawKey)
+
+	tests := []struct {
+		name            string
+		sse             *ServerSideEncryption
+		expectedHeaders map[string]string
+	}{
+		{
+			name: "SSE-KMS with context",
+			sse:  &ServerSideEncryption{Mode: SSEModeKMS, KMSKeyID: "alias/audit", KMSContext: map[string]string{"team": "compliance"}, BucketKeyEnabled: true},
+			expectedHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption":                    "aws:kms",
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id":     "alias/audit",
+				"X-Amz-Server-Side-Encryption-Context":            base64.StdEncoding.EncodeToString([]byte(`{"team":"compliance"}`)),
+				"X-Amz-Server-Side-Encryption-Bucket-Key-Enabled": "true",
+			},
+		},
+		{
+			name: "SSE-C",
+			sse:  &ServerSideEncryption{Mode: SSEModeC, CustomerKey: customerKey},
+			expectedHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption":                    "",
+				"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+				"X-Amz-Server-Side-Encryption-Customer-Key":       customerKey,
+				"X-Amz-Server-Side-Encryption-Customer-Key-Md5":   base64.StdEncoding.EncodeToString(keyMD5[:]),
+			},
+		},
+		{
+			name: "bucket default",
+			expectedHeaders: map[string]string{
+				"X-Amz-Server-Side-Encryption":              "",
+				"X-Amz-Server-Side-Encryption-Customer-Key": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeS3{routes: []fakeRoute{okRoute}}
+			service := newFakeTransportService(t, transport)
+
+			_, err := service.UploadObject(context.Background(), UploadObjectInput{
+				Bucket:     "test-bucket",
+				Key:        "audit/report.csv",
+				Body:       strings.NewReader("a,b"),
+				Size:       3,
+				Encryption: tt.sse,
+			})
+			if err != nil {
+				t.Fatalf("UploadObject() error = %v", err)
+			}
+			if len(transport.requests) != 1 {
+				t.Fatalf("Expected a single PutObject request, got %d", len(transport.requests))
+			}
+
+			header := transport.requests[0].header
+			for name, expected := range tt.expectedHeaders {
+				if got := header.Get(name); got != expected {
+					t.Errorf("Header %s = %q, want %q", name, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestClientEncryptionStream(t *testing.T) {
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty body", size: 0},
+		{name: "single byte", size: 1},
+		{name: "exactly one chunk", size: clientChunkSize},
+		{name: "several chunks", size: 2*clientChunkSize + 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			plain := make([]byte, tt.size)
+			for i := range plain {
+				plain[i] = byte(i % 251)
+			}
+
+			// Act
+			encrypting, err := newEncryptingReader(bytes.NewReader(plain), dataKey)
+			if err != nil {
+				t.Fatalf("newEncryptingReader() error = %v", err)
+			}
+			sealed, err := io.ReadAll(encrypting)
+			if err != nil {
+				t.Fatalf("Failed to encrypt: %v", err)
+			}
+			decrypting, err := newDecryptingReader(io.NopCloser(bytes.NewReader(sealed)), dataKey)
+			if err != nil {
+				t.Fatalf("newDecryptingReader() error = %v", err)
+			}
+			opened, err := io.ReadAll(decrypting)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Failed to decrypt: %v", err)
+			}
+			if !bytes.Equal(opened, plain) {
+				t.Errorf("Decrypted body differs from the original")
+			}
+			if bytes.Contains(sealed, plain) && tt.size > 0 {
+				t.Errorf("Sealed body contains the plaintext")
+			}
+			if got := encryptedSize(int64(tt.size)); got != int64(len(sealed)) {
+				t.Errorf("encryptedSize(%d) = %d, sealed body is %d bytes", tt.size, got, len(sealed))
+			}
+			if got := decryptedSize(int64(len(sealed))); got != int64(tt.size) {
+				t.Errorf("decryptedSize(%d) = %d, want %d", len(sealed), got, tt.size)
+			}
+		})
+	}
+}
+
+func TestClientEncryptionStreamTampering(t *testing.T) {
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	plain := bytes.Repeat([]byte("s3c"), clientChunkSize)
+
+	encrypting, err := newEncryptingReader(bytes.NewReader(plain), dataKey)
+	if err != nil {
+		t.Fatalf("newEncryptingReader() error = %v", err)
+	}
+	sealed, err := io.ReadAll(encrypting)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(sealed []byte) []byte
+	}{
+		{
+			name: "flipped byte",
+			modify: func(sealed []byte) []byte {
+				sealed[10] ^= 0xff
+				return sealed
+			},
+		},
+		{
+			name: "truncated at a chunk boundary",
+			modify: func(sealed []byte) []byte {
+				return sealed[:clientChunkSize+gcmTagSize]
+			},
+		},
+		{
+			name: "chunks reordered",
+			modify: func(sealed []byte) []byte {
+				chunk := clientChunkSize + gcmTagSize
+				return slices.Concat(sealed[chunk:2*chunk], sealed[:chunk], sealed[2*chunk:])
+			},
+		},
+		{
+			name: "empty body",
+			modify: func(sealed []byte) []byte {
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			body := tt.modify(slices.Clone(sealed))
+			decrypting, err := newDecryptingReader(io.NopCloser(bytes.NewReader(body)), dataKey)
+			if err != nil {
+				t.Fatalf("newDecryptingReader() error = %v", err)
+			}
+
+			// Act
+			_, err = io.ReadAll(decrypting)
+
+			// Assert
+			var s3cErr *s3cerrors.S3CError
+			if !errors.As(err, &s3cErr) || s3cErr.Code != s3cerrors.CodeDecryptionFailed {
+				t.Errorf("Expected %s, got %v", s3cerrors.CodeDecryptionFailed, err)
+			}
+		})
+	}
+}
+
+func TestOpenClientDataKey(t *testing.T) {
+	fileKey := []byte("0123456789abcdef0123456789abcdef")
+	otherKey := []byte("fedcba9876543210fedcba9876543210")
+	withKey := &AWSS3Service{clientKey: fileKey}
+
+	_, passphraseMetadata, err := withKey.newClientDataKey(&ClientEncryption{KeySource: ClientKeySourcePassphrase, Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("newClientDataKey(passphrase) error = %v", err)
+	}
+	_, keyFileMetadata, err := withKey.newClientDataKey(&ClientEncryption{KeySource: ClientKeySourceKeyFile})
+	if err != nil {
+		t.Fatalf("newClientDataKey(key-file) error = %v", err)
+	}
+	costlyMetadata := maps.Clone(passphraseMetadata)
+	costlyMetadata[metaClientKDFIterations] = "2147483647"
+
+	tests := []struct {
+		name         string
+		service      *AWSS3Service
+		metadata     map[string]string
+		passphrase   string
+		expectedCode s3cerrors.ErrorCode
+	}{
+		{
+			name:       "matching passphrase",
+			service:    &AWSS3Service{},
+			metadata:   passphraseMetadata,
+			passphrase: "correct horse",
+		},
+		{
+			name:         "wrong passphrase",
+			service:      &AWSS3Service{},
+			metadata:     passphraseMetadata,
+			passphrase:   "battery staple",
+			expectedCode: s3cerrors.CodeDecryptionFailed,
+		},
+		{
+			name:         "no passphrase",
+			service:      &AWSS3Service{},
+			metadata:     passphraseMetadata,
+			expectedCode: s3cerrors.CodeEncryptionKeyMissing,
+		},
+		{
+			name:         "tampered iteration count",
+			service:      &AWSS3Service{},
+			metadata:     costlyMetadata,
+			passphrase:   "correct horse",
+			expectedCode: s3cerrors.CodeDecryptionFailed,
+		},
+		{
+			name:     "matching key file",
+			service:  withKey,
+			metadata: keyFileMetadata,
+		},
+		{
+			name:         "no key file configured",
+			service:      &AWSS3Service{},
+			metadata:     keyFileMetadata,
+			expectedCode: s3cerrors.CodeEncryptionKeyMissing,
+		},
+		{
+			name:         "different key file",
+			service:      &AWSS3Service{clientKey: otherKey},
+			metadata:     keyFileMetadata,
+			expectedCode: s3cerrors.CodeEncryptionKeyMissing,
+		},
+		{
+			name:         "unknown algorithm",
+			service:      withKey,
+			metadata:     map[string]string{metaClientAlgorithm: "ROT13"},
+			expectedCode: s3cerrors.CodeDecryptionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			dataKey, err := tt.service.openClientDataKey(tt.metadata, tt.passphrase)
+
+			// Assert
+			if tt.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("openClientDataKey() error = %v", err)
+				}
+				if len(dataKey) != clientKeySize {
+					t.Errorf("Expected a %d byte data key, got %d bytes", clientKeySize, len(dataKey))
+				}
+				return
+			}
+
+			var s3cErr *s3cerrors.S3CError
+			if !errors.As(err, &s3cErr) || s3cErr.Code != tt.expectedCode {
+				t.Errorf("Expected %s, got %v", tt.expectedCode, err)
+			}
+		})
+	}
+}
+
+func TestWithClientEncryptionMetadata(t *testing.T) {
+	current := map[string]string{
+		"owner":              "alice",
+		metaClientAlgorithm:  clientEncryptionAlgorithm,
+		metaClientKeySource:  ClientKeySourceKeyFile,
+		metaClientWrappedKey: "d3JhcHBlZA==",
+		metaClientKeyID:      "0011223344556677",
+		metaClientChunkSize:  "65536",
+	}
+
+	// Act
+	merged := withClientEncryptionMetadata(map[string]string{"team": "audit"}, current)
+
+	// Assert
+	expected := map[string]string{
+		"team":               "audit",
+		metaClientAlgorithm:  clientEncryptionAlgorithm,
+		metaClientKeySource:  ClientKeySourceKeyFile,
+		metaClientWrappedKey: "d3JhcHBlZA==",
+		metaClientKeyID:      "0011223344556677",
+		metaClientChunkSize:  "65536",
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("withClientEncryptionMetadata() = %v, want %v", merged, expected)
+	}
+}
+
+func TestClientEncryptionUploadDownload(t *testing.T) {
+	// Arrange
+	// PutObject bodies and user metadata are kept and served back on GetObject
+	objects := map[string]fakeRequest{}
+	transport := &fakeS3{routes: []fakeRoute{
+		{method: http.MethodPut, respond: func(req fakeRequest) fakeResponse {
+			objects[req.key] = req
+			return okRoute.response
+		}},
+		{respond: func(req fakeRequest) fakeResponse {
+			stored := objects[req.key]
+			header := http.Header{"Etag": {`"etag"`}, "Content-Length": {strconv.Itoa(len(stored.body))}}
+			for name, values := range stored.header {
+				if strings.HasPrefix(name, "X-Amz-Meta-") {
+					header[name] = values
+				}
+			}
+			return fakeResponse{status: http.StatusOK, header: header, body: stored.body}
+		}},
+	}}
+	service := newFakeTransportService(t, transport)
+	plain := strings.Repeat("confidential,", 10000)
+
+	// Act
+	_, err := service.UploadObject(context.Background(), UploadObjectInput{
+		Bucket:           "test-bucket",
+		Key:              "secret.csv",
+		Body:             strings.NewReader(plain),
+		Size:             int64(len(plain)),
+		Metadata:         map[string]string{"original-filename": "secret.csv"},
+		ClientEncryption: &ClientEncryption{KeySource: ClientKeySourcePassphrase, Passphrase: "correct horse"},
+	})
+	if err != nil {
+		t.Fatalf("UploadObject() error = %v", err)
+	}
+
+	// Assert
+	stored := objects["secret.csv"].body
+	if int64(len(stored)) != encryptedSize(int64(len(plain))) {
+		t.Fatalf("Expected %d stored bytes, got %d", encryptedSize(int64(len(plain))), len(stored))
+	}
+	if strings.Contains(stored, "confidential") {
+		t.Fatalf("Stored body contains the plaintext")
+	}
+
+	_, err = service.DownloadObject(context.Background(), DownloadObjectInput{Bucket: "test-bucket", Key: "secret.csv"})
+	var s3cErr *s3cerrors.S3CError
+	if !errors.As(err, &s3cErr) || s3cErr.Code != s3cerrors.CodeEncryptionKeyMissing {
+		t.Fatalf("Expected %s without a passphrase, got %v", s3cerrors.CodeEncryptionKeyMissing, err)
+	}
+
+	output, err := service.DownloadObject(context.Background(), DownloadObjectInput{Bucket: "test-bucket", Key: "secret.csv", ClientPassphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("DownloadObject() error = %v", err)
+	}
+	defer output.Body.Close()
+	opened, err := io.ReadAll(output.Body)
+	if err != nil {
+		t.Fatalf("Failed to read decrypted body: %v", err)
+	}
+	if string(opened) != plain {
+		t.Errorf("Downloaded body differs from the uploaded one")
+	}
+	if output.ContentLength != int64(len(plain)) {
+		t.Errorf("Expected content length %d, got %d", len(plain), output.ContentLength)
+	}
+	if output.Metadata["original-filename"] != "secret.csv" {
+		t.Errorf("Expected user metadata to be kept, got %v", output.Metadata)
+	}
+}
+
+// storageClassRoutes serve objects with fixed storage classes (empty for STANDARD) and
+// accept copies. Copies of GLACIER objects fail the way S3 refuses to copy archived objects.
+func storageClassRoutes(classes map[string]string) []fakeRoute {
+	return []fakeRoute{
+		{method: http.MethodGet, query: "list-type", respond: func(fakeRequest) fakeResponse {
+			var listing strings.Builder
+			listing.WriteString("<ListBucketResult>")
+			for _, key := range slices.Sorted(maps.Keys(classes)) {
+				size := "3"
+				if strings.HasSuffix(key, "/") {
+					size = "0" // folder marker
+				}
+				listing.WriteString("<Contents><Key>" + key + "</Key><Size>" + size + "</Size>")
+				if classes[key] != "" {
+					listing.WriteString("<StorageClass>" + classes[key] + "</StorageClass>")
+				}
+				listing.WriteString("</Contents>")
+			}
+			listing.WriteString("</ListBucketResult>")
+			return reply(http.StatusOK, listing.String())
+		}},
+		{method: http.MethodHead, respond: func(req fakeRequest) fakeResponse {
+			class, ok := classes[req.key]
+			if !ok {
+				return reply(http.StatusNotFound, "")
+			}
+			header := http.Header{"Etag": {`"etag"`}, "Content-Length": {"3"}}
+			if class != "" {
+				header.Set("X-Amz-Storage-Class", class)
+			}
+			return fakeResponse{status: http.StatusOK, header: header}
+		}},
+		{method: http.MethodPut, respond: func(req fakeRequest) fakeResponse {
+			if classes[req.key] == "GLACIER" {
+				return replyError(http.StatusForbidden, "InvalidObjectState")
+			}
+			return reply(http.StatusOK, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+		}},
+	}
+}
+
+// copiedClasses returns the storage class requested by each successful copy, by destination key
+func copiedClasses(transport *fakeS3, classes map[string]string) map[string]string {
+	copies := map[string]string{}
+	for _, req := range transport.sent(http.MethodPut, "") {
+		if req.header.Get("X-Amz-Copy-Source") != "" && classes[req.key] != "GLACIER" {
+			copies[req.key] = req.header.Get("X-Amz-Storage-Class")
+		}
+	}
+	return copies
+}
+
+func TestListObjectsStorageClass(t *testing.T) {
+	// Arrange
+	transport := &fakeS3{routes: storageClassRoutes(map[string]string{
+		"logs/":         "",
+		"logs/new.log":  "",
+		"logs/old.log":  "GLACIER",
+		"logs/warm.log": "STANDARD_IA",
+	})}
+	service := newFakeTransportService(t, transport)
+
+	// Act
+	output, err := service.ListObjects(context.Background(), ListObjectsInput{Bucket: "test-bucket", Recursive: true})
+	if err != nil {
+		t.Fatalf("ListObjects() error = %v", err)
+	}
+
+	// Assert
+	expected := map[string]string{
+		"logs/new.log":  "STANDARD",
+		"logs/old.log":  "GLACIER",
+		"logs/warm.log": "STANDARD_IA",
+	}
+	for _, obj := range output.Objects {
+		if obj.IsFolder {
+			if obj.StorageClass != "" {
+				t.Errorf("Expected no storage class for folder %s, got %q", obj.Key, obj.StorageClass)
+			}
+			continue
+		}
+		if obj.StorageClass != expected[obj.Key] {
+			t.Errorf("Expected %s to be %s, got %q", obj.Key, expected[obj.Key], obj.StorageClass)
+		}
+	}
+}
+
+func TestListObjectVersionsStorageClass(t *testing.T) {
+	// Arrange
+	transport := &fakeS3{routes: []fakeRoute{
+		{method: http.MethodGet, query: "versions", response: reply(http.StatusOK, "<ListVersionsResult>"+
+			"<Version><Key>logs/app.log</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest><Size>3</Size></Version>"+
+			"<Version><Key>logs/app.log</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><Size>3</Size><StorageClass>GLACIER</StorageClass></Version>"+
+			"</ListVersionsResult>")},
+	}}
+	service := newFakeTransportService(t, transport)
+
+	// Act
+	output, err := service.ListObjectVersions(context.Background(), ListObjectVersionsInput{Bucket: "test-bucket"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("ListObjectVersions() error = %v", err)
+	}
+	classes := map[string]string{}
+	for _, version := range output.Versions {
+		classes[version.VersionID] = version.StorageClass
+	}
+	if classes["v2"] != "STANDARD" || classes["v1"] != "GLACIER" {
+		t.Errorf("Expected STANDARD for the unlabelled version and GLACIER for the old one, got %v", classes)
+	}
+}
+
+func TestUploadObjectStorageClass(t *testing.T) {
+	// Arrange
+	transport := &fakeS3{routes: []fakeRoute{okRoute}}
+	service := newFakeTransportService(t, transport)
+
+	// Act
+	_, err := service.UploadObject(context.Background(), UploadObjectInput{
+		Bucket:       "test-bucket",
+		Key:          "archive/2020.tar",
+		Body:         strings.NewReader("tar"),
+		Size:         3,
+		StorageClass: "DEEP_ARCHIVE",
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("UploadObject() error = %v", err)
+	}
+	if got := transport.requests[0].header.Get("X-Amz-Storage-Class"); got != "DEEP_ARCHIVE" {
+		t.Errorf("Expected storage class header DEEP_ARCHIVE, got %q", got)
+	}
+}
+
+func TestCopyObjectKeepsStorageClass(t *testing.T) {
+	// Arrange
+	classes := map[string]string{"logs/warm.log": "STANDARD_IA"}
+	transport := &fakeS3{routes: storageClassRoutes(classes)}
+	service := newFakeTransportService(t, transport)
+
+	// Act
+	_, err := service.CopyObject(context.Background(), CopyObjectInput{
+		SourceBucket:      "test-bucket",
+		SourceKey:         "logs/warm.log",
+		DestinationBucket: "test-bucket",
+		DestinationKey:    "backup/warm.log",
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("CopyObject() error = %v", err)
+	}
+	if got := copiedClasses(transport, classes)["backup/warm.log"]; got != "STANDARD_IA" {
+		t.Errorf("Expected copy to stay in STANDARD_IA, got %q", got)
+	}
+}
+
+func TestCopyObjectRequiresInspectedSource(t *testing.T) {
+	// Arrange
+	transport := &fakeS3{routes: []fakeRoute{okRoute}}
+	service := newFakeTransportService(t, transport)
+
+	// Act
+	_, err := service.CopyObject(context.Background(), CopyObjectInput{
+		SourceBucket:      "test-bucket",
+		SourceKey:         "report.csv",
+		DestinationBucket: "test-bucket",
+		DestinationKey:    "archive/report.csv",
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("CopyObject() error = %v", err)
+	}
+	if len(transport.requests) != 2 {
+		t.Fatalf("Expected HeadObject and CopyObject requests, got %d", len(transport.requests))
+	}
+	if got := transport.requests[1].header.Get("X-Amz-Copy-Source-If-Match"); got != `"etag"` {
+		t.Errorf("Expected copy to require the inspected ETag, got %q", got)
+	}
+}
+
+func TestChangeStorageClass(t *testing.T) {
+	tests := []struct {
+		name              string
+		input             ChangeStorageClassInput
+		expectedChanged   []string
+		expectedUnchanged []string
+		expectedFailed    []string
+	}{
+		{
+			name:              "selected keys",
+			input:             ChangeStorageClassInput{Keys: []string{"logs/new.log", "logs/warm.log", "logs/old.log", "logs/missing.log"}, StorageClass: "STANDARD_IA"},
+			expectedChanged:   []string{"logs/new.log"},
+			expectedUnchanged: []string{"logs/warm.log"},
+			expectedFailed:    []string{"logs/old.log", "logs/missing.log"},
+		},
+		{
+			name:              "prefix skips folder markers",
+			input:             ChangeStorageClassInput{Prefix: "logs", StorageClass: "STANDARD"},
+			expectedChanged:   []string{"logs/warm.log"},
+			expectedUnchanged: []string{"logs/new.log"},
+			expectedFailed:    []string{"logs/old.log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			classes := map[string]string{
+				"logs/":         "",
+				"logs/new.log":  "",
+				"logs/old.log":  "GLACIER",
+				"logs/warm.log": "STANDARD_IA",
+			}
+			transport := &fakeS3{routes: storageClassRoutes(classes)}
+			service := newFakeTransportService(t, transport)
+			tt.input.Bucket = "test-bucket"
+
+			// Act
+			output, err := service.ChangeStorageClass(context.Background(), tt.input)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("ChangeStorageClass() error = %v", err)
+			}
+			if !reflect.DeepEqual(output.Changed, tt.expectedChanged) {
+				t.Errorf("Expected changed %v, got %v", tt.expectedChanged, output.Changed)
+			}
+			if !reflect.DeepEqual(output.Unchanged, tt.expectedUnchanged) {
+				t.Errorf("Expected unchanged %v, got %v", tt.expectedUnchanged, output.Unchanged)
+			}
+			var failed []string
+			for _, failure := range output.Failed {
+				failed = append(failed, failure.Key)
+			}
+			if !reflect.DeepEqual(failed, tt.expectedFailed) {
+				t.Errorf("Expected failed %v, got %v", tt.expectedFailed, output.Failed)
+			}
+			copies := copiedClasses(transport, classes)
+			for _, key := range tt.expectedChanged {
+				if copies[key] != tt.input.StorageClass {
+					t.Errorf("Expected %s to be copied as %s, got %q", key, tt.input.StorageClass, copies[key])
+				}
+			}
+			if len(copies) != len(tt.expectedChanged) {
+				t.Errorf("Expected only changed keys to be copied, got %v", copies)
+			}
+		})
+	}
+}
+
+func TestParseRestoreHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected *RestoreStatus
+	}{
+		{
+			name: "never restored",
+		},
+		{
+			name:     "restore running",
+			header:   `ongoing-request="true"`,
+			expected: &RestoreStatus{State: RestoreStateRestoring},
+		},
+		{
+			name:     "restored copy with expiry",
+			header:   `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`,
+			expected: &RestoreStatus{State: RestoreStateRestored, ExpiryDate: "2012-12-21T00:00:00Z"},
+		},
+		{
+			name:     "restored copy with unreadable expiry",
+			header:   `ongoing-request="false", expiry-date="soon"`,
+			expected: &RestoreStatus{State: RestoreStateRestored},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			status := parseRestoreHeader(tt.header)
+
+			// Assert
+			if !reflect.DeepEqual(status, tt.expected) {
+				t.Errorf("parseRestoreHeader(%q) = %+v, want %+v", tt.header, status, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRestoreObject(t *testing.T) {
+	// Arrange
+	responses := map[string]fakeResponse{
+		"archive/cold.tar":     reply(http.StatusAccepted, ""),
+		"archive/restored.tar": reply(http.StatusOK, ""),
+		"archive/running.tar":  replyError(http.StatusConflict, "RestoreAlreadyInProgress"),
+		"archive/hot.tar":      replyError(http.StatusForbidden, "InvalidObjectState"),
+		"archive/denied.tar":   replyError(http.StatusForbidden, "AccessDenied"),
+	}
+	transport := &fakeS3{routes: []fakeRoute{
+		{method: http.MethodGet, query: "list-type", respond: func(fakeRequest) fakeResponse {
+			var listing strings.Builder
+			listing.WriteString("<ListBucketResult>")
+			for _, key := range slices.Sorted(maps.Keys(responses)) {
+				listing.WriteString("<Contents><Key>" + key + "</Key><Size>1</Size></Contents>")
+			}
+			listing.WriteString("<Contents><Key>archive/</Key><Size>0</Size></Contents></ListBucketResult>")
+			return reply(http.StatusOK, listing.String())
+		}},
+		{method: http.MethodPost, query: "restore", respond: func(req fakeRequest) fakeResponse {
+			return responses[req.key]
+		}},
+	}}
+	service := newFakeTransportService(t, transport)
+
+	// Act
+	output, err := service.RestoreObject(context.Background(), RestoreObjectInput{Bucket: "test-bucket", Prefix: "archive", Days: 7, Tier: RestoreTierBulk})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RestoreObject() error = %v", err)
+	}
+	if !reflect.DeepEqual(output.Requested, []string{"archive/cold.tar", "archive/restored.tar"}) {
+		t.Errorf("Expected cold and restored objects to be requested, got %v", output.Requested)
+	}
+	if !reflect.DeepEqual(output.InProgress, []string{"archive/running.tar"}) {
+		t.Errorf("Expected running.tar in progress, got %v", output.InProgress)
+	}
+	if !reflect.DeepEqual(output.Skipped, []string{"archive/hot.tar"}) {
+		t.Errorf("Expected hot.tar to be skipped, got %v", output.Skipped)
+	}
+	if len(output.Failed) != 1 || output.Failed[0].Key != "archive/denied.tar" || output.Failed[0].Code != string(s3cerrors.CodeS3AccessDenied) {
+		t.Errorf("Expected denied.tar to fail with access denied, got %+v", output.Failed)
+	}
+	requests := map[string]string{}
+	for _, req := range transport.sent(http.MethodPost, "restore") {
+		requests[req.key] = req.body
+	}
+	if _, ok := requests["archive/"]; ok {
+		t.Error("Expected the folder marker to be skipped")
+	}
+	body := requests["archive/cold.tar"]
+	if !strings.Contains(body, "<Days>7</Days>") || !strings.Contains(body, "<Tier>Bulk</Tier>") {
+		t.Errorf("Expected days and tier in the restore request, got %s", body)
+	}
+
+	_, err = service.RestoreObject(context.Background(), RestoreObjectInput{Bucket: "test-bucket", Key: "archive/denied.tar", Days: 7})
+	var s3cErr *s3cerrors.S3CError
+	if !errors.As(err, &s3cErr) || s3cErr.Code != s3cerrors.CodeS3AccessDenied {
+		t.Errorf("Expected a single key failure to be returned, got %v", err)
+	}
+}
+
+// objectLockRoutes answer object lock, retention, legal hold and batch delete requests
+var objectLockRoutes = []fakeRoute{
+	{method: http.MethodPost, query: "delete", response: reply(http.StatusOK, "<DeleteResult>"+
+		"<Deleted><Key>old.csv</Key><VersionId>v1</VersionId></Deleted>"+
+		"<Error><Key>ledger.csv</Key><VersionId>v2</VersionId><Code>AccessDenied</Code><Message>Access Denied because object protected by object lock.</Message></Error>"+
+		"<Error><Key>private.csv</Key><VersionId>v3</VersionId><Code>AccessDenied</Code><Message>Access Denied</Message></Error>"+
+		"</DeleteResult>")},
+	{method: http.MethodGet, query: "retention", response: reply(http.StatusOK, "<Retention><Mode>GOVERNANCE</Mode><RetainUntilDate>2030-01-01T00:00:00Z</RetainUntilDate></Retention>")},
+	{method: http.MethodGet, query: "legal-hold", response: reply(http.StatusNotFound, "<Error><Code>NoSuchObjectLockConfiguration</Code><Message>The specified object does not have a ObjectLock configuration</Message></Error>")},
+	{method: http.MethodGet, query: "object-lock", response: reply(http.StatusOK, "<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled>"+
+		"<Rule><DefaultRetention><Mode>COMPLIANCE</Mode><Years>7</Years></DefaultRetention></Rule></ObjectLockConfiguration>")},
+	{response: reply(http.StatusOK, "")},
+}
+
+func TestObjectRetentionAndLegalHold(t *testing.T) {
+	// Arrange
+	transport := &fakeS3{routes: objectLockRoutes}
+	service := newFakeTransportService(t, transport)
+	target := ObjectLockInput{Bucket: "test-bucket", Key: "ledger.csv"}
+
+	// Act
+	retention, err := service.GetObjectRetention(context.Background(), target)
+	if err != nil {
+		t.Fatalf("GetObjectRetention() error = %v", err)
+	}
+	hold, err := service.GetObjectLegalHold(context.Background(), target)
+	if err != nil {
+		t.Fatalf("GetObjectLegalHold() error = %v", err)
+	}
+	err = service.PutObjectRetention(context.Background(), PutObjectRetentionInput{
+		Bucket:          "test-bucket",
+		Key:             "ledger.csv",
+		Mode:            RetentionModeGovernance,
+		RetainUntilDate: time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("PutObjectRetention() error = %v", err)
+	}
+	withoutBypass := transport.sent(http.MethodPut, "retention")[0].header.Get("X-Amz-Bypass-Governance-Retention")
+	err = service.PutObjectRetention(context.Background(), PutObjectRetentionInput{
+		Bucket:                    "test-bucket",
+		Key:                       "ledger.csv",
+		BypassGovernanceRetention: true,
+	})
+	if err != nil {
+		t.Fatalf("PutObjectRetention() with bypass error = %v", err)
+	}
+
+	// Assert
+	if retention.Mode != RetentionModeGovernance || retention.RetainUntilDate != "2030-01-01T00:00:00Z" {
+		t.Errorf("Unexpected retention: %+v", retention)
+	}
+	if retention.BucketDefault == nil || retention.BucketDefault.Mode != RetentionModeCompliance || retention.BucketDefault.Years != 7 {
+		t.Errorf("Expected the bucket default retention, got %+v", retention.BucketDefault)
+	}
+	if hold.Status != LegalHoldOff {
+		t.Errorf("Expected a version without legal hold to report OFF, got %s", hold.Status)
+	}
+	if withoutBypass != "" {
+		t.Errorf("Expected no governance bypass unless requested, got %q", withoutBypass)
+	}
+	withBypass := transport.sent(http.MethodPut, "retention")[1]
+	if got := withBypass.header.Get("X-Amz-Bypass-Governance-Retention"); got != "true" {
+		t.Errorf("Expected the governance bypass header when requested, got %q", got)
+	}
+	if strings.Contains(withBypass.body, "<Mode>") {
+		t.Errorf("Expected an empty retention to remove it, got %s", withBypass.body)
+	}
+}
+
+func TestDeleteObjectIdentifiers_ObjectLock(t *testing.T) {
+	// Arrange
+	transport := &fakeS3{routes: objectLockRoutes}
+	service := newFakeTransportService(t, transport)
+	objects := []types.ObjectIdentifier{
+		{Key: aws.String("old.csv"), VersionId: aws.String("v1")},
+		{Key: aws.String("ledger.csv"), VersionId: aws.String("v2")},
+		{Key: aws.String("private.csv"), VersionId: aws.String("v3")},
+	}
+
+	// Act
+	deleted, failed, err := service.deleteObjectIdentifiers(context.Background(), "test-bucket", objects, false)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("deleteObjectIdentifiers() error = %v", err)
+	}
+	if !reflect.DeepEqual(deleted, []string{"old.csv"}) {
+		t.Errorf("Expected old.csv to be deleted, got %v", deleted)
+	}
+	if len(failed) != 2 || failed[0].Code != string(s3cerrors.CodeS3ObjectLocked) || failed[1].Code != "AccessDenied" {
+		t.Errorf("Expected locked and denied versions to be told apart, got %+v", failed)
+	}
+	if got := transport.sent(http.MethodPost, "delete")[0].header.Get("X-Amz-Bypass-Governance-Retention"); got != "" {
+		t.Errorf("Expected no governance bypass unless requested, got %q", got)
+	}
+
+	var s3cErr *s3cerrors.S3CError
+	if err := bucketNotEmptyError("test-bucket", failed); !errors.As(err, &s3cErr) || s3cErr.Code != s3cerrors.CodeS3ObjectLocked {
+		t.Errorf("Expected locked versions to stop emptying with the locked error, got %v", err)
+	}
+
+	if _, _, err := service.deleteObjectIdentifiers(context.Background(), "test-bucket", objects, true); err != nil {
+		t.Fatalf("deleteObjectIdentifiers() with bypass error = %v", err)
+	}
+	if got := transport.sent(http.MethodPost, "delete")[1].header.Get("X-Amz-Bypass-Governance-Retention"); got != "true" {
+		t.Errorf("Expected the governance bypass header when requested, got %q", got)
+	}
+}
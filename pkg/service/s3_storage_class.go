@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// ChangeStorageClassInput represents input for moving objects to another storage class
+type ChangeStorageClassInput struct {
+	Bucket       string   `json:"bucket"`
+	Keys         []string `json:"keys,omitempty"`   // selected objects
+	Prefix       string   `json:"prefix,omitempty"` // every object under the prefix, when Keys is empty
+	StorageClass string   `json:"storageClass"`
+}
+
+// ChangeStorageClassOutput summarises a storage class change key by key
+type ChangeStorageClassOutput struct {
+	Changed   []string      `json:"changed"`
+	Unchanged []string      `json:"unchanged,omitempty"` // already in the requested class
+	Failed    []ObjectError `json:"failed,omitempty"`
+}
+
+// S3StorageClassChanger interface for moving objects between storage classes
+type S3StorageClassChanger interface {
+	ChangeStorageClass(ctx context.Context, input ChangeStorageClassInput) (*ChangeStorageClassOutput, error)
+}
+
+// errStorageClassUnchanged marks keys that were already in the requested class
+var errStorageClassUnchanged = errors.New("object is already in the requested storage class")
+
+// listedStorageClass returns the storage class of a listed or inspected object.
+// HeadObject omits STANDARD, and so do some S3 compatible services in listings.
+func listedStorageClass(class string) string {
+	if class == "" {
+		return string(types.StorageClassStandard)
+	}
+	return class
+}
+
+// ChangeStorageClass copies objects onto themselves with a new storage class, keeping
+// their headers, metadata and tags. Failures are reported per key.
+func (s *AWSS3Service) ChangeStorageClass(ctx context.Context, input ChangeStorageClassInput) (*ChangeStorageClassOutput, error) {
+	if input.StorageClass == "" {
+		return nil, s3cerrors.NewMissingFieldError("storageClass")
+	}
+
+	output := &ChangeStorageClassOutput{Changed: []string{}}
+	changeKeys := func(keys []string) {
+		errs := forEachKey(ctx, keys, func(_ int, key string) error {
+			return s.changeObjectStorageClass(ctx, input.Bucket, key, input.StorageClass)
+		})
+
+		for i, key := range keys {
+			switch {
+			case errors.Is(errs[i], errStorageClassUnchanged):
+				output.Unchanged = append(output.Unchanged, key)
+			case errs[i] != nil:
+				output.Failed = append(output.Failed, newObjectError(key, errs[i]))
+			default:
+				output.Changed = append(output.Changed, key)
+			}
+		}
+	}
+
+	prefix := normalizePrefix(input.Prefix)
+	switch {
+	case len(input.Keys) > 0:
+		changeKeys(input.Keys)
+	case prefix != "":
+		err := s.walkPrefix(ctx, input.Bucket, prefix, func(keys []string) error {
+			// Folder markers carry no content, so they stay where they are
+			changeKeys(slices.DeleteFunc(keys, func(key string) bool { return strings.HasSuffix(key, "/") }))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, s3cerrors.NewValidationError(s3cerrors.CodeMissingField, "Either keys or prefix is required")
+	}
+
+	s.logger.Info("Finished storage class change",
+		"bucket", input.Bucket,
+		"prefix", prefix,
+		"storageClass", input.StorageClass,
+		"changed", len(output.Changed),
+		"unchanged", len(output.Unchanged),
+		"failed", len(output.Failed),
+	)
+	return output, nil
+}
+
+// changeObjectStorageClass rewrites one object in a new storage class, skipping
+// objects that are already there so they are not copied for nothing
+func (s *AWSS3Service) changeObjectStorageClass(ctx context.Context, bucket, key, storageClass string) error {
+	current, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return convertS3Error("change storage class", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket": bucket,
+				"key":    key,
+			})
+	}
+	if listedStorageClass(string(current.StorageClass)) == storageClass {
+		return errStorageClassUnchanged
+	}
+
+	return s.rewriteObject(ctx, bucket, key, current, MetadataUpdate{StorageClass: storageClass})
+}
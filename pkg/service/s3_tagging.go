@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// ObjectTaggingInput identifies the object, or object version, whose tags are read or removed
+type ObjectTaggingInput struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+// ObjectTags represents the tag set of an object
+type ObjectTags struct {
+	Bucket    string            `json:"bucket"`
+	Key       string            `json:"key"`
+	VersionID string            `json:"versionId,omitempty"`
+	Tags      map[string]string `json:"tags"`
+}
+
+// PutObjectTaggingInput represents input for replacing the tag set of an object
+type PutObjectTaggingInput struct {
+	Bucket    string            `json:"bucket"`
+	Key       string            `json:"key"`
+	VersionID string            `json:"versionId,omitempty"`
+	Tags      map[string]string `json:"tags"`
+}
+
+// TagPrefixInput represents input for tagging every object under a prefix
+type TagPrefixInput struct {
+	Bucket string            `json:"bucket"`
+	Prefix string            `json:"prefix"`
+	Tags   map[string]string `json:"tags"`
+	Merge  bool              `json:"merge,omitempty"` // keep existing tags that are not overwritten
+}
+
+// TagPrefixOutput summarises a prefix tagging key by key
+type TagPrefixOutput struct {
+	Tagged []string      `json:"tagged"`
+	Failed []ObjectError `json:"failed,omitempty"`
+}
+
+// S3ObjectTagger interface for object tagging operations
+type S3ObjectTagger interface {
+	GetObjectTagging(ctx context.Context, input ObjectTaggingInput) (*ObjectTags, error)
+	PutObjectTagging(ctx context.Context, input PutObjectTaggingInput) error
+	DeleteObjectTagging(ctx context.Context, input ObjectTaggingInput) error
+	TagPrefix(ctx context.Context, input TagPrefixInput) (*TagPrefixOutput, error)
+}
+
+// GetObjectTagging returns the tag set of an object
+func (s *AWSS3Service) GetObjectTagging(ctx context.Context, input ObjectTaggingInput) (*ObjectTags, error) {
+	s3Input := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(input.Bucket),
+		Key:    aws.String(input.Key),
+	}
+	if input.VersionID != "" {
+		s3Input.VersionId = aws.String(input.VersionID)
+	}
+
+	result, err := s.client.GetObjectTagging(ctx, s3Input)
+	if err != nil {
+		return nil, convertS3Error("get object tagging", err).(*s3cerrors.S3CError).
+			WithDetails(taggingDetails(input.Bucket, input.Key, input.VersionID))
+	}
+
+	tags := make(map[string]string, len(result.TagSet))
+	for _, tag := range result.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return &ObjectTags{
+		Bucket:    input.Bucket,
+		Key:       input.Key,
+		VersionID: aws.ToString(result.VersionId),
+		Tags:      tags,
+	}, nil
+}
+
+// PutObjectTagging replaces the tag set of an object
+func (s *AWSS3Service) PutObjectTagging(ctx context.Context, input PutObjectTaggingInput) error {
+	s3Input := &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(input.Bucket),
+		Key:     aws.String(input.Key),
+		Tagging: &types.Tagging{TagSet: tagSet(input.Tags)},
+	}
+	if input.VersionID != "" {
+		s3Input.VersionId = aws.String(input.VersionID)
+	}
+
+	if _, err := s.client.PutObjectTagging(ctx, s3Input); err != nil {
+		return convertS3Error("put object tagging", err).(*s3cerrors.S3CError).
+			WithDetails(taggingDetails(input.Bucket, input.Key, input.VersionID))
+	}
+
+	s.logger.Debug("Updated S3 object tags", "bucket", input.Bucket, "key", input.Key, "tagCount", len(input.Tags))
+	return nil
+}
+
+// DeleteObjectTagging removes every tag from an object
+func (s *AWSS3Service) DeleteObjectTagging(ctx context.Context, input ObjectTaggingInput) error {
+	s3Input := &s3.DeleteObjectTaggingInput{
+		Bucket: aws.String(input.Bucket),
+		Key:    aws.String(input.Key),
+	}
+	if input.VersionID != "" {
+		s3Input.VersionId = aws.String(input.VersionID)
+	}
+
+	if _, err := s.client.DeleteObjectTagging(ctx, s3Input); err != nil {
+		return convertS3Error("delete object tagging", err).(*s3cerrors.S3CError).
+			WithDetails(taggingDetails(input.Bucket, input.Key, input.VersionID))
+	}
+
+	s.logger.Debug("Removed S3 object tags", "bucket", input.Bucket, "key", input.Key)
+	return nil
+}
+
+// TagPrefix applies a tag set to every object under a prefix. Tags are set per
+// object, so a failure on one key does not stop the others.
+func (s *AWSS3Service) TagPrefix(ctx context.Context, input TagPrefixInput) (*TagPrefixOutput, error) {
+	prefix := normalizePrefix(input.Prefix)
+	if prefix == "" {
+		return nil, s3cerrors.NewMissingFieldError("prefix")
+	}
+
+	output := &TagPrefixOutput{Tagged: []string{}}
+
+	err := s.walkPrefix(ctx, input.Bucket, prefix, func(keys []string) error {
+		// Folder markers are not real content, tagging them would skew cost reports
+		keys = slices.DeleteFunc(keys, func(key string) bool { return strings.HasSuffix(key, "/") })
+
+		errs := forEachKey(ctx, keys, func(_ int, key string) error {
+			return s.tagObject(ctx, input, key)
+		})
+
+		for i, key := range keys {
+			if errs[i] != nil {
+				output.Failed = append(output.Failed, newObjectError(key, errs[i]))
+				continue
+			}
+			output.Tagged = append(output.Tagged, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Finished prefix tagging",
+		"bucket", input.Bucket,
+		"prefix", prefix,
+		"tagged", len(output.Tagged),
+		"failed", len(output.Failed),
+	)
+	return output, nil
+}
+
+// tagObject sets the prefix tag set on one key, merging with its current tags when asked
+func (s *AWSS3Service) tagObject(ctx context.Context, input TagPrefixInput, key string) error {
+	tags := input.Tags
+	if input.Merge {
+		current, err := s.GetObjectTagging(ctx, ObjectTaggingInput{Bucket: input.Bucket, Key: key})
+		if err != nil {
+			return err
+		}
+		tags = current.Tags
+		maps.Copy(tags, input.Tags)
+	}
+
+	return s.PutObjectTagging(ctx, PutObjectTaggingInput{
+		Bucket: input.Bucket,
+		Key:    key,
+		Tags:   tags,
+	})
+}
+
+// tagSet converts a tag map into an S3 tag set, sorted by key for stable requests
+func tagSet(tags map[string]string) []types.Tag {
+	set := make([]types.Tag, 0, len(tags))
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		set = append(set, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return set
+}
+
+// taggingDetails returns the error details shared by tagging operations
+func taggingDetails(bucket, key, versionID string) map[string]any {
+	return map[string]any{
+		"bucket":    bucket,
+		"key":       key,
+		"versionId": versionID,
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	s3cerrors "github.com/tenkoh/s3c/pkg/errors"
+)
+
+// ListObjectVersionsInput represents input for listing object versions
+type ListObjectVersionsInput struct {
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix,omitempty"`
+	Delimiter       string `json:"delimiter,omitempty"`
+	MaxKeys         int32  `json:"maxKeys,omitempty"`
+	KeyMarker       string `json:"keyMarker,omitempty"`
+	VersionIDMarker string `json:"versionIdMarker,omitempty"`
+}
+
+// ObjectVersion represents one version of an object, or a delete marker
+type ObjectVersion struct {
+	Key            string `json:"key"`
+	VersionID      string `json:"versionId"`
+	IsLatest       bool   `json:"isLatest"`
+	IsDeleteMarker bool   `json:"isDeleteMarker"`
+	Size           int64  `json:"size"`
+	LastModified   string `json:"lastModified"`
+	ETag           string `json:"etag,omitempty"`
+	StorageClass   string `json:"storageClass,omitempty"`
+}
+
+// ListObjectVersionsOutput represents output from listing object versions
+type ListObjectVersionsOutput struct {
+	Versions            []ObjectVersion `json:"versions"`
+	CommonPrefixes      []string        `json:"commonPrefixes"`
+	IsTruncated         bool            `json:"isTruncated"`
+	NextKeyMarker       string          `json:"nextKeyMarker,omitempty"`
+	NextVersionIDMarker string          `json:"nextVersionIdMarker,omitempty"`
+}
+
+// S3VersionManager interface for browsing and recovering object versions
+type S3VersionManager interface {
+	ListObjectVersions(ctx context.Context, input ListObjectVersionsInput) (*ListObjectVersionsOutput, error)
+	RestoreObjectVersion(ctx context.Context, bucket, key, versionID string) (*CopyObjectOutput, error)
+	UndeleteObject(ctx context.Context, bucket, key string) (*ObjectVersion, error)
+}
+
+// ListObjectVersions lists object versions and delete markers, newest first for each key
+func (s *AWSS3Service) ListObjectVersions(ctx context.Context, input ListObjectVersionsInput) (*ListObjectVersionsOutput, error) {
+	s.logger.Debug("Listing S3 object versions",
+		"bucket", input.Bucket,
+		"prefix", input.Prefix,
+		"keyMarker", input.KeyMarker,
+	)
+
+	maxKeys := input.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = 100 // Same default page size as ListObjects
+	}
+
+	s3Input := &s3.ListObjectVersionsInput{
+		Bucket:  aws.String(input.Bucket),
+		MaxKeys: aws.Int32(maxKeys),
+	}
+	if input.Prefix != "" {
+		s3Input.Prefix = aws.String(input.Prefix)
+	}
+	if input.Delimiter != "" {
+		s3Input.Delimiter = aws.String(input.Delimiter)
+	}
+	if input.KeyMarker != "" {
+		s3Input.KeyMarker = aws.String(input.KeyMarker)
+	}
+	if input.VersionIDMarker != "" {
+		s3Input.VersionIdMarker = aws.String(input.VersionIDMarker)
+	}
+
+	result, err := s.client.ListObjectVersions(ctx, s3Input)
+	if err != nil {
+		return nil, convertS3Error("list object versions", err).(*s3cerrors.S3CError).
+			WithDetails(map[string]any{
+				"bucket": input.Bucket,
+				"prefix": input.Prefix,
+			})
+	}
+
+	output := &ListObjectVersionsOutput{
+		Versions:            make([]ObjectVersion, 0, len(result.Versions)+len(result.DeleteMarkers)),
+		CommonPrefixes:      make([]string, 0, len(result.CommonPrefixes)),
+		IsTruncated:         aws.ToBool(result.IsTruncated),
+		NextKeyMarker:       aws.ToString(result.NextKeyMarker),
+		NextVersionIDMarker: aws.ToString(result.NextVersionIdMarker),
+	}
+
+	for _, prefix := range result.CommonPrefixes {
+		output.CommonPrefixes = append(output.CommonPrefixes, aws.ToString(prefix.Prefix))
+	}
+	for _, version := range result.Versions {
+		output.Versions = append(output.Versions, ObjectVersion{
+			Key:          aws.ToString(version.Key),
+			VersionID:    aws.ToString(version.VersionId),
+			IsLatest:     aws.ToBool(version.IsLatest),
+			Size:         aws.ToInt64(version.Size),
+			LastModified: formatTime(version.LastModified),
+			ETag:         aws.ToString(version.ETag),
+			StorageClass: listedStorageClass(string(version.StorageClass)),
+		})
+	}
+	for _, marker := range result.DeleteMarkers {
+		output.Versions = append(output.Versions, ObjectVersion{
+			Key:            aws.ToString(marker.Key),
+			VersionID:      aws.ToString(marker.VersionId),
+			IsLatest:       aws.ToBool(marker.IsLatest),
+			IsDeleteMarker: true,
+			LastModified:   formatTime(marker.LastModified),
+		})
+	}
+
+	// S3 returns versions and delete markers separately, interleave them per key
+	sortVersions(output.Versions)
+
+	return output, nil
+}
+
+// RestoreObjectVersion makes an older version current again by copying it over the latest one.
+// The history is kept: the restored content becomes a new version.
+func (s *AWSS3Service) RestoreObjectVersion(ctx context.Context, bucket, key, versionID string) (*CopyObjectOutput, error) {
+	output, err := s.CopyObject(ctx, CopyObjectInput{
+		SourceBucket:      bucket,
+		SourceKey:         key,
+		SourceVersionID:   versionID,
+		DestinationBucket: bucket,
+		DestinationKey:    key,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Restored object version", "bucket", bucket, "key", key, "versionId", versionID)
+	return output, nil
+}
+
+// UndeleteObject removes the delete marker that hides the latest version of a key
+func (s *AWSS3Service) UndeleteObject(ctx context.Context, bucket, key string) (*ObjectVersion, error) {
+	details := map[string]any{
+		"bucket": bucket,
+		"key":    key,
+	}
+
+	marker, err := s.latestDeleteMarker(ctx, bucket, key)
+	if err != nil {
+		return nil, convertS3Error("undelete object", err).(*s3cerrors.S3CError).WithDetails(details)
+	}
+	if marker == nil {
+		return nil, s3cerrors.NewValidationError(s3cerrors.CodeInvalidInput, "Object is not deleted").
+			WithDetails(details).
+			WithSuggestion("Only keys whose latest version is a delete marker can be undeleted")
+	}
+
+	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(marker.VersionID),
+	})
+	if err != nil {
+		return nil, convertS3Error("undelete object", err).(*s3cerrors.S3CError).WithDetails(details)
+	}
+
+	s.logger.Info("Undeleted object", "bucket", bucket, "key", key, "deleteMarker", marker.VersionID)
+	return marker, nil
+}
+
+// latestDeleteMarker finds the delete marker that is the latest version of key, or nil.
+// Other keys can share the prefix and a key can have many versions, so the listing
+// is paged until it moves past key.
+func (s *AWSS3Service) latestDeleteMarker(ctx context.Context, bucket, key string) (*ObjectVersion, error) {
+	input := &s3.ListObjectVersionsInput{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(key),
+		MaxKeys: aws.Int32(maxDeleteBatchSize),
+	}
+
+	for {
+		result, err := s.client.ListObjectVersions(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range result.DeleteMarkers {
+			if aws.ToString(m.Key) == key && aws.ToBool(m.IsLatest) {
+				return &ObjectVersion{
+					Key:            key,
+					VersionID:      aws.ToString(m.VersionId),
+					IsLatest:       true,
+					IsDeleteMarker: true,
+					LastModified:   formatTime(m.LastModified),
+				}, nil
+			}
+		}
+
+		// Keys are listed in order, so anything after key means it has been fully listed
+		if !aws.ToBool(result.IsTruncated) || aws.ToString(result.NextKeyMarker) > key {
+			return nil, nil
+		}
+		input.KeyMarker = result.NextKeyMarker
+		input.VersionIdMarker = result.NextVersionIdMarker
+	}
+}
+
+// sortVersions orders versions by key, then newest first
+func sortVersions(versions []ObjectVersion) {
+	slices.SortStableFunc(versions, func(a, b ObjectVersion) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		// RFC 3339 timestamps in UTC sort lexically
+		if c := strings.Compare(b.LastModified, a.LastModified); c != 0 {
+			return c
+		}
+		// Within the same second only the IsLatest flag tells versions apart
+		switch {
+		case a.IsLatest && !b.IsLatest:
+			return -1
+		case b.IsLatest && !a.IsLatest:
+			return 1
+		}
+		return 0
+	})
+}
+
+// formatTime formats an optional S3 timestamp as RFC 3339
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
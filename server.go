@@ -90,11 +90,47 @@ func (s *Server) setupRoutes() {
 	s.mux.HandleFunc("POST /api/settings", s.apiHandler.HandleSettings)
 	s.mux.HandleFunc("POST /api/buckets", s.apiHandler.HandleBuckets)
 	s.mux.HandleFunc("POST /api/buckets/create", s.apiHandler.HandleBucketCreate)
+	s.mux.HandleFunc("POST /api/buckets/delete", s.apiHandler.HandleBucketDelete)
+	s.mux.HandleFunc("POST /api/buckets/info", s.apiHandler.HandleBucketInfo)
+	s.mux.HandleFunc("POST /api/buckets/policy", s.apiHandler.HandleBucketPolicy)
+	s.mux.HandleFunc("POST /api/buckets/policy/update", s.apiHandler.HandleBucketPolicyUpdate)
+	s.mux.HandleFunc("POST /api/buckets/policy/delete", s.apiHandler.HandleBucketPolicyDelete)
+	s.mux.HandleFunc("POST /api/buckets/policy/status", s.apiHandler.HandleBucketPolicyStatus)
+	s.mux.HandleFunc("POST /api/buckets/cors", s.apiHandler.HandleBucketCors)
+	s.mux.HandleFunc("POST /api/buckets/cors/update", s.apiHandler.HandleBucketCorsUpdate)
+	s.mux.HandleFunc("POST /api/buckets/cors/delete", s.apiHandler.HandleBucketCorsDelete)
+	s.mux.HandleFunc("POST /api/buckets/lifecycle", s.apiHandler.HandleBucketLifecycle)
+	s.mux.HandleFunc("POST /api/buckets/lifecycle/update", s.apiHandler.HandleBucketLifecycleUpdate)
+	s.mux.HandleFunc("POST /api/buckets/lifecycle/delete", s.apiHandler.HandleBucketLifecycleDelete)
+	s.mux.HandleFunc("POST /api/buckets/lifecycle/preview", s.apiHandler.HandleBucketLifecyclePreview)
+	s.mux.HandleFunc("POST /api/buckets/versioning", s.apiHandler.HandleBucketVersioning)
+	s.mux.HandleFunc("POST /api/buckets/versioning/update", s.apiHandler.HandleBucketVersioningUpdate)
 	s.mux.HandleFunc("POST /api/objects/list", s.apiHandler.HandleObjectsList)
+	s.mux.HandleFunc("POST /api/objects/versions", s.apiHandler.HandleObjectVersionsList)
+	s.mux.HandleFunc("POST /api/objects/versions/download", s.apiHandler.HandleObjectVersionDownload)
+	s.mux.HandleFunc("GET /api/objects/versions/download", s.apiHandler.HandleObjectVersionDownload)
+	s.mux.HandleFunc("POST /api/objects/versions/restore", s.apiHandler.HandleObjectVersionRestore)
+	s.mux.HandleFunc("POST /api/objects/versions/undelete", s.apiHandler.HandleObjectUndelete)
 	s.mux.HandleFunc("POST /api/objects/delete", s.apiHandler.HandleObjectsDelete)
+	s.mux.HandleFunc("POST /api/objects/head", s.apiHandler.HandleObjectsHead)
+	s.mux.HandleFunc("POST /api/objects/presign", s.apiHandler.HandleObjectsPresign)
+	s.mux.HandleFunc("POST /api/objects/tags", s.apiHandler.HandleObjectTags)
+	s.mux.HandleFunc("POST /api/objects/tags/update", s.apiHandler.HandleObjectTagsUpdate)
+	s.mux.HandleFunc("POST /api/objects/tags/delete", s.apiHandler.HandleObjectTagsDelete)
+	s.mux.HandleFunc("POST /api/objects/tags/bulk", s.apiHandler.HandleObjectTagsBulk)
+	s.mux.HandleFunc("POST /api/objects/metadata", s.apiHandler.HandleObjectsMetadata)
+	s.mux.HandleFunc("POST /api/objects/storage-class", s.apiHandler.HandleObjectsStorageClass)
+	s.mux.HandleFunc("POST /api/objects/restore", s.apiHandler.HandleObjectsRestore)
+	s.mux.HandleFunc("POST /api/objects/retention", s.apiHandler.HandleObjectRetention)
+	s.mux.HandleFunc("POST /api/objects/retention/update", s.apiHandler.HandleObjectRetentionUpdate)
+	s.mux.HandleFunc("POST /api/objects/legal-hold", s.apiHandler.HandleObjectLegalHold)
+	s.mux.HandleFunc("POST /api/objects/legal-hold/update", s.apiHandler.HandleObjectLegalHoldUpdate)
 	s.mux.HandleFunc("POST /api/objects/upload", s.apiHandler.HandleObjectsUpload)
 	s.mux.HandleFunc("POST /api/objects/download", s.apiHandler.HandleObjectsDownload)
+	s.mux.HandleFunc("GET /api/objects/download", s.apiHandler.HandleObjectsDownload) // single object, supports Range
 	s.mux.HandleFunc("POST /api/objects/folder/create", s.apiHandler.HandleFolderCreate)
+	s.mux.HandleFunc("POST /api/objects/copy", s.apiHandler.HandleObjectsCopy)
+	s.mux.HandleFunc("POST /api/objects/move", s.apiHandler.HandleObjectsMove)
 	s.mux.HandleFunc("POST /api/shutdown", s.apiHandler.HandleShutdown)
 
 	// Serve static files and SPA routing
@@ -109,7 +145,44 @@ func (s *Server) Start() error {
 		"writeTimeout", "15s",
 	)
 
-	// Wrap mux with HTTP logging middleware
+	s.mu.Lock()
+	s.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", s.port),
+		Handler:      s.handler(),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("HTTP server listening", "address", s.httpServer.Addr)
+
+	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		s.logger.Error("HTTP server failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// rawWriterKey is the request context key under which handler stores the
+// connection's own ResponseWriter, before the logging middleware wraps it.
+type rawWriterKey struct{}
+
+// deadlineWriter writes through the logging middleware's writer but unwraps to the
+// connection's writer, so http.ResponseController can reach SetReadDeadline and
+// SetWriteDeadline. slog-http's writer does not implement Unwrap itself.
+type deadlineWriter struct {
+	http.ResponseWriter
+	raw http.ResponseWriter
+}
+
+// Unwrap returns the connection's writer for http.ResponseController
+func (w *deadlineWriter) Unwrap() http.ResponseWriter {
+	return w.raw
+}
+
+// handler builds the full handler chain: mux wrapped with HTTP logging middleware
+func (s *Server) handler() http.Handler {
 	httpLogger := logger.WithComponent(s.logger, "http")
 
 	// Configure slog-http middleware
@@ -131,23 +204,16 @@ func (s *Server) Start() error {
 
 	middleware := sloghttp.NewWithConfig(httpLogger, config)
 
-	s.mu.Lock()
-	s.httpServer = &http.Server{
-		Addr:         fmt.Sprintf(":%d", s.port),
-		Handler:      middleware(s.mux),
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-	}
-	s.mu.Unlock()
-
-	s.logger.Info("HTTP server listening", "address", s.httpServer.Addr)
+	logged := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if raw, ok := r.Context().Value(rawWriterKey{}).(http.ResponseWriter); ok {
+			w = &deadlineWriter{ResponseWriter: w, raw: raw}
+		}
+		s.mux.ServeHTTP(w, r)
+	}))
 
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		s.logger.Error("HTTP server failed", "error", err)
-		return err
-	}
-
-	return nil
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logged.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), rawWriterKey{}, w)))
+	})
 }
 
 // Shutdown gracefully shuts down the server
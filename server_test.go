@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServerHandlerAllowsDeadlineExtension(t *testing.T) {
+	s := NewTestServer(0, nil, nil)
+
+	// Probe route mirroring what long-running handlers do: clear the deadlines,
+	// then respond after the server's write timeout has passed.
+	s.mux.HandleFunc("POST /test/slow", func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		if err := rc.SetReadDeadline(time.Time{}); err != nil {
+			t.Errorf("SetReadDeadline through handler chain: %v", err)
+		}
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			t.Errorf("SetWriteDeadline through handler chain: %v", err)
+		}
+		time.Sleep(300 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+	})
+
+	srv := httptest.NewUnstartedServer(s.handler())
+	srv.Config.ReadTimeout = 100 * time.Millisecond
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/test/slow", "text/plain", nil)
+	if err != nil {
+		t.Fatalf("request through handler chain failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading response body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != "done" {
+		t.Errorf("got status %d body %q, want 200 %q", resp.StatusCode, body, "done")
+	}
+}